@@ -1,6 +1,7 @@
 package collectors
 
 import (
+	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -22,135 +23,160 @@ type AdminStatsCollector struct {
 	usersMetric                     prometheus.Gauge
 	scrapesTotalMetric              prometheus.Counter
 	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
 	lastScrapeErrorMetric           prometheus.Gauge
 	lastScrapeTimestampMetric       prometheus.Gauge
 	lastScrapeDurationSecondsMetric prometheus.Gauge
 }
 
-func NewAdminStatsCollector(grafanaClient grafana.Client) *AdminStatsCollector {
+func NewAdminStatsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels) *AdminStatsCollector {
 	alertsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "alerts",
-			Help:      "Number of Grafana Alerts.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "alerts",
+			Help:        "Number of Grafana Alerts.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	dashboardsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "dashboards",
-			Help:      "Number of Grafana Dashboards.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "dashboards",
+			Help:        "Number of Grafana Dashboards.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	datasourcesMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "datasources",
-			Help:      "Number of Grafana Datasources.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "datasources",
+			Help:        "Number of Grafana Datasources.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	orgsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "orgs",
-			Help:      "Number of Grafana Orgs.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "orgs",
+			Help:        "Number of Grafana Orgs.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	playlistsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "playlists",
-			Help:      "Number of Grafana Playlists.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "playlists",
+			Help:        "Number of Grafana Playlists.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	dbSnapshotsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "db_snapshots",
-			Help:      "Number of Grafana Snapshots.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "db_snapshots",
+			Help:        "Number of Grafana Snapshots.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	starredDBMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "starred_db",
-			Help:      "Number of Grafana Dashboards Starred.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "starred_db",
+			Help:        "Number of Grafana Dashboards Starred.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	dbTagsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "db_tags",
-			Help:      "Number of Grafana Tags.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "db_tags",
+			Help:        "Number of Grafana Tags.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	usersMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "users",
-			Help:      "Number of Grafana Users.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "users",
+			Help:        "Number of Grafana Users.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	scrapesTotalMetric := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "scrapes_total",
-			Help:      "Total number of Grafana Admin Stats scrapes.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Admin Stats scrapes.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	scrapeErrorsTotalMetric := prometheus.NewCounter(
 		prometheus.CounterOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "scrape_errors_total",
-			Help:      "Total number of Grafana Admin Stats scrape errors.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Admin Stats scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Admin Stats scrape timeouts.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	lastScrapeErrorMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "last_scrape_error",
-			Help:      "Whether the last metrics scrape from Grafana Admin Stats resulted in an error (1 for error, 0 for success).",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Admin Stats resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
 		},
 	)
 
 	lastScrapeTimestampMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "last_scrape_timestamp",
-			Help:      "Number of seconds since 1970 since last metrics scrape from Grafana Admin Stats.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Admin Stats.",
+			ConstLabels: constLabels,
 		},
 	)
 
 	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
 		prometheus.GaugeOpts{
-			Namespace: "grafana",
-			Subsystem: "admin_stats",
-			Name:      "last_scrape_duration_seconds",
-			Help:      "Duration of the last metrics scrape from Grafana Admin Stats.",
+			Namespace:   "grafana",
+			Subsystem:   "admin_stats",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Admin Stats.",
+			ConstLabels: constLabels,
 		},
 	)
 
@@ -167,6 +193,7 @@ func NewAdminStatsCollector(grafanaClient grafana.Client) *AdminStatsCollector {
 		usersMetric:                     usersMetric,
 		scrapesTotalMetric:              scrapesTotalMetric,
 		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
 		lastScrapeErrorMetric:           lastScrapeErrorMetric,
 		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
 		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
@@ -187,21 +214,32 @@ func (c *AdminStatsCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.usersMetric.Describe(ch)
 	c.scrapesTotalMetric.Describe(ch)
 	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
 	c.lastScrapeErrorMetric.Describe(ch)
 	c.lastScrapeTimestampMetric.Describe(ch)
 	c.lastScrapeDurationSecondsMetric.Describe(ch)
 }
 
 func (c *AdminStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *AdminStatsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
 	var begun = time.Now()
 
 	errorMetric := float64(0)
-	if err := c.reportAdminStatsMetrics(ch); err != nil {
+	if err := c.reportAdminStatsMetrics(ctx, ch); err != nil {
 		errorMetric = float64(1)
-		c.scrapeErrorsTotalMetric.Inc()
-		log.Errorf("Error while getting Grafana Admin Stats metrics: %s", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Admin Stats metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Admin Stats metrics: %s", err)
+		}
 	}
 	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
 
 	c.scrapesTotalMetric.Inc()
 	c.scrapesTotalMetric.Collect(ch)
@@ -216,8 +254,8 @@ func (c *AdminStatsCollector) Collect(ch chan<- prometheus.Metric) {
 	c.lastScrapeDurationSecondsMetric.Collect(ch)
 }
 
-func (c *AdminStatsCollector) reportAdminStatsMetrics(ch chan<- prometheus.Metric) error {
-	adminStats, err := c.grafanaClient.GetAdminStats()
+func (c *AdminStatsCollector) reportAdminStatsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	adminStats, err := c.grafanaClient.GetAdminStats(ctx)
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,10 @@
 package collectors_test
 
 import (
+	"context"
 	"errors"
 	"flag"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -22,6 +24,7 @@ func init() {
 var _ = Describe("AdminStatsCollectors", func() {
 	var (
 		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
 
 		alertsMetric                    prometheus.Gauge
 		dashboardsMetric                prometheus.Gauge
@@ -34,6 +37,7 @@ var _ = Describe("AdminStatsCollectors", func() {
 		usersMetric                     prometheus.Gauge
 		scrapesTotalMetric              prometheus.Counter
 		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
 		lastScrapeErrorMetric           prometheus.Gauge
 		lastScrapeTimestampMetric       prometheus.Gauge
 		lastScrapeDurationSecondsMetric prometheus.Gauge
@@ -53,138 +57,163 @@ var _ = Describe("AdminStatsCollectors", func() {
 
 	BeforeEach(func() {
 		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
 
 		alertsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "alerts",
-				Help:      "Number of Grafana Alerts.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "alerts",
+				Help:        "Number of Grafana Alerts.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		dashboardsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "dashboards",
-				Help:      "Number of Grafana Dashboards.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "dashboards",
+				Help:        "Number of Grafana Dashboards.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		datasourcesMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "datasources",
-				Help:      "Number of Grafana Datasources.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "datasources",
+				Help:        "Number of Grafana Datasources.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		orgsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "orgs",
-				Help:      "Number of Grafana Orgs.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "orgs",
+				Help:        "Number of Grafana Orgs.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		playlistsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "playlists",
-				Help:      "Number of Grafana Playlists.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "playlists",
+				Help:        "Number of Grafana Playlists.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		dbSnapshotsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "db_snapshots",
-				Help:      "Number of Grafana Snapshots.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "db_snapshots",
+				Help:        "Number of Grafana Snapshots.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		starredDBMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "starred_db",
-				Help:      "Number of Grafana Dashboards Starred.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "starred_db",
+				Help:        "Number of Grafana Dashboards Starred.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		dbTagsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "db_tags",
-				Help:      "Number of Grafana Tags.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "db_tags",
+				Help:        "Number of Grafana Tags.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		usersMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "users",
-				Help:      "Number of Grafana Users.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "users",
+				Help:        "Number of Grafana Users.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		scrapesTotalMetric = prometheus.NewCounter(
 			prometheus.CounterOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "scrapes_total",
-				Help:      "Total number of Grafana Admin Stats scrapes.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Admin Stats scrapes.",
+				ConstLabels: constLabels,
 			},
 		)
 		scrapesTotalMetric.Inc()
 
 		scrapeErrorsTotalMetric = prometheus.NewCounter(
 			prometheus.CounterOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "scrape_errors_total",
-				Help:      "Total number of Grafana Admin Stats scrape errors.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Admin Stats scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Admin Stats scrape timeouts.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		lastScrapeErrorMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "last_scrape_error",
-				Help:      "Whether the last metrics scrape from Grafana Admin Stats resulted in an error (1 for error, 0 for success).",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Admin Stats resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
 			},
 		)
 		lastScrapeErrorMetric.Set(0)
 
 		lastScrapeTimestampMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "last_scrape_timestamp",
-				Help:      "Number of seconds since 1970 since last metrics scrape from Grafana Admin Stats.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Admin Stats.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "admin_stats",
-				Name:      "last_scrape_duration_seconds",
-				Help:      "Duration of the last metrics scrape from Grafana Admin Stats.",
+				Namespace:   "grafana",
+				Subsystem:   "admin_stats",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Admin Stats.",
+				ConstLabels: constLabels,
 			},
 		)
 	})
 
 	JustBeforeEach(func() {
-		adminStatsCollector = NewAdminStatsCollector(grafanaClient)
+		adminStatsCollector = NewAdminStatsCollector(grafanaClient, constLabels)
 	})
 
 	Describe("Describe", func() {
@@ -244,6 +273,10 @@ var _ = Describe("AdminStatsCollectors", func() {
 			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
 		})
 
+		It("returns a grafana_admin_stats_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
 		It("returns a grafana_admin_stats_last_scrape_error metric description", func() {
 			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
 		})
@@ -261,6 +294,7 @@ var _ = Describe("AdminStatsCollectors", func() {
 		var (
 			adminStatsResponse grafana.AdminStats
 
+			ctx     context.Context
 			metrics chan prometheus.Metric
 		)
 
@@ -278,11 +312,12 @@ var _ = Describe("AdminStatsCollectors", func() {
 			}
 			grafanaClient.GetAdminStatsReturns(adminStatsResponse, nil)
 
+			ctx = context.Background()
 			metrics = make(chan prometheus.Metric)
 		})
 
 		JustBeforeEach(func() {
-			go adminStatsCollector.Collect(metrics)
+			go adminStatsCollector.CollectContext(ctx, metrics)
 		})
 
 		It("returns a grafana_admin_stats_alerts metric", func() {
@@ -349,5 +384,31 @@ var _ = Describe("AdminStatsCollectors", func() {
 				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
 			})
 		})
+
+		Context("when the scrape times out", func() {
+			var (
+				cancel context.CancelFunc
+			)
+
+			BeforeEach(func() {
+				ctx, cancel = context.WithDeadline(context.Background(), time.Now())
+				grafanaClient.GetAdminStatsReturns(adminStatsResponse, errors.New("error"))
+
+				scrapeTimeoutsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("returns a grafana_admin_stats_scrape_timeouts_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeTimeoutsTotalMetric)))
+			})
+
+			It("returns a grafana_admin_stats_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
 	})
 })
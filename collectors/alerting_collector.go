@@ -0,0 +1,341 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// alertingRuleStates, alertingRuleHealths and silenceStates are always
+// reported, even when no rule or silence is in the state.
+var (
+	alertingRuleStates  = []string{"inactive", "pending", "firing"}
+	alertingRuleHealths = []string{"ok", "error", "nodata"}
+	silenceStates       = []string{"active", "pending", "expired"}
+)
+
+// noSeverity is the severity of the alert instances without a `severity`
+// label.
+const noSeverity = "none"
+
+type alertingRulesKey struct {
+	folderUID   string
+	folderTitle string
+	group       string
+	value       string
+}
+
+type alertingAlertsKey struct {
+	state    string
+	severity string
+}
+
+type AlertingCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	rulesDesc                       *prometheus.Desc
+	rulesHealthDesc                 *prometheus.Desc
+	ruleLastErrorDesc               *prometheus.Desc
+	alertsDesc                      *prometheus.Desc
+	silencesDesc                    *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewAlertingCollector returns a collector of the Grafana Alerting (unified
+// alerting) rules, alert instances and silences of the orgs matching the org
+// filter.
+func NewAlertingCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter) *AlertingCollector {
+	rulesDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alerting", "rules"),
+		"Number of Grafana Alerting Rules in the state.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "state"},
+		constLabels,
+	)
+
+	rulesHealthDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alerting", "rules_health"),
+		"Number of Grafana Alerting Rules with the evaluation health.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "health"},
+		constLabels,
+	)
+
+	ruleLastErrorDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alerting", "rule_last_error"),
+		"Whether the last evaluation of the Grafana Alerting Rule failed (1 for yes, 0 for no).",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "rule"},
+		constLabels,
+	)
+
+	alertsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alerting", "alerts"),
+		"Number of Grafana Alertmanager alert instances in the state.",
+		[]string{"org_id", "org_name", "state", "severity"},
+		constLabels,
+	)
+
+	silencesDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alerting", "silences"),
+		"Number of Grafana Alertmanager silences in the state.",
+		[]string{"org_id", "org_name", "state"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "alerting",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Alerting scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "alerting",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Alerting scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "alerting",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Alerting scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "alerting",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Alerting resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "alerting",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Alerting.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "alerting",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Alerting.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	alertingCollector := &AlertingCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		rulesDesc:                       rulesDesc,
+		rulesHealthDesc:                 rulesHealthDesc,
+		ruleLastErrorDesc:               ruleLastErrorDesc,
+		alertsDesc:                      alertsDesc,
+		silencesDesc:                    silencesDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return alertingCollector
+}
+
+func (c *AlertingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.rulesDesc
+	ch <- c.rulesHealthDesc
+	ch <- c.ruleLastErrorDesc
+	ch <- c.alertsDesc
+	ch <- c.silencesDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *AlertingCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *AlertingCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportAlertingMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Alerting metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Alerting metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *AlertingCollector) reportAlertingMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "alerting", func(org grafana.Org) error {
+		orgMetrics, err := c.orgAlertingMetrics(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range orgMetrics {
+			ch <- metric
+		}
+
+		return nil
+	})
+}
+
+// orgAlertingMetrics returns the metrics of the rules, the alert instances
+// and the silences of the org. Grafana Alerting is not available before
+// Grafana 8 nor when legacy alerting is enabled, so an endpoint not found is
+// skipped.
+func (c *AlertingCollector) orgAlertingMetrics(ctx context.Context, org grafana.Org) ([]prometheus.Metric, error) {
+	var metrics []prometheus.Metric
+
+	orgID := strconv.FormatInt(org.ID, 10)
+
+	ruleGroups, err := c.grafanaClient.GetRuleGroups(ctx, org.ID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	// The folder titles are not unique, so the rules are keyed by the folder
+	// uid. Grafana versions without the folder uid in the rule groups key
+	// them by the folder title only, so the rules of the folders with the same
+	// title are merged.
+	rules := map[alertingRulesKey]int{}
+	rulesHealth := map[alertingRulesKey]int{}
+	rulesLastError := map[alertingRulesKey]float64{}
+	for _, ruleGroup := range ruleGroups {
+		key := alertingRulesKey{folderUID: ruleGroup.FolderUID, folderTitle: ruleGroup.File, group: ruleGroup.Name}
+		// Add rather than set, so the rules already counted for a merged
+		// folder are kept.
+		for _, state := range alertingRuleStates {
+			key.value = state
+			rules[key] += 0
+		}
+		for _, health := range alertingRuleHealths {
+			key.value = health
+			rulesHealth[key] += 0
+		}
+
+		for _, rule := range ruleGroup.Rules {
+			// Recording rules have no state.
+			if rule.State != "" {
+				key.value = rule.State
+				rules[key]++
+			}
+
+			key.value = rule.Health
+			rulesHealth[key]++
+
+			// Keep the error text out of the labels, as it changes from an
+			// evaluation to another and would churn the series.
+			key.value = rule.Name
+			rulesLastError[key] += 0
+			if rule.LastError != "" {
+				log.Warnf("Grafana Alerting rule `%s` of group `%s` of org `%s` failed its last evaluation: %s", rule.Name, ruleGroup.Name, org.Name, rule.LastError)
+				rulesLastError[key] = 1
+			}
+		}
+	}
+
+	for key, count := range rules {
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.rulesDesc, prometheus.GaugeValue, float64(count), orgID, org.Name, key.folderUID, key.folderTitle, key.group, key.value))
+	}
+
+	for key, count := range rulesHealth {
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.rulesHealthDesc, prometheus.GaugeValue, float64(count), orgID, org.Name, key.folderUID, key.folderTitle, key.group, key.value))
+	}
+
+	for key, lastError := range rulesLastError {
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.ruleLastErrorDesc, prometheus.GaugeValue, lastError, orgID, org.Name, key.folderUID, key.folderTitle, key.group, key.value))
+	}
+
+	alerts, err := c.grafanaClient.GetAlertmanagerAlerts(ctx, org.ID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	alertInstances := map[alertingAlertsKey]int{}
+	for _, alert := range alerts {
+		severity := alert.Labels["severity"]
+		if severity == "" {
+			severity = noSeverity
+		}
+		alertInstances[alertingAlertsKey{state: alert.Status.State, severity: severity}]++
+	}
+
+	for key, count := range alertInstances {
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.alertsDesc, prometheus.GaugeValue, float64(count), orgID, org.Name, key.state, key.severity))
+	}
+
+	silences, err := c.grafanaClient.GetSilences(ctx, org.ID)
+	if err != nil {
+		if isNotFound(err) {
+			return metrics, nil
+		}
+		return nil, err
+	}
+
+	silencesByState := map[string]int{}
+	for _, state := range silenceStates {
+		silencesByState[state] = 0
+	}
+	for _, silence := range silences {
+		silencesByState[silence.Status.State]++
+	}
+
+	for state, count := range silencesByState {
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.silencesDesc, prometheus.GaugeValue, float64(count), orgID, org.Name, state))
+	}
+
+	return metrics, nil
+}
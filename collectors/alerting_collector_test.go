@@ -0,0 +1,439 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("AlertingCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+
+		rulesDesc                       *prometheus.Desc
+		rulesHealthDesc                 *prometheus.Desc
+		ruleLastErrorDesc               *prometheus.Desc
+		alertsDesc                      *prometheus.Desc
+		silencesDesc                    *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		alertingCollector *AlertingCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+
+		rulesDesc = prometheus.NewDesc(
+			"grafana_alerting_rules",
+			"Number of Grafana Alerting Rules in the state.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "state"},
+			constLabels,
+		)
+
+		rulesHealthDesc = prometheus.NewDesc(
+			"grafana_alerting_rules_health",
+			"Number of Grafana Alerting Rules with the evaluation health.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "health"},
+			constLabels,
+		)
+
+		ruleLastErrorDesc = prometheus.NewDesc(
+			"grafana_alerting_rule_last_error",
+			"Whether the last evaluation of the Grafana Alerting Rule failed (1 for yes, 0 for no).",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "rule"},
+			constLabels,
+		)
+
+		alertsDesc = prometheus.NewDesc(
+			"grafana_alerting_alerts",
+			"Number of Grafana Alertmanager alert instances in the state.",
+			[]string{"org_id", "org_name", "state", "severity"},
+			constLabels,
+		)
+
+		silencesDesc = prometheus.NewDesc(
+			"grafana_alerting_silences",
+			"Number of Grafana Alertmanager silences in the state.",
+			[]string{"org_id", "org_name", "state"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "alerting",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Alerting scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "alerting",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Alerting scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "alerting",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Alerting scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "alerting",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Alerting resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "alerting",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Alerting.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "alerting",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Alerting.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		alertingCollector = NewAlertingCollector(grafanaClient, constLabels, orgFilter)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go alertingCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_alerting_rules metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(rulesDesc)))
+		})
+
+		It("returns a grafana_alerting_rules_health metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(rulesHealthDesc)))
+		})
+
+		It("returns a grafana_alerting_rule_last_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(ruleLastErrorDesc)))
+		})
+
+		It("returns a grafana_alerting_alerts metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(alertsDesc)))
+		})
+
+		It("returns a grafana_alerting_silences metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(silencesDesc)))
+		})
+
+		It("returns a grafana_alerting_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_alerting_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_alerting_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_alerting_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_alerting_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_alerting_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			metricDesc  = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+			constMetric = func(desc *prometheus.Desc, value float64, labelValues ...string) OmegaMatcher {
+				return And(
+					WithTransform(metricDesc, Equal(desc)),
+					PrometheusMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)),
+				)
+			}
+		)
+
+		BeforeEach(func() {
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetRuleGroupsStub = func(ctx context.Context, orgID int64) ([]grafana.RuleGroup, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "rule groups", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.RuleGroup{
+					{
+						Name:      "fake-group",
+						File:      "Fake Folder",
+						FolderUID: "fake-folder",
+						Rules: []grafana.Rule{
+							{Name: "Fake Firing Rule", State: "firing", Health: "ok", Type: "alerting"},
+							{Name: "Fake Broken Rule", State: "inactive", Health: "error", LastError: "fake-error", Type: "alerting"},
+							{Name: "fake:recording_rule", Health: "ok", Type: "recording"},
+						},
+					},
+				}, nil
+			}
+			grafanaClient.GetAlertmanagerAlertsStub = func(ctx context.Context, orgID int64) ([]grafana.AlertmanagerAlert, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "alertmanager alerts", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.AlertmanagerAlert{
+					{Labels: map[string]string{"alertname": "Fake Firing Rule", "severity": "critical"}, Status: grafana.AlertStatus{State: "active"}},
+					{Labels: map[string]string{"alertname": "Fake Firing Rule", "severity": "critical"}, Status: grafana.AlertStatus{State: "active"}},
+					{Labels: map[string]string{"alertname": "Fake Firing Rule"}, Status: grafana.AlertStatus{State: "suppressed"}},
+				}, nil
+			}
+			grafanaClient.GetSilencesStub = func(ctx context.Context, orgID int64) ([]grafana.Silence, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "silences", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.Silence{
+					{ID: "fake-silence-1", Status: grafana.SilenceStatus{State: "active"}},
+					{ID: "fake-silence-2", Status: grafana.SilenceStatus{State: "expired"}},
+					{ID: "fake-silence-3", Status: grafana.SilenceStatus{State: "expired"}},
+				}, nil
+			}
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go alertingCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_alerting_rules metric for the firing rules of a group", func() {
+			Eventually(metrics).Should(Receive(constMetric(rulesDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-group", "firing")))
+		})
+
+		It("returns a grafana_alerting_rules metric for the states without rules", func() {
+			Eventually(metrics).Should(Receive(constMetric(rulesDesc, 0, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-group", "pending")))
+		})
+
+		It("returns a grafana_alerting_rules_health metric including the recording rules", func() {
+			Eventually(metrics).Should(Receive(constMetric(rulesHealthDesc, 2, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-group", "ok")))
+		})
+
+		It("returns a grafana_alerting_rules_health metric for the rules with an error", func() {
+			Eventually(metrics).Should(Receive(constMetric(rulesHealthDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-group", "error")))
+		})
+
+		It("returns a grafana_alerting_rule_last_error metric for the rules with an error", func() {
+			Eventually(metrics).Should(Receive(constMetric(ruleLastErrorDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-group", "Fake Broken Rule")))
+		})
+
+		It("returns a grafana_alerting_rule_last_error metric for the rules without error", func() {
+			Eventually(metrics).Should(Receive(constMetric(ruleLastErrorDesc, 0, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-group", "Fake Firing Rule")))
+		})
+
+		Context("when folders have the same title", func() {
+			BeforeEach(func() {
+				grafanaClient.GetRuleGroupsStub = nil
+				grafanaClient.GetRuleGroupsReturns([]grafana.RuleGroup{
+					{
+						Name:      "fake-group",
+						File:      "Fake Folder",
+						FolderUID: "fake-folder",
+						Rules:     []grafana.Rule{{Name: "Fake Rule", State: "inactive", Health: "error", LastError: "fake-error", Type: "alerting"}},
+					},
+					{
+						Name:      "fake-group",
+						File:      "Fake Folder",
+						FolderUID: "fake-other-folder",
+						Rules:     []grafana.Rule{{Name: "Fake Rule", State: "firing", Health: "ok", Type: "alerting"}},
+					},
+				}, nil)
+			})
+
+			It("returns a grafana_alerting_rule_last_error metric for the rules of every folder", func() {
+				Eventually(metrics).Should(Receive(constMetric(ruleLastErrorDesc, 0, "1", "Main Org.", "fake-other-folder", "Fake Folder", "fake-group", "Fake Rule")))
+			})
+
+			It("returns a grafana_alerting_rules metric for the rules of every folder", func() {
+				Eventually(metrics).Should(Receive(constMetric(rulesDesc, 1, "1", "Main Org.", "fake-other-folder", "Fake Folder", "fake-group", "firing")))
+			})
+
+			It("can be gathered", func() {
+				registry := prometheus.NewRegistry()
+				registry.MustRegister(alertingCollector)
+				_, err := registry.Gather()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("when Grafana does not return the folder uid", func() {
+				BeforeEach(func() {
+					grafanaClient.GetRuleGroupsReturns([]grafana.RuleGroup{
+						{
+							Name:  "fake-group",
+							File:  "Fake Folder",
+							Rules: []grafana.Rule{{Name: "Fake Rule", State: "inactive", Health: "error", LastError: "fake-error", Type: "alerting"}},
+						},
+						{
+							Name:  "fake-group",
+							File:  "Fake Folder",
+							Rules: []grafana.Rule{{Name: "Fake Rule", State: "firing", Health: "ok", Type: "alerting"}},
+						},
+					}, nil)
+				})
+
+				It("merges the grafana_alerting_rule_last_error metrics of the folders", func() {
+					Eventually(metrics).Should(Receive(constMetric(ruleLastErrorDesc, 1, "1", "Main Org.", "", "Fake Folder", "fake-group", "Fake Rule")))
+				})
+
+				It("merges the grafana_alerting_rules metrics of the folders", func() {
+					Eventually(metrics).Should(Receive(constMetric(rulesDesc, 1, "1", "Main Org.", "", "Fake Folder", "fake-group", "inactive")))
+				})
+
+				It("can be gathered", func() {
+					registry := prometheus.NewRegistry()
+					registry.MustRegister(alertingCollector)
+					_, err := registry.Gather()
+					Expect(err).ToNot(HaveOccurred())
+				})
+			})
+		})
+
+		It("returns a grafana_alerting_alerts metric by severity", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertsDesc, 2, "1", "Main Org.", "active", "critical")))
+		})
+
+		It("returns a grafana_alerting_alerts metric for the alerts without severity", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertsDesc, 1, "1", "Main Org.", "suppressed", "none")))
+		})
+
+		It("returns a grafana_alerting_silences metric for the expired silences", func() {
+			Eventually(metrics).Should(Receive(constMetric(silencesDesc, 2, "1", "Main Org.", "expired")))
+		})
+
+		It("returns a grafana_alerting_silences metric for the states without silences", func() {
+			Eventually(metrics).Should(Receive(constMetric(silencesDesc, 0, "1", "Main Org.", "pending")))
+		})
+
+		It("does not return metrics for the orgs without Grafana Alerting", func() {
+			Consistently(metrics).ShouldNot(Receive(constMetric(silencesDesc, 0, "2", "fake-org", "active")))
+		})
+
+		It("returns a grafana_alerting_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_alerting_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_alerting_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetRuleGroupsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the silences of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetSilencesReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("does not return the metrics of the org", func() {
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(rulesDesc))))
+			})
+
+			It("returns a grafana_alerting_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_alerting_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_alerting_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_alerting_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
@@ -0,0 +1,320 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+const (
+	apiKeyKind              = "api_key"
+	serviceAccountTokenKind = "service_account_token"
+)
+
+// apiKeysExpirations counts the API keys or service account tokens of an org
+// by expiration.
+type apiKeysExpirations struct {
+	expiring    int
+	expired     int
+	nonExpiring int
+}
+
+type APIKeysCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	expiryWindow                    time.Duration
+	apiKeyExpiryDesc                *prometheus.Desc
+	serviceAccountTokenExpiryDesc   *prometheus.Desc
+	expiringDesc                    *prometheus.Desc
+	expiredDesc                     *prometheus.Desc
+	nonExpiringDesc                 *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewAPIKeysCollector returns a collector of the expiration of the API keys
+// and of the service account tokens of the orgs matching the org filter. The
+// keys and tokens expiring within the expiry window are counted as expiring.
+func NewAPIKeysCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, expiryWindow time.Duration) *APIKeysCollector {
+	apiKeyExpiryDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "api_key", "expiry_timestamp_seconds"),
+		"Number of seconds since 1970 until the expiration of the Grafana API Key.",
+		[]string{"org_id", "org_name", "name", "role"},
+		constLabels,
+	)
+
+	serviceAccountTokenExpiryDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "service_account_token", "expiry_timestamp_seconds"),
+		"Number of seconds since 1970 until the expiration of the Grafana Service Account Token.",
+		[]string{"org_id", "org_name", "service_account", "name", "role"},
+		constLabels,
+	)
+
+	expiringDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "api_keys", "expiring"),
+		"Number of Grafana API Keys or Service Account Tokens expiring within the expiry window.",
+		[]string{"org_id", "org_name", "kind"},
+		constLabels,
+	)
+
+	expiredDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "api_keys", "expired"),
+		"Number of expired Grafana API Keys or Service Account Tokens.",
+		[]string{"org_id", "org_name", "kind"},
+		constLabels,
+	)
+
+	nonExpiringDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "api_keys", "non_expiring"),
+		"Number of Grafana API Keys or Service Account Tokens that never expire.",
+		[]string{"org_id", "org_name", "kind"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "api_keys",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana API Keys scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "api_keys",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana API Keys scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "api_keys",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana API Keys scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "api_keys",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana API Keys resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "api_keys",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana API Keys.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "api_keys",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana API Keys.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	apiKeysCollector := &APIKeysCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		expiryWindow:                    expiryWindow,
+		apiKeyExpiryDesc:                apiKeyExpiryDesc,
+		serviceAccountTokenExpiryDesc:   serviceAccountTokenExpiryDesc,
+		expiringDesc:                    expiringDesc,
+		expiredDesc:                     expiredDesc,
+		nonExpiringDesc:                 nonExpiringDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return apiKeysCollector
+}
+
+func (c *APIKeysCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.apiKeyExpiryDesc
+	ch <- c.serviceAccountTokenExpiryDesc
+	ch <- c.expiringDesc
+	ch <- c.expiredDesc
+	ch <- c.nonExpiringDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *APIKeysCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *APIKeysCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportAPIKeysMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana API Keys metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana API Keys metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *APIKeysCollector) reportAPIKeysMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "api keys", func(org grafana.Org) error {
+		orgMetrics, err := c.orgAPIKeysMetrics(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range orgMetrics {
+			ch <- metric
+		}
+
+		return nil
+	})
+}
+
+// orgAPIKeysMetrics returns the metrics of the API keys and of the service
+// account tokens of the org. API keys were replaced by service accounts in
+// Grafana 9, and removed in Grafana 12, so an endpoint that is not available
+// in the Grafana version is skipped. The tokens of the disabled service
+// accounts, which cannot be used, are left out.
+func (c *APIKeysCollector) orgAPIKeysMetrics(ctx context.Context, org grafana.Org) ([]prometheus.Metric, error) {
+	var metrics []prometheus.Metric
+
+	now := time.Now()
+	orgID := strconv.FormatInt(org.ID, 10)
+
+	apiKeys, err := c.grafanaClient.GetAPIKeys(ctx, org.ID)
+	if err == nil {
+		var expirations apiKeysExpirations
+		for _, apiKey := range apiKeys {
+			c.countExpiration(&expirations, apiKey.Expiration, now)
+			if apiKey.Expiration != nil {
+				metrics = append(metrics, prometheus.MustNewConstMetric(
+					c.apiKeyExpiryDesc,
+					prometheus.GaugeValue,
+					float64(apiKey.Expiration.Unix()),
+					orgID,
+					org.Name,
+					apiKey.Name,
+					apiKey.Role,
+				))
+			}
+		}
+		metrics = append(metrics, c.expirationsMetrics(expirations, orgID, org.Name, apiKeyKind)...)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	serviceAccounts, err := c.grafanaClient.GetServiceAccounts(ctx, org.ID)
+	if err != nil {
+		if isNotFound(err) {
+			return metrics, nil
+		}
+		return nil, err
+	}
+
+	var expirations apiKeysExpirations
+	for _, serviceAccount := range serviceAccounts {
+		if serviceAccount.IsDisabled || serviceAccount.Tokens == 0 {
+			continue
+		}
+
+		tokens, err := c.grafanaClient.GetServiceAccountTokens(ctx, org.ID, serviceAccount.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, token := range tokens {
+			if token.HasExpired {
+				expirations.expired++
+			} else {
+				c.countExpiration(&expirations, token.Expiration, now)
+			}
+			if token.Expiration != nil {
+				metrics = append(metrics, prometheus.MustNewConstMetric(
+					c.serviceAccountTokenExpiryDesc,
+					prometheus.GaugeValue,
+					float64(token.Expiration.Unix()),
+					orgID,
+					org.Name,
+					serviceAccount.Name,
+					token.Name,
+					serviceAccount.Role,
+				))
+			}
+		}
+	}
+	metrics = append(metrics, c.expirationsMetrics(expirations, orgID, org.Name, serviceAccountTokenKind)...)
+
+	return metrics, nil
+}
+
+func (c *APIKeysCollector) countExpiration(expirations *apiKeysExpirations, expiration *time.Time, now time.Time) {
+	switch {
+	case expiration == nil:
+		expirations.nonExpiring++
+	case expiration.Before(now):
+		expirations.expired++
+	case expiration.Before(now.Add(c.expiryWindow)):
+		expirations.expiring++
+	}
+}
+
+func (c *APIKeysCollector) expirationsMetrics(expirations apiKeysExpirations, orgID string, orgName string, kind string) []prometheus.Metric {
+	return []prometheus.Metric{
+		prometheus.MustNewConstMetric(c.expiringDesc, prometheus.GaugeValue, float64(expirations.expiring), orgID, orgName, kind),
+		prometheus.MustNewConstMetric(c.expiredDesc, prometheus.GaugeValue, float64(expirations.expired), orgID, orgName, kind),
+		prometheus.MustNewConstMetric(c.nonExpiringDesc, prometheus.GaugeValue, float64(expirations.nonExpiring), orgID, orgName, kind),
+	}
+}
@@ -0,0 +1,382 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("APIKeysCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+		expiryWindow  time.Duration
+
+		apiKeyExpiryDesc                *prometheus.Desc
+		serviceAccountTokenExpiryDesc   *prometheus.Desc
+		expiringDesc                    *prometheus.Desc
+		expiredDesc                     *prometheus.Desc
+		nonExpiringDesc                 *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		apiKeysCollector *APIKeysCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		expiryWindow = 7 * 24 * time.Hour
+
+		apiKeyExpiryDesc = prometheus.NewDesc(
+			"grafana_api_key_expiry_timestamp_seconds",
+			"Number of seconds since 1970 until the expiration of the Grafana API Key.",
+			[]string{"org_id", "org_name", "name", "role"},
+			constLabels,
+		)
+
+		serviceAccountTokenExpiryDesc = prometheus.NewDesc(
+			"grafana_service_account_token_expiry_timestamp_seconds",
+			"Number of seconds since 1970 until the expiration of the Grafana Service Account Token.",
+			[]string{"org_id", "org_name", "service_account", "name", "role"},
+			constLabels,
+		)
+
+		expiringDesc = prometheus.NewDesc(
+			"grafana_api_keys_expiring",
+			"Number of Grafana API Keys or Service Account Tokens expiring within the expiry window.",
+			[]string{"org_id", "org_name", "kind"},
+			constLabels,
+		)
+
+		expiredDesc = prometheus.NewDesc(
+			"grafana_api_keys_expired",
+			"Number of expired Grafana API Keys or Service Account Tokens.",
+			[]string{"org_id", "org_name", "kind"},
+			constLabels,
+		)
+
+		nonExpiringDesc = prometheus.NewDesc(
+			"grafana_api_keys_non_expiring",
+			"Number of Grafana API Keys or Service Account Tokens that never expire.",
+			[]string{"org_id", "org_name", "kind"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "api_keys",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana API Keys scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "api_keys",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana API Keys scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "api_keys",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana API Keys scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "api_keys",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana API Keys resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "api_keys",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana API Keys.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "api_keys",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana API Keys.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		apiKeysCollector = NewAPIKeysCollector(grafanaClient, constLabels, orgFilter, expiryWindow)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go apiKeysCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_api_key_expiry_timestamp_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(apiKeyExpiryDesc)))
+		})
+
+		It("returns a grafana_service_account_token_expiry_timestamp_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(serviceAccountTokenExpiryDesc)))
+		})
+
+		It("returns a grafana_api_keys_expiring metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(expiringDesc)))
+		})
+
+		It("returns a grafana_api_keys_expired metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(expiredDesc)))
+		})
+
+		It("returns a grafana_api_keys_non_expiring metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(nonExpiringDesc)))
+		})
+
+		It("returns a grafana_api_keys_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_api_keys_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_api_keys_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_api_keys_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_api_keys_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_api_keys_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			expiringSoon time.Time
+			expiringLate time.Time
+			expiredAt    time.Time
+
+			metricDesc  = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+			constMetric = func(desc *prometheus.Desc, value float64, labelValues ...string) OmegaMatcher {
+				return And(
+					WithTransform(metricDesc, Equal(desc)),
+					PrometheusMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)),
+				)
+			}
+		)
+
+		BeforeEach(func() {
+			now := time.Now()
+			expiringSoon = now.Add(2 * 24 * time.Hour)
+			expiringLate = now.Add(30 * 24 * time.Hour)
+			expiredAt = now.Add(-24 * time.Hour)
+
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetAPIKeysStub = func(ctx context.Context, orgID int64) ([]grafana.APIKey, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "api keys", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.APIKey{
+					{ID: 1, Name: "fake-expiring-key", Role: "Viewer", Expiration: &expiringSoon},
+					{ID: 2, Name: "fake-expired-key", Role: "Admin", Expiration: &expiredAt},
+					{ID: 3, Name: "fake-non-expiring-key", Role: "Editor"},
+					{ID: 4, Name: "fake-other-non-expiring-key", Role: "Editor"},
+				}, nil
+			}
+			grafanaClient.GetServiceAccountsStub = func(ctx context.Context, orgID int64) ([]grafana.ServiceAccount, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "service accounts", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.ServiceAccount{
+					{ID: 1, Name: "fake-service-account", Role: "Editor", Tokens: 3},
+					{ID: 2, Name: "fake-disabled-service-account", Role: "Admin", IsDisabled: true, Tokens: 1},
+					{ID: 3, Name: "fake-tokenless-service-account", Role: "Viewer"},
+				}, nil
+			}
+			grafanaClient.GetServiceAccountTokensReturns([]grafana.ServiceAccountToken{
+				{ID: 1, Name: "fake-token", Expiration: &expiringLate},
+				{ID: 2, Name: "fake-non-expiring-token"},
+				{ID: 3, Name: "fake-expired-token", Expiration: &expiredAt, HasExpired: true},
+			}, nil)
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go apiKeysCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_api_key_expiry_timestamp_seconds metric for an expiring API key", func() {
+			Eventually(metrics).Should(Receive(constMetric(apiKeyExpiryDesc, float64(expiringSoon.Unix()), "1", "Main Org.", "fake-expiring-key", "Viewer")))
+		})
+
+		It("returns a grafana_service_account_token_expiry_timestamp_seconds metric for an expiring token", func() {
+			Eventually(metrics).Should(Receive(constMetric(serviceAccountTokenExpiryDesc, float64(expiringLate.Unix()), "1", "Main Org.", "fake-service-account", "fake-token", "Editor")))
+		})
+
+		It("returns a grafana_api_keys_expiring metric for the API keys", func() {
+			Eventually(metrics).Should(Receive(constMetric(expiringDesc, 1, "1", "Main Org.", "api_key")))
+		})
+
+		It("returns a grafana_api_keys_expired metric for the API keys", func() {
+			Eventually(metrics).Should(Receive(constMetric(expiredDesc, 1, "1", "Main Org.", "api_key")))
+		})
+
+		It("returns a grafana_api_keys_non_expiring metric for the API keys", func() {
+			Eventually(metrics).Should(Receive(constMetric(nonExpiringDesc, 2, "1", "Main Org.", "api_key")))
+		})
+
+		It("returns a grafana_api_keys_expiring metric for the service account tokens", func() {
+			Eventually(metrics).Should(Receive(constMetric(expiringDesc, 0, "1", "Main Org.", "service_account_token")))
+		})
+
+		It("returns a grafana_api_keys_expired metric for the service account tokens", func() {
+			Eventually(metrics).Should(Receive(constMetric(expiredDesc, 1, "1", "Main Org.", "service_account_token")))
+		})
+
+		It("returns a grafana_api_keys_non_expiring metric for the service account tokens", func() {
+			Eventually(metrics).Should(Receive(constMetric(nonExpiringDesc, 1, "1", "Main Org.", "service_account_token")))
+		})
+
+		It("only requests the tokens of the enabled service accounts with tokens", func() {
+			Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+			Expect(grafanaClient.GetServiceAccountTokensCallCount()).To(Equal(1))
+			_, _, serviceAccountID := grafanaClient.GetServiceAccountTokensArgsForCall(0)
+			Expect(serviceAccountID).To(Equal(int64(1)))
+		})
+
+		It("does not return metrics for the endpoints not available in the Grafana version", func() {
+			Consistently(metrics).ShouldNot(Receive(constMetric(expiringDesc, 0, "2", "fake-org", "api_key")))
+		})
+
+		It("returns a grafana_api_keys_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_api_keys_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_api_keys_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the expiry window is wider", func() {
+			BeforeEach(func() {
+				expiryWindow = 60 * 24 * time.Hour
+			})
+
+			It("counts the tokens expiring within the window", func() {
+				Eventually(metrics).Should(Receive(constMetric(expiringDesc, 1, "1", "Main Org.", "service_account_token")))
+			})
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetAPIKeysCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the service account tokens", func() {
+			BeforeEach(func() {
+				grafanaClient.GetServiceAccountTokensReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("does not return the metrics of the org", func() {
+				Consistently(metrics).ShouldNot(Receive(constMetric(expiringDesc, 1, "1", "Main Org.", "api_key")))
+			})
+
+			It("returns a grafana_api_keys_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_api_keys_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_api_keys_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_api_keys_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type CollectorEnabledCollector struct {
+	versionDetector      *VersionDetector
+	versionRanges        map[string]VersionRange
+	collectorEnabledDesc *prometheus.Desc
+}
+
+// NewCollectorEnabledCollector returns a collector of whether the given
+// collectors are enabled for the detected Grafana version, keyed by the
+// collector name.
+func NewCollectorEnabledCollector(versionDetector *VersionDetector, constLabels prometheus.Labels, versionRanges map[string]VersionRange) *CollectorEnabledCollector {
+	collectorEnabledDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana_exporter", "", "collector_enabled"),
+		"Whether the collector is enabled for the detected Grafana version (1 for enabled, 0 for disabled).",
+		[]string{"collector"},
+		constLabels,
+	)
+
+	collectorEnabledCollector := &CollectorEnabledCollector{
+		versionDetector:      versionDetector,
+		versionRanges:        versionRanges,
+		collectorEnabledDesc: collectorEnabledDesc,
+	}
+
+	return collectorEnabledCollector
+}
+
+func (c *CollectorEnabledCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.collectorEnabledDesc
+}
+
+func (c *CollectorEnabledCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *CollectorEnabledCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	// Only detect the version when a collector is bound to a version range.
+	var (
+		version         string
+		versionDetected bool
+	)
+	for collector, versionRange := range c.versionRanges {
+		enabled := float64(1)
+		if versionRange != (VersionRange{}) {
+			if !versionDetected {
+				version = c.versionDetector.Version(ctx)
+				versionDetected = true
+			}
+			if !versionRange.Contains(version) {
+				enabled = float64(0)
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.collectorEnabledDesc, prometheus.GaugeValue, enabled, collector)
+	}
+}
@@ -0,0 +1,107 @@
+package collectors_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("CollectorEnabledCollector", func() {
+	var (
+		grafanaClient   *grafanafakes.FakeClient
+		constLabels     prometheus.Labels
+		versionDetector *VersionDetector
+		versionRanges   map[string]VersionRange
+
+		collectorEnabledDesc *prometheus.Desc
+
+		collectorEnabledCollector *CollectorEnabledCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "11.1.0"}, nil)
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		versionDetector = NewVersionDetector(grafanaClient, time.Hour, time.Hour)
+		versionRanges = map[string]VersionRange{
+			"alerting":      {Min: "8.0.0"},
+			"legacy_alerts": {Max: "11.0.0"},
+			"metrics":       {},
+		}
+
+		collectorEnabledDesc = prometheus.NewDesc(
+			"grafana_exporter_collector_enabled",
+			"Whether the collector is enabled for the detected Grafana version (1 for enabled, 0 for disabled).",
+			[]string{"collector"},
+			constLabels,
+		)
+	})
+
+	JustBeforeEach(func() {
+		collectorEnabledCollector = NewCollectorEnabledCollector(versionDetector, constLabels, versionRanges)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go collectorEnabledCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_exporter_collector_enabled metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(collectorEnabledDesc)))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			metrics chan prometheus.Metric
+		)
+
+		BeforeEach(func() {
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go collectorEnabledCollector.CollectContext(context.Background(), metrics)
+		})
+
+		It("returns a grafana_exporter_collector_enabled metric for the supported collectors", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(prometheus.MustNewConstMetric(collectorEnabledDesc, prometheus.GaugeValue, 1, "alerting"))))
+		})
+
+		It("returns a grafana_exporter_collector_enabled metric for the unsupported collectors", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(prometheus.MustNewConstMetric(collectorEnabledDesc, prometheus.GaugeValue, 0, "legacy_alerts"))))
+		})
+
+		It("returns a grafana_exporter_collector_enabled metric for the collectors without version range", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(prometheus.MustNewConstMetric(collectorEnabledDesc, prometheus.GaugeValue, 1, "metrics"))))
+		})
+
+		Context("when no collector has a version range", func() {
+			BeforeEach(func() {
+				versionRanges = map[string]VersionRange{"metrics": {}}
+			})
+
+			It("does not detect the Grafana version", func() {
+				Eventually(metrics).Should(Receive())
+				Expect(grafanaClient.GetHealthCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
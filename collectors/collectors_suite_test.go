@@ -1,9 +1,16 @@
 package collectors_test
 
 import (
+	"context"
+	"sync"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+
 	"testing"
 )
 
@@ -11,3 +18,35 @@ func TestCollectors(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Collectors Suite")
 }
+
+// backgroundCollections runs the collections of a spec in the background, and
+// drains their metrics once the spec is done, so a collection blocked on a
+// metric the spec did not receive does not outlive the spec and read the
+// fakes of the next one.
+type backgroundCollections struct {
+	wg sync.WaitGroup
+}
+
+func (b *backgroundCollections) Collect(ctx context.Context, collector ContextCollector, ch chan<- prometheus.Metric) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		collector.CollectContext(ctx, ch)
+	}()
+}
+
+func (b *backgroundCollections) Drain(ch <-chan prometheus.Metric) {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ContextCollector is a prometheus.Collector whose scrapes can be bound to a
+// context, so the requests to Grafana are cancelled once the context is done.
+type ContextCollector interface {
+	prometheus.Collector
+	CollectContext(ctx context.Context, ch chan<- prometheus.Metric)
+}
+
+type contextCollector struct {
+	ctx       context.Context
+	collector ContextCollector
+}
+
+// WithContext returns a prometheus.Collector that scrapes the collector
+// bound to the given context.
+func WithContext(ctx context.Context, collector ContextCollector) prometheus.Collector {
+	return &contextCollector{
+		ctx:       ctx,
+		collector: collector,
+	}
+}
+
+func (c *contextCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.collector.Describe(ch)
+}
+
+func (c *contextCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collector.CollectContext(c.ctx, ch)
+}
@@ -0,0 +1,45 @@
+package collectors
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// counterMetric exports a Grafana counter as a const Prometheus counter built
+// at scrape time. When legacy metric names are enabled, the counter is also
+// exported as a gauge named without the `_total` suffix, as it was exported
+// before being converted to a counter.
+type counterMetric struct {
+	desc       *prometheus.Desc
+	legacyDesc *prometheus.Desc
+}
+
+func newCounterMetric(opts prometheus.CounterOpts, variableLabels []string, legacyMetricNames bool) *counterMetric {
+	counterMetric := &counterMetric{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+			opts.Help,
+			variableLabels,
+			opts.ConstLabels,
+		),
+	}
+
+	if legacyMetricNames {
+		counterMetric.legacyDesc = prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, opts.Subsystem, strings.TrimSuffix(opts.Name, "_total")),
+			opts.Help,
+			variableLabels,
+			opts.ConstLabels,
+		)
+	}
+
+	return counterMetric
+}
+
+func (m *counterMetric) collect(ch chan<- prometheus.Metric, value float64, labelValues ...string) {
+	ch <- prometheus.MustNewConstMetric(m.desc, prometheus.CounterValue, value, labelValues...)
+	if m.legacyDesc != nil {
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, value, labelValues...)
+	}
+}
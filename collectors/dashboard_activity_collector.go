@@ -0,0 +1,363 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+type dashboardFolderKey struct {
+	orgID       string
+	orgName     string
+	folderUID   string
+	folderTitle string
+}
+
+type dashboardSavesKey struct {
+	dashboardFolderKey
+	user string
+}
+
+// dashboardVersionsLimit is the maximum number of versions of a dashboard
+// requested at once, so at most as many saves of a dashboard are counted by a
+// scrape.
+const dashboardVersionsLimit = 100
+
+// dashboardBackfillsLimit is the maximum number of dashboards seen for the
+// first time whose versions are requested by a scrape. The versions of the
+// other dashboards are requested by the next scrapes.
+const dashboardBackfillsLimit = 100
+
+type DashboardActivityCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	dashboardsFetcher               *DashboardsFetcher
+	infoLimit                       int
+	mtx                             sync.Mutex
+	dashboardVersions               map[string]int
+	saves                           map[dashboardSavesKey]float64
+	updatedTimestampDesc            *prometheus.Desc
+	versionDesc                     *prometheus.Desc
+	folderUpdatedTimestampDesc      *prometheus.Desc
+	savesTotalDesc                  *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewDashboardActivityCollector returns a collector of the updates of the
+// dashboards of the orgs matching the org filter. At most info limit
+// dashboards are exported as per dashboard series, none if it is 0.
+func NewDashboardActivityCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, dashboardsFetcher *DashboardsFetcher, infoLimit int) *DashboardActivityCollector {
+	updatedTimestampDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "updated_timestamp_seconds"),
+		"Number of seconds since 1970 since the last update of the Grafana Dashboard.",
+		[]string{"org_id", "uid", "title", "folder_uid", "folder_title"},
+		constLabels,
+	)
+
+	versionDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "version"),
+		"Version of the Grafana Dashboard, incremented on every save.",
+		[]string{"org_id", "uid", "title", "folder_uid", "folder_title"},
+		constLabels,
+	)
+
+	folderUpdatedTimestampDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboards", "updated_timestamp_seconds"),
+		"Number of seconds since 1970 since the last update of a Grafana Dashboard of the folder.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title"},
+		constLabels,
+	)
+
+	savesTotalDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "saves_total"),
+		"Total number of Grafana Dashboard saves seen by the exporter.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "user"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboard_activity",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Dashboard Activity scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboard_activity",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Dashboard Activity scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboard_activity",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Dashboard Activity scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboard_activity",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Dashboard Activity resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboard_activity",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Dashboard Activity.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboard_activity",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Dashboard Activity.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	dashboardActivityCollector := &DashboardActivityCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		dashboardsFetcher:               dashboardsFetcher,
+		infoLimit:                       infoLimit,
+		dashboardVersions:               map[string]int{},
+		saves:                           map[dashboardSavesKey]float64{},
+		updatedTimestampDesc:            updatedTimestampDesc,
+		versionDesc:                     versionDesc,
+		folderUpdatedTimestampDesc:      folderUpdatedTimestampDesc,
+		savesTotalDesc:                  savesTotalDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return dashboardActivityCollector
+}
+
+func (c *DashboardActivityCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.updatedTimestampDesc
+	ch <- c.versionDesc
+	ch <- c.folderUpdatedTimestampDesc
+	ch <- c.savesTotalDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *DashboardActivityCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *DashboardActivityCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportDashboardActivityMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Dashboard Activity metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Dashboard Activity metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *DashboardActivityCollector) reportDashboardActivityMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	// The saves are counted from the dashboard versions seen by previous
+	// scrapes, so concurrent scrapes must not count them twice.
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	folderUpdatedTimestamps := map[dashboardFolderKey]time.Time{}
+	seenDashboards := map[string]bool{}
+	seenFolders := map[dashboardFolderKey]bool{}
+	infoCount := 0
+	backfills := 0
+
+	// Keep on counting the saves of the other dashboards when the versions of
+	// a dashboard cannot be requested.
+	var dashboardErrors []string
+	err := forEachOrg(ctx, c.grafanaClient, c.orgFilter, "dashboards", func(org grafana.Org) error {
+		orgDashboards, err := c.dashboardsFetcher.OrgDashboards(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		orgID := strconv.FormatInt(org.ID, 10)
+		for _, dashboard := range orgDashboards {
+			folderTitle := dashboard.Meta.FolderTitle
+			if dashboard.Meta.FolderUID == "" {
+				folderTitle = generalFolderTitle
+			}
+			folderKey := dashboardFolderKey{orgID: orgID, orgName: org.Name, folderUID: dashboard.Meta.FolderUID, folderTitle: folderTitle}
+
+			seenDashboards[orgID+"/"+dashboard.Dashboard.UID] = true
+			seenFolders[folderKey] = true
+			if err := c.countSaves(ctx, org, dashboard, folderKey, &backfills); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				dashboardErrors = append(dashboardErrors, fmt.Sprintf("Error getting versions of dashboard `%s` of org `%s`: %s", dashboard.Dashboard.UID, org.Name, err))
+			}
+
+			if dashboard.Meta.Updated.IsZero() {
+				continue
+			}
+			if dashboard.Meta.Updated.After(folderUpdatedTimestamps[folderKey]) {
+				folderUpdatedTimestamps[folderKey] = dashboard.Meta.Updated
+			}
+
+			if infoCount >= c.infoLimit {
+				continue
+			}
+			infoCount++
+
+			labelValues := []string{orgID, dashboard.Dashboard.UID, dashboard.Dashboard.Title, dashboard.Meta.FolderUID, folderTitle}
+			ch <- prometheus.MustNewConstMetric(c.updatedTimestampDesc, prometheus.GaugeValue, float64(dashboard.Meta.Updated.Unix()), labelValues...)
+			ch <- prometheus.MustNewConstMetric(c.versionDesc, prometheus.GaugeValue, float64(dashboard.Meta.Version), labelValues...)
+		}
+
+		return nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+
+	for key, updated := range folderUpdatedTimestamps {
+		ch <- prometheus.MustNewConstMetric(c.folderUpdatedTimestampDesc, prometheus.GaugeValue, float64(updated.Unix()), key.orgID, key.orgName, key.folderUID, key.folderTitle)
+	}
+
+	if err != nil {
+		dashboardErrors = append([]string{err.Error()}, dashboardErrors...)
+	}
+
+	// Forget the deleted dashboards and the saves of the deleted folders, only
+	// once every org has been scraped so the saves of the dashboards of a
+	// failing org are neither counted again nor reset.
+	if len(dashboardErrors) == 0 {
+		for dashboardKey := range c.dashboardVersions {
+			if !seenDashboards[dashboardKey] {
+				delete(c.dashboardVersions, dashboardKey)
+			}
+		}
+		for key := range c.saves {
+			if !seenFolders[key.dashboardFolderKey] {
+				delete(c.saves, key)
+			}
+		}
+	}
+
+	for key, saves := range c.saves {
+		ch <- prometheus.MustNewConstMetric(c.savesTotalDesc, prometheus.CounterValue, saves, key.orgID, key.orgName, key.folderUID, key.folderTitle, key.user)
+	}
+
+	if len(dashboardErrors) > 0 {
+		return errors.New(strings.Join(dashboardErrors, "; "))
+	}
+
+	return nil
+}
+
+// countSaves counts the versions of the dashboard saved since the previous
+// scrape, per user. The versions are only requested when the dashboard
+// version changed, and the versions kept by Grafana are counted the first
+// time a dashboard is seen, up to dashboardVersionsLimit versions and
+// dashboardBackfillsLimit dashboards per scrape.
+func (c *DashboardActivityCollector) countSaves(ctx context.Context, org grafana.Org, dashboard grafana.Dashboard, folderKey dashboardFolderKey, backfills *int) error {
+	dashboardKey := folderKey.orgID + "/" + dashboard.Dashboard.UID
+	lastVersion, seen := c.dashboardVersions[dashboardKey]
+	if dashboard.Meta.Version <= lastVersion {
+		// A dashboard deleted and created again with the same uid starts
+		// over from version 1.
+		c.dashboardVersions[dashboardKey] = dashboard.Meta.Version
+		return nil
+	}
+
+	if !seen {
+		if *backfills >= dashboardBackfillsLimit {
+			return nil
+		}
+		*backfills++
+	}
+
+	limit := dashboard.Meta.Version - lastVersion
+	if limit > dashboardVersionsLimit {
+		limit = dashboardVersionsLimit
+	}
+
+	dashboardVersions, err := c.grafanaClient.GetDashboardVersions(ctx, org.ID, dashboard.Dashboard.UID, limit)
+	if err != nil {
+		// Grafana versions without the dashboard versions by uid endpoint
+		// do not report the saves.
+		if isNotFound(err) {
+			c.dashboardVersions[dashboardKey] = dashboard.Meta.Version
+			return nil
+		}
+		return err
+	}
+
+	for _, dashboardVersion := range dashboardVersions {
+		if dashboardVersion.Version > lastVersion {
+			c.saves[dashboardSavesKey{dashboardFolderKey: folderKey, user: dashboardVersion.CreatedBy}]++
+		}
+	}
+	c.dashboardVersions[dashboardKey] = dashboard.Meta.Version
+
+	return nil
+}
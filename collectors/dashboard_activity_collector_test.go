@@ -0,0 +1,485 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("DashboardActivityCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+		infoLimit     int
+
+		updatedTimestampDesc            *prometheus.Desc
+		versionDesc                     *prometheus.Desc
+		folderUpdatedTimestampDesc      *prometheus.Desc
+		savesTotalDesc                  *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		dashboardActivityCollector *DashboardActivityCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		infoLimit = 0
+
+		updatedTimestampDesc = prometheus.NewDesc(
+			"grafana_dashboard_updated_timestamp_seconds",
+			"Number of seconds since 1970 since the last update of the Grafana Dashboard.",
+			[]string{"org_id", "uid", "title", "folder_uid", "folder_title"},
+			constLabels,
+		)
+
+		versionDesc = prometheus.NewDesc(
+			"grafana_dashboard_version",
+			"Version of the Grafana Dashboard, incremented on every save.",
+			[]string{"org_id", "uid", "title", "folder_uid", "folder_title"},
+			constLabels,
+		)
+
+		folderUpdatedTimestampDesc = prometheus.NewDesc(
+			"grafana_dashboards_updated_timestamp_seconds",
+			"Number of seconds since 1970 since the last update of a Grafana Dashboard of the folder.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title"},
+			constLabels,
+		)
+
+		savesTotalDesc = prometheus.NewDesc(
+			"grafana_dashboard_saves_total",
+			"Total number of Grafana Dashboard saves seen by the exporter.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "user"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboard_activity",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Dashboard Activity scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboard_activity",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Dashboard Activity scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboard_activity",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Dashboard Activity scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboard_activity",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Dashboard Activity resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboard_activity",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Dashboard Activity.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboard_activity",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Dashboard Activity.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		dashboardActivityCollector = NewDashboardActivityCollector(grafanaClient, constLabels, orgFilter, NewDashboardsFetcher(grafanaClient), infoLimit)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go dashboardActivityCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_dashboard_updated_timestamp_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(updatedTimestampDesc)))
+		})
+
+		It("returns a grafana_dashboard_version metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(versionDesc)))
+		})
+
+		It("returns a grafana_dashboards_updated_timestamp_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(folderUpdatedTimestampDesc)))
+		})
+
+		It("returns a grafana_dashboard_saves_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(savesTotalDesc)))
+		})
+
+		It("returns a grafana_dashboard_activity_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboard_activity_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboard_activity_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboard_activity_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboard_activity_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboard_activity_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx         context.Context
+			metrics     chan prometheus.Metric
+			collections backgroundCollections
+			updated     time.Time
+
+			metricDesc = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+		)
+
+		BeforeEach(func() {
+			updated = time.Date(2017, 2, 3, 4, 5, 6, 0, time.UTC)
+			dashboards := map[string]grafana.Dashboard{
+				"fake-dashboard-1": {
+					Dashboard: grafana.DashboardModel{UID: "fake-dashboard-1", Title: "Fake Dashboard 1"},
+					Meta:      grafana.DashboardMeta{Updated: updated, UpdatedBy: "fake-user", Version: 3},
+				},
+				"fake-dashboard-2": {
+					Dashboard: grafana.DashboardModel{UID: "fake-dashboard-2", Title: "Fake Dashboard 2"},
+					Meta:      grafana.DashboardMeta{FolderUID: "fake-folder", FolderTitle: "Fake Folder", Updated: updated.Add(-time.Hour), UpdatedBy: "admin", Version: 1},
+				},
+			}
+			dashboardVersions := map[string]grafana.DashboardVersions{
+				"fake-dashboard-1": {{Version: 3, CreatedBy: "fake-user"}, {Version: 2, CreatedBy: "admin"}, {Version: 1, CreatedBy: "admin"}},
+				"fake-dashboard-2": {{Version: 1, CreatedBy: "admin"}},
+			}
+
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}}, nil)
+			grafanaClient.GetDashboardsReturns([]grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil)
+			grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+				return dashboards[uid], nil
+			}
+			grafanaClient.GetDashboardVersionsStub = func(ctx context.Context, orgID int64, uid string, limit int) (grafana.DashboardVersions, error) {
+				return dashboardVersions[uid], nil
+			}
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			collections.Collect(ctx, dashboardActivityCollector, metrics)
+		})
+
+		AfterEach(func() {
+			collections.Drain(metrics)
+		})
+
+		It("requests the dashboard versions saved since the dashboard was first seen", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+			Expect(grafanaClient.GetDashboardVersionsCallCount()).To(Equal(2))
+			_, orgID, uid, limit := grafanaClient.GetDashboardVersionsArgsForCall(0)
+			Expect(orgID).To(Equal(int64(1)))
+			Expect(uid).To(Equal("fake-dashboard-1"))
+			Expect(limit).To(Equal(3))
+		})
+
+		It("returns a grafana_dashboard_saves_total metric for a user", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 2, "1", "Main Org.", "", "General", "admin"),
+			)))
+		})
+
+		It("returns a grafana_dashboard_saves_total metric for another user", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 1, "1", "Main Org.", "", "General", "fake-user"),
+			)))
+		})
+
+		It("returns a grafana_dashboard_saves_total metric for another folder", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "admin"),
+			)))
+		})
+
+		It("returns a grafana_dashboards_updated_timestamp_seconds metric for the General folder", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(folderUpdatedTimestampDesc, prometheus.GaugeValue, float64(updated.Unix()), "1", "Main Org.", "", "General"),
+			)))
+		})
+
+		It("returns a grafana_dashboards_updated_timestamp_seconds metric for a folder", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(folderUpdatedTimestampDesc, prometheus.GaugeValue, float64(updated.Add(-time.Hour).Unix()), "1", "Main Org.", "fake-folder", "Fake Folder"),
+			)))
+		})
+
+		It("does not return a grafana_dashboard_updated_timestamp_seconds metric", func() {
+			Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(updatedTimestampDesc))))
+		})
+
+		It("returns a grafana_dashboard_activity_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_dashboard_activity_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the dashboards are scraped again", func() {
+			JustBeforeEach(func() {
+				Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+			})
+
+			It("only requests the latest version of the unchanged dashboards", func() {
+				collections.Collect(ctx, dashboardActivityCollector, metrics)
+				Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+				Expect(grafanaClient.GetDashboardVersionsCallCount()).To(Equal(4))
+				_, _, _, limit := grafanaClient.GetDashboardVersionsArgsForCall(2)
+				Expect(limit).To(Equal(1))
+				_, _, _, limit = grafanaClient.GetDashboardVersionsArgsForCall(3)
+				Expect(limit).To(Equal(1))
+				Expect(grafanaClient.GetDashboardCallCount()).To(Equal(2))
+			})
+
+			It("forgets the saves of the folders without dashboards", func() {
+				grafanaClient.GetDashboardsReturns([]grafana.SearchHit{{UID: "fake-dashboard-1"}}, nil)
+
+				collections.Collect(ctx, dashboardActivityCollector, metrics)
+				Consistently(metrics).ShouldNot(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "admin"),
+				)))
+			})
+
+			It("counts the new saves", func() {
+				getDashboard := grafanaClient.GetDashboardStub
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					dashboard, err := getDashboard(ctx, orgID, uid)
+					if uid == "fake-dashboard-1" {
+						dashboard.Meta.Version = 4
+					}
+					return dashboard, err
+				}
+				grafanaClient.GetDashboardVersionsReturns(grafana.DashboardVersions{{Version: 4, CreatedBy: "fake-user"}}, nil)
+				grafanaClient.GetDashboardVersionsStub = nil
+
+				collections.Collect(ctx, dashboardActivityCollector, metrics)
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 2, "1", "Main Org.", "", "General", "fake-user"),
+				)))
+				_, _, uid, limit := grafanaClient.GetDashboardVersionsArgsForCall(grafanaClient.GetDashboardVersionsCallCount() - 1)
+				Expect(uid).To(Equal("fake-dashboard-1"))
+				Expect(limit).To(Equal(1))
+			})
+		})
+
+		Context("when a dashboard has more versions than requested at once", func() {
+			BeforeEach(func() {
+				getDashboard := grafanaClient.GetDashboardStub
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					dashboard, err := getDashboard(ctx, orgID, uid)
+					if uid == "fake-dashboard-1" {
+						dashboard.Meta.Version = 150
+					}
+					return dashboard, err
+				}
+			})
+
+			It("requests at most 100 versions", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				_, _, uid, limit := grafanaClient.GetDashboardVersionsArgsForCall(0)
+				Expect(uid).To(Equal("fake-dashboard-1"))
+				Expect(limit).To(Equal(100))
+			})
+		})
+
+		Context("when more dashboards than backfilled by a scrape are seen for the first time", func() {
+			var backfilledUIDs func() []string
+
+			BeforeEach(func() {
+				var searchHits []grafana.SearchHit
+				for i := 0; i < 101; i++ {
+					searchHits = append(searchHits, grafana.SearchHit{UID: fmt.Sprintf("fake-dashboard-%03d", i)})
+				}
+				grafanaClient.GetDashboardsReturns(searchHits, nil)
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					return grafana.Dashboard{Dashboard: grafana.DashboardModel{UID: uid}, Meta: grafana.DashboardMeta{Version: 2}}, nil
+				}
+				grafanaClient.GetDashboardVersionsReturns(grafana.DashboardVersions{{Version: 2, CreatedBy: "admin"}, {Version: 1, CreatedBy: "admin"}}, nil)
+				grafanaClient.GetDashboardVersionsStub = nil
+
+				// The dashboards fetcher requests the latest version only.
+				backfilledUIDs = func() []string {
+					var uids []string
+					for i := 0; i < grafanaClient.GetDashboardVersionsCallCount(); i++ {
+						_, _, uid, limit := grafanaClient.GetDashboardVersionsArgsForCall(i)
+						if limit == 2 {
+							uids = append(uids, uid)
+						}
+					}
+					return uids
+				}
+			})
+
+			It("requests the versions of 100 dashboards", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 200, "1", "Main Org.", "", "General", "admin"),
+				)))
+				Expect(backfilledUIDs()).To(HaveLen(100))
+				Expect(backfilledUIDs()).ToNot(ContainElement("fake-dashboard-100"))
+			})
+
+			It("requests the versions of the other dashboards on the next scrape", func() {
+				Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+
+				collections.Collect(ctx, dashboardActivityCollector, metrics)
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(savesTotalDesc, prometheus.CounterValue, 202, "1", "Main Org.", "", "General", "admin"),
+				)))
+				Expect(backfilledUIDs()).To(HaveLen(101))
+				Expect(backfilledUIDs()[100]).To(Equal("fake-dashboard-100"))
+			})
+		})
+
+		Context("when the per dashboard series are enabled", func() {
+			BeforeEach(func() {
+				infoLimit = 10
+			})
+
+			It("returns a grafana_dashboard_updated_timestamp_seconds metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(updatedTimestampDesc, prometheus.GaugeValue, float64(updated.Unix()), "1", "fake-dashboard-1", "Fake Dashboard 1", "", "General"),
+				)))
+			})
+
+			It("returns a grafana_dashboard_version metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(versionDesc, prometheus.GaugeValue, 3, "1", "fake-dashboard-1", "Fake Dashboard 1", "", "General"),
+				)))
+			})
+		})
+
+		Context("when the dashboard versions are not available", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardVersionsReturns(nil, grafana.StatusCodeError{Resource: "dashboard versions", StatusCode: http.StatusNotFound})
+				grafanaClient.GetDashboardVersionsStub = nil
+			})
+
+			It("does not return a grafana_dashboard_saves_total metric", func() {
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(savesTotalDesc))))
+			})
+
+			It("returns a grafana_dashboard_activity_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the dashboard versions", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardVersionsReturns(nil, errors.New("error"))
+				grafanaClient.GetDashboardVersionsStub = nil
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_dashboards_updated_timestamp_seconds metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(folderUpdatedTimestampDesc, prometheus.GaugeValue, float64(updated.Unix()), "1", "Main Org.", "", "General"),
+				)))
+			})
+
+			It("returns a grafana_dashboard_activity_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_dashboard_activity_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_dashboard_activity_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_dashboard_activity_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
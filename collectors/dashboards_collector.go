@@ -0,0 +1,281 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// generalFolderTitle is the title of the folder holding the dashboards that
+// are not in a folder.
+const generalFolderTitle = "General"
+
+type dashboardsKey struct {
+	orgID       string
+	orgName     string
+	folderUID   string
+	folderTitle string
+	provisioned string
+}
+
+type dashboardsByTagKey struct {
+	orgID   string
+	orgName string
+	tag     string
+}
+
+type DashboardsCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	dashboardsFetcher               *DashboardsFetcher
+	infoLimit                       int
+	dashboardsDesc                  *prometheus.Desc
+	dashboardsByTagDesc             *prometheus.Desc
+	infoDesc                        *prometheus.Desc
+	infoTruncatedDesc               *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewDashboardsCollector returns a collector of the dashboards of the orgs
+// matching the org filter. At most info limit dashboards are exported as
+// `grafana_dashboard_info` series, none if it is 0.
+func NewDashboardsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, dashboardsFetcher *DashboardsFetcher, infoLimit int) *DashboardsCollector {
+	dashboardsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "", "dashboards"),
+		"Number of Grafana Dashboards.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "provisioned"},
+		constLabels,
+	)
+
+	dashboardsByTagDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboards", "by_tag"),
+		"Number of Grafana Dashboards with a tag.",
+		[]string{"org_id", "org_name", "tag"},
+		constLabels,
+	)
+
+	infoDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "info"),
+		"Grafana Dashboard information.",
+		[]string{"org_id", "uid", "title", "folder_uid", "folder_title", "schema_version"},
+		constLabels,
+	)
+
+	infoTruncatedDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "info_truncated"),
+		"Whether Grafana Dashboards were left out of the grafana_dashboard_info metric by the info limit (1 for truncated, 0 for complete).",
+		nil,
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboards",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Dashboards scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboards",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Dashboards scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboards",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Dashboards scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboards",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Dashboards resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboards",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Dashboards.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "dashboards",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Dashboards.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	dashboardsCollector := &DashboardsCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		dashboardsFetcher:               dashboardsFetcher,
+		infoLimit:                       infoLimit,
+		dashboardsDesc:                  dashboardsDesc,
+		dashboardsByTagDesc:             dashboardsByTagDesc,
+		infoDesc:                        infoDesc,
+		infoTruncatedDesc:               infoTruncatedDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return dashboardsCollector
+}
+
+func (c *DashboardsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.dashboardsDesc
+	ch <- c.dashboardsByTagDesc
+	ch <- c.infoDesc
+	ch <- c.infoTruncatedDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *DashboardsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *DashboardsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportDashboardsMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Dashboards metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Dashboards metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *DashboardsCollector) reportDashboardsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	dashboards := map[dashboardsKey]int{}
+	dashboardsByTag := map[dashboardsByTagKey]int{}
+	infoCount := 0
+	infoTruncated := float64(0)
+
+	err := forEachOrg(ctx, c.grafanaClient, c.orgFilter, "dashboards", func(org grafana.Org) error {
+		orgDashboards, err := c.dashboardsFetcher.OrgDashboards(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		orgID := strconv.FormatInt(org.ID, 10)
+		for _, dashboard := range orgDashboards {
+			folderTitle := dashboard.Meta.FolderTitle
+			if dashboard.Meta.FolderUID == "" {
+				folderTitle = generalFolderTitle
+			}
+
+			dashboards[dashboardsKey{
+				orgID:       orgID,
+				orgName:     org.Name,
+				folderUID:   dashboard.Meta.FolderUID,
+				folderTitle: folderTitle,
+				provisioned: strconv.FormatBool(dashboard.Meta.Provisioned),
+			}]++
+
+			for _, tag := range dashboard.Dashboard.Tags {
+				dashboardsByTag[dashboardsByTagKey{orgID: orgID, orgName: org.Name, tag: tag}]++
+			}
+
+			if infoCount >= c.infoLimit {
+				if c.infoLimit > 0 {
+					infoTruncated = 1
+				}
+				continue
+			}
+			infoCount++
+			ch <- prometheus.MustNewConstMetric(
+				c.infoDesc,
+				prometheus.GaugeValue,
+				1,
+				orgID,
+				dashboard.Dashboard.UID,
+				dashboard.Dashboard.Title,
+				dashboard.Meta.FolderUID,
+				folderTitle,
+				strconv.Itoa(dashboard.Dashboard.SchemaVersion),
+			)
+		}
+
+		return nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+
+	for key, count := range dashboards {
+		ch <- prometheus.MustNewConstMetric(c.dashboardsDesc, prometheus.GaugeValue, float64(count), key.orgID, key.orgName, key.folderUID, key.folderTitle, key.provisioned)
+	}
+
+	for key, count := range dashboardsByTag {
+		ch <- prometheus.MustNewConstMetric(c.dashboardsByTagDesc, prometheus.GaugeValue, float64(count), key.orgID, key.orgName, key.tag)
+	}
+
+	if c.infoLimit > 0 {
+		if infoTruncated == 1 {
+			log.Warnf("Only %d Grafana Dashboards exported as `grafana_dashboard_info` series, raise the dashboards info limit to export them all", c.infoLimit)
+		}
+		ch <- prometheus.MustNewConstMetric(c.infoTruncatedDesc, prometheus.GaugeValue, infoTruncated)
+	}
+
+	return err
+}
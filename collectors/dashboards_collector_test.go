@@ -0,0 +1,415 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("DashboardsCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+		infoLimit     int
+
+		dashboardsDesc                  *prometheus.Desc
+		dashboardsByTagDesc             *prometheus.Desc
+		infoDesc                        *prometheus.Desc
+		infoTruncatedDesc               *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		dashboardsCollector *DashboardsCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		infoLimit = 0
+
+		dashboardsDesc = prometheus.NewDesc(
+			"grafana_dashboards",
+			"Number of Grafana Dashboards.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "provisioned"},
+			constLabels,
+		)
+
+		dashboardsByTagDesc = prometheus.NewDesc(
+			"grafana_dashboards_by_tag",
+			"Number of Grafana Dashboards with a tag.",
+			[]string{"org_id", "org_name", "tag"},
+			constLabels,
+		)
+
+		infoDesc = prometheus.NewDesc(
+			"grafana_dashboard_info",
+			"Grafana Dashboard information.",
+			[]string{"org_id", "uid", "title", "folder_uid", "folder_title", "schema_version"},
+			constLabels,
+		)
+
+		infoTruncatedDesc = prometheus.NewDesc(
+			"grafana_dashboard_info_truncated",
+			"Whether Grafana Dashboards were left out of the grafana_dashboard_info metric by the info limit (1 for truncated, 0 for complete).",
+			nil,
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboards",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Dashboards scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboards",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Dashboards scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboards",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Dashboards scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboards",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Dashboards resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboards",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Dashboards.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "dashboards",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Dashboards.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		dashboardsCollector = NewDashboardsCollector(grafanaClient, constLabels, orgFilter, NewDashboardsFetcher(grafanaClient), infoLimit)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go dashboardsCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_dashboards metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(dashboardsDesc)))
+		})
+
+		It("returns a grafana_dashboards_by_tag metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(dashboardsByTagDesc)))
+		})
+
+		It("returns a grafana_dashboard_info metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(infoDesc)))
+		})
+
+		It("returns a grafana_dashboard_info_truncated metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(infoTruncatedDesc)))
+		})
+
+		It("returns a grafana_dashboards_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboards_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboards_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboards_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboards_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_dashboards_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx        context.Context
+			metrics    chan prometheus.Metric
+			dashboards map[string]grafana.Dashboard
+
+			metricDesc = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+		)
+
+		BeforeEach(func() {
+			dashboards = map[string]grafana.Dashboard{
+				"fake-dashboard-1": {
+					Dashboard: grafana.DashboardModel{UID: "fake-dashboard-1", Title: "Fake Dashboard 1", Tags: []string{"fake-tag-1", "fake-tag-2"}, SchemaVersion: 16},
+					Meta:      grafana.DashboardMeta{Provisioned: true},
+				},
+				"fake-dashboard-2": {
+					Dashboard: grafana.DashboardModel{UID: "fake-dashboard-2", Title: "Fake Dashboard 2", Tags: []string{"fake-tag-1"}, SchemaVersion: 27},
+					Meta:      grafana.DashboardMeta{FolderUID: "fake-folder", FolderTitle: "Fake Folder"},
+				},
+				"fake-dashboard-3": {
+					Dashboard: grafana.DashboardModel{UID: "fake-dashboard-3", Title: "Fake Dashboard 3", SchemaVersion: 36},
+				},
+			}
+
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetDashboardsStub = func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+				if orgID == 1 {
+					return []grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil
+				}
+				return []grafana.SearchHit{{UID: "fake-dashboard-3"}}, nil
+			}
+			grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+				return dashboards[uid], nil
+			}
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go dashboardsCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_dashboards metric for the provisioned dashboards of the General folder", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(dashboardsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "", "General", "true"),
+			)))
+		})
+
+		It("returns a grafana_dashboards metric for the manual dashboards of a folder", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(dashboardsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "false"),
+			)))
+		})
+
+		It("returns a grafana_dashboards metric for the dashboards of another org", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(dashboardsDesc, prometheus.GaugeValue, 1, "2", "fake-org", "", "General", "false"),
+			)))
+		})
+
+		It("returns a grafana_dashboards_by_tag metric for every tag", func() {
+			// The tags are reported in map order, so accept them in any order.
+			var received []prometheus.Metric
+			receivedMetrics := func() []prometheus.Metric {
+				for {
+					select {
+					case metric := <-metrics:
+						received = append(received, metric)
+					default:
+						return received
+					}
+				}
+			}
+
+			Eventually(receivedMetrics).Should(And(
+				ContainElement(PrometheusMetric(
+					prometheus.MustNewConstMetric(dashboardsByTagDesc, prometheus.GaugeValue, 2, "1", "Main Org.", "fake-tag-1"),
+				)),
+				ContainElement(PrometheusMetric(
+					prometheus.MustNewConstMetric(dashboardsByTagDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "fake-tag-2"),
+				)),
+			))
+		})
+
+		It("does not return a grafana_dashboard_info metric", func() {
+			Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(infoDesc))))
+		})
+
+		It("returns a grafana_dashboards_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_dashboards_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_dashboards_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the dashboards info is enabled", func() {
+			BeforeEach(func() {
+				infoLimit = 10
+			})
+
+			It("returns a grafana_dashboard_info metric for every dashboard", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "1", "fake-dashboard-1", "Fake Dashboard 1", "", "General", "16"),
+				)))
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "1", "fake-dashboard-2", "Fake Dashboard 2", "fake-folder", "Fake Folder", "27"),
+				)))
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "2", "fake-dashboard-3", "Fake Dashboard 3", "", "General", "36"),
+				)))
+			})
+
+			It("returns a grafana_dashboard_info_truncated metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(infoTruncatedDesc, prometheus.GaugeValue, 0),
+				)))
+			})
+
+			Context("when there are more dashboards than the info limit", func() {
+				BeforeEach(func() {
+					infoLimit = 1
+				})
+
+				It("does not return a grafana_dashboard_info metric for the dashboards over the limit", func() {
+					Consistently(metrics).ShouldNot(Receive(PrometheusMetric(
+						prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "2", "fake-dashboard-3", "Fake Dashboard 3", "", "General", "36"),
+					)))
+				})
+
+				It("returns a grafana_dashboard_info_truncated metric", func() {
+					Eventually(metrics).Should(Receive(PrometheusMetric(
+						prometheus.MustNewConstMetric(infoTruncatedDesc, prometheus.GaugeValue, 1),
+					)))
+				})
+			})
+		})
+
+		Context("when a dashboard is deleted while scraping", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					if uid == "fake-dashboard-2" {
+						return grafana.Dashboard{}, grafana.StatusCodeError{Resource: "dashboard", StatusCode: http.StatusNotFound}
+					}
+					return dashboards[uid], nil
+				}
+			})
+
+			It("does not count the dashboard", func() {
+				Consistently(metrics).ShouldNot(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(dashboardsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "false"),
+				)))
+			})
+
+			It("returns a grafana_dashboards_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the dashboards of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					if orgID == 2 {
+						return grafana.Dashboard{}, errors.New("error")
+					}
+					return dashboards[uid], nil
+				}
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_dashboards metric for the other orgs", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(dashboardsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "", "General", "true"),
+				)))
+			})
+
+			It("returns a grafana_dashboards_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_dashboards_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_dashboards_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_dashboards_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
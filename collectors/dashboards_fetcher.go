@@ -0,0 +1,214 @@
+package collectors
+
+import (
+	"context"
+	"sync"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// dashboardsConcurrency is the maximum number of dashboards requested at once
+// from a Grafana org.
+const dashboardsConcurrency = 8
+
+// DashboardsFetcher fetches the dashboards of the orgs of a Grafana for the
+// collectors walking every dashboard (dashboards, dashboard_activity and
+// panels) or searching them (legacy_alerts and org_stats). The dashboards of
+// an org are searched and requested once per scrape and shared by those
+// collectors, instead of being requested by each of them.
+//
+// A scrape is identified by its context, and its dashboards are forgotten once
+// the context is done. A context that is never done (i.e. a background
+// context) does not identify a scrape, so its dashboards are requested on
+// every call.
+//
+// The dashboards are also kept between scrapes. The search results lack the
+// dashboards version, so the latest version of a kept dashboard is requested
+// instead of the whole dashboard, which is only requested again when it was
+// saved since.
+type DashboardsFetcher struct {
+	grafanaClient grafana.Client
+
+	mtx                 sync.Mutex
+	scrapes             map[context.Context]map[dashboardsFetchKey]*dashboardsFetch
+	orgDashboards       map[int64]map[string]grafana.Dashboard
+	noDashboardVersions bool
+}
+
+type dashboardsFetchKey struct {
+	orgID      int64
+	dashboards bool
+}
+
+type dashboardsFetch struct {
+	done       chan struct{}
+	searchHits []grafana.SearchHit
+	dashboards []grafana.Dashboard
+	err        error
+}
+
+func NewDashboardsFetcher(grafanaClient grafana.Client) *DashboardsFetcher {
+	return &DashboardsFetcher{
+		grafanaClient: grafanaClient,
+		scrapes:       map[context.Context]map[dashboardsFetchKey]*dashboardsFetch{},
+		orgDashboards: map[int64]map[string]grafana.Dashboard{},
+	}
+}
+
+// OrgSearchHits returns the search results of every dashboard of the org,
+// waiting for the search of another collector of the same scrape if there is
+// one. The search results are shared, so they must not be modified.
+func (f *DashboardsFetcher) OrgSearchHits(ctx context.Context, org grafana.Org) ([]grafana.SearchHit, error) {
+	fetch := f.fetch(ctx, dashboardsFetchKey{orgID: org.ID}, func(fetch *dashboardsFetch) {
+		fetch.searchHits, fetch.err = f.grafanaClient.GetDashboards(ctx, org.ID)
+	})
+
+	return fetch.searchHits, fetch.err
+}
+
+// OrgDashboards returns every dashboard of the org, waiting for the fetch of
+// another collector of the same scrape if there is one. The dashboards are
+// shared, so they must not be modified.
+func (f *DashboardsFetcher) OrgDashboards(ctx context.Context, org grafana.Org) ([]grafana.Dashboard, error) {
+	fetch := f.fetch(ctx, dashboardsFetchKey{orgID: org.ID, dashboards: true}, func(fetch *dashboardsFetch) {
+		searchHits, err := f.OrgSearchHits(ctx, org)
+		if err != nil {
+			fetch.err = err
+			return
+		}
+		fetch.dashboards, fetch.err = f.getOrgDashboards(ctx, org, searchHits)
+	})
+
+	return fetch.dashboards, fetch.err
+}
+
+// fetch runs get once per scrape and key, and returns its fetch to every
+// caller of the scrape.
+func (f *DashboardsFetcher) fetch(ctx context.Context, key dashboardsFetchKey, get func(fetch *dashboardsFetch)) *dashboardsFetch {
+	if ctx.Done() == nil {
+		fetch := &dashboardsFetch{}
+		get(fetch)
+		return fetch
+	}
+
+	f.mtx.Lock()
+	fetches, ok := f.scrapes[ctx]
+	if !ok {
+		fetches = map[dashboardsFetchKey]*dashboardsFetch{}
+		f.scrapes[ctx] = fetches
+		go f.forget(ctx)
+	}
+	fetch, ok := fetches[key]
+	if !ok {
+		fetch = &dashboardsFetch{done: make(chan struct{})}
+		fetches[key] = fetch
+	}
+	f.mtx.Unlock()
+
+	if !ok {
+		get(fetch)
+		close(fetch.done)
+	}
+
+	// The fetch is bound to the same context, so it gives up as soon as the
+	// context is done.
+	<-fetch.done
+
+	return fetch
+}
+
+func (f *DashboardsFetcher) forget(ctx context.Context) {
+	<-ctx.Done()
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	delete(f.scrapes, ctx)
+}
+
+// getOrgDashboards returns the dashboards of the search results of the org.
+// The search results lack the dashboards metadata (i.e. whether they are
+// provisioned), so every dashboard is requested, at most dashboardsConcurrency
+// at once, unless it was kept by a previous fetch and was not saved since. The
+// dashboards deleted since the search are left out.
+func (f *DashboardsFetcher) getOrgDashboards(ctx context.Context, org grafana.Org, searchHits []grafana.SearchHit) ([]grafana.Dashboard, error) {
+	f.mtx.Lock()
+	keptDashboards := f.orgDashboards[org.ID]
+	noDashboardVersions := f.noDashboardVersions
+	f.mtx.Unlock()
+
+	dashboards := make([]grafana.Dashboard, len(searchHits))
+	found := make([]bool, len(searchHits))
+	errs := make([]error, len(searchHits))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, dashboardsConcurrency)
+	for i, searchHit := range searchHits {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, searchHit grafana.SearchHit) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			versionsNotFound := false
+			if keptDashboard, ok := keptDashboards[searchHit.UID]; ok && !noDashboardVersions {
+				dashboardVersions, err := f.grafanaClient.GetDashboardVersions(ctx, org.ID, searchHit.UID, 1)
+				if err != nil && !isNotFound(err) {
+					errs[i] = err
+					return
+				}
+				if err == nil && len(dashboardVersions) > 0 && dashboardVersions[0].Version == keptDashboard.Meta.Version {
+					// Renaming a folder does not save its dashboards.
+					keptDashboard.Meta.FolderID = searchHit.FolderID
+					keptDashboard.Meta.FolderUID = searchHit.FolderUID
+					keptDashboard.Meta.FolderTitle = searchHit.FolderTitle
+					dashboards[i] = keptDashboard
+					found[i] = true
+					return
+				}
+				versionsNotFound = err != nil
+			}
+
+			dashboard, err := f.grafanaClient.GetDashboard(ctx, org.ID, searchHit.UID)
+			if err != nil {
+				if !isNotFound(err) {
+					errs[i] = err
+				}
+				return
+			}
+			dashboards[i] = dashboard
+			found[i] = true
+
+			// Grafana versions without the dashboard versions by uid endpoint
+			// request every dashboard on every scrape.
+			if versionsNotFound {
+				f.mtx.Lock()
+				f.noDashboardVersions = true
+				f.mtx.Unlock()
+			}
+		}(i, searchHit)
+	}
+	wg.Wait()
+
+	var orgDashboards []grafana.Dashboard
+	for i := range searchHits {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if found[i] {
+			orgDashboards = append(orgDashboards, dashboards[i])
+		}
+	}
+
+	// Keep the dashboards of the latest fetch only, so the deleted dashboards
+	// are forgotten.
+	keptDashboards = map[string]grafana.Dashboard{}
+	for _, dashboard := range orgDashboards {
+		keptDashboards[dashboard.Dashboard.UID] = dashboard
+	}
+	f.mtx.Lock()
+	f.orgDashboards[org.ID] = keptDashboards
+	f.mtx.Unlock()
+
+	return orgDashboards, nil
+}
@@ -0,0 +1,286 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+)
+
+var _ = Describe("DashboardsFetcher", func() {
+	var (
+		grafanaClient     *grafanafakes.FakeClient
+		dashboardsFetcher *DashboardsFetcher
+		ctx               context.Context
+		cancel            context.CancelFunc
+
+		org      = grafana.Org{ID: 1, Name: "Main Org."}
+		otherOrg = grafana.Org{ID: 2, Name: "fake-org"}
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		grafanaClient.GetDashboardsReturns([]grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil)
+		grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+			return grafana.Dashboard{Dashboard: grafana.DashboardModel{UID: uid}}, nil
+		}
+
+		dashboardsFetcher = NewDashboardsFetcher(grafanaClient)
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("returns every dashboard of the org", func() {
+		dashboards, err := dashboardsFetcher.OrgDashboards(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dashboards).To(Equal([]grafana.Dashboard{
+			{Dashboard: grafana.DashboardModel{UID: "fake-dashboard-1"}},
+			{Dashboard: grafana.DashboardModel{UID: "fake-dashboard-2"}},
+		}))
+	})
+
+	It("requests the dashboards of an org once per scrape", func() {
+		_, err := dashboardsFetcher.OrgDashboards(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+		dashboards, err := dashboardsFetcher.OrgDashboards(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(dashboards).To(HaveLen(2))
+		Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+		Expect(grafanaClient.GetDashboardCallCount()).To(Equal(2))
+	})
+
+	It("requests the dashboards of every org", func() {
+		_, err := dashboardsFetcher.OrgDashboards(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = dashboardsFetcher.OrgDashboards(ctx, otherOrg)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(2))
+		_, orgID := grafanaClient.GetDashboardsArgsForCall(1)
+		Expect(orgID).To(Equal(int64(2)))
+	})
+
+	It("requests the dashboards again on the next scrape", func() {
+		_, err := dashboardsFetcher.OrgDashboards(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+		cancel()
+
+		nextCtx, nextCancel := context.WithCancel(context.Background())
+		defer nextCancel()
+		_, err = dashboardsFetcher.OrgDashboards(nextCtx, org)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(2))
+	})
+
+	It("requests the dashboards on every call without a scrape", func() {
+		_, err := dashboardsFetcher.OrgDashboards(context.Background(), org)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = dashboardsFetcher.OrgDashboards(context.Background(), org)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(2))
+	})
+
+	It("shares the search of the dashboards with the collectors of the scrape", func() {
+		searchHits, err := dashboardsFetcher.OrgSearchHits(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(searchHits).To(Equal([]grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}))
+		_, err = dashboardsFetcher.OrgDashboards(ctx, org)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+	})
+
+	Context("when the dashboards were fetched by a previous scrape", func() {
+		var (
+			nextCtx    context.Context
+			nextCancel context.CancelFunc
+		)
+
+		BeforeEach(func() {
+			grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+				return grafana.Dashboard{Dashboard: grafana.DashboardModel{UID: uid}, Meta: grafana.DashboardMeta{Version: 3}}, nil
+			}
+			grafanaClient.GetDashboardVersionsReturns(grafana.DashboardVersions{{Version: 3}}, nil)
+
+			_, err := dashboardsFetcher.OrgDashboards(ctx, org)
+			Expect(err).ToNot(HaveOccurred())
+			cancel()
+
+			nextCtx, nextCancel = context.WithCancel(context.Background())
+		})
+
+		AfterEach(func() {
+			nextCancel()
+		})
+
+		It("only requests the latest version of the dashboards", func() {
+			dashboards, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dashboards).To(HaveLen(2))
+
+			Expect(grafanaClient.GetDashboardCallCount()).To(Equal(2))
+			Expect(grafanaClient.GetDashboardVersionsCallCount()).To(Equal(2))
+			_, orgID, uid, limit := grafanaClient.GetDashboardVersionsArgsForCall(0)
+			Expect(orgID).To(Equal(int64(1)))
+			Expect(uid).To(HavePrefix("fake-dashboard-"))
+			Expect(limit).To(Equal(1))
+		})
+
+		It("takes the folder of the dashboards from the search", func() {
+			grafanaClient.GetDashboardsReturns([]grafana.SearchHit{{UID: "fake-dashboard-1", FolderID: 4, FolderUID: "fake-folder", FolderTitle: "Fake Folder"}}, nil)
+
+			dashboards, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dashboards).To(Equal([]grafana.Dashboard{
+				{
+					Dashboard: grafana.DashboardModel{UID: "fake-dashboard-1"},
+					Meta:      grafana.DashboardMeta{FolderID: 4, FolderUID: "fake-folder", FolderTitle: "Fake Folder", Version: 3},
+				},
+			}))
+		})
+
+		Context("when a dashboard was saved since", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardVersionsStub = func(ctx context.Context, orgID int64, uid string, limit int) (grafana.DashboardVersions, error) {
+					if uid == "fake-dashboard-1" {
+						return grafana.DashboardVersions{{Version: 4}}, nil
+					}
+					return grafana.DashboardVersions{{Version: 3}}, nil
+				}
+			})
+
+			It("requests the dashboard again", func() {
+				_, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(grafanaClient.GetDashboardCallCount()).To(Equal(3))
+				_, _, uid := grafanaClient.GetDashboardArgsForCall(2)
+				Expect(uid).To(Equal("fake-dashboard-1"))
+			})
+		})
+
+		Context("when a dashboard was deleted since", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardsReturns([]grafana.SearchHit{{UID: "fake-dashboard-2"}}, nil)
+				_, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).ToNot(HaveOccurred())
+				nextCancel()
+
+				nextCtx, nextCancel = context.WithCancel(context.Background())
+				grafanaClient.GetDashboardsReturns([]grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil)
+			})
+
+			It("forgets the dashboard", func() {
+				_, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(grafanaClient.GetDashboardCallCount()).To(Equal(3))
+				_, _, uid := grafanaClient.GetDashboardArgsForCall(2)
+				Expect(uid).To(Equal("fake-dashboard-1"))
+			})
+		})
+
+		Context("when the dashboard versions are not found", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardVersionsReturns(nil, grafana.StatusCodeError{Resource: "dashboard versions", StatusCode: http.StatusNotFound})
+			})
+
+			It("requests the dashboards again", func() {
+				dashboards, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dashboards).To(HaveLen(2))
+
+				Expect(grafanaClient.GetDashboardCallCount()).To(Equal(4))
+			})
+
+			It("stops requesting the latest version of the dashboards", func() {
+				_, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).ToNot(HaveOccurred())
+				nextCancel()
+
+				nextCtx, nextCancel = context.WithCancel(context.Background())
+				_, err = dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(grafanaClient.GetDashboardVersionsCallCount()).To(Equal(2))
+				Expect(grafanaClient.GetDashboardCallCount()).To(Equal(6))
+			})
+		})
+
+		Context("when the latest version of a dashboard cannot be requested", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardVersionsReturns(nil, errors.New("fake-error"))
+			})
+
+			It("returns the error", func() {
+				_, err := dashboardsFetcher.OrgDashboards(nextCtx, org)
+				Expect(err).To(MatchError("fake-error"))
+			})
+		})
+	})
+
+	Context("when the dashboards are requested concurrently", func() {
+		var (
+			searching chan struct{}
+			release   chan struct{}
+		)
+
+		BeforeEach(func() {
+			searching = make(chan struct{}, 2)
+			release = make(chan struct{})
+			grafanaClient.GetDashboardsStub = func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+				searching <- struct{}{}
+				<-release
+				return []grafana.SearchHit{{UID: "fake-dashboard-1"}}, nil
+			}
+		})
+
+		It("shares a single request between the callers", func() {
+			results := make(chan []grafana.Dashboard, 2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					defer GinkgoRecover()
+					dashboards, err := dashboardsFetcher.OrgDashboards(ctx, org)
+					Expect(err).ToNot(HaveOccurred())
+					results <- dashboards
+				}()
+			}
+
+			Eventually(searching).Should(Receive())
+			Consistently(searching).ShouldNot(Receive())
+			close(release)
+
+			Eventually(results).Should(Receive(HaveLen(1)))
+			Eventually(results).Should(Receive(HaveLen(1)))
+			Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the dashboards cannot be requested", func() {
+		BeforeEach(func() {
+			grafanaClient.GetDashboardsReturns(nil, errors.New("fake-error"))
+		})
+
+		It("returns the error to every caller of the scrape", func() {
+			_, err := dashboardsFetcher.OrgDashboards(ctx, org)
+			Expect(err).To(MatchError("fake-error"))
+			_, err = dashboardsFetcher.OrgDashboards(ctx, org)
+			Expect(err).To(MatchError("fake-error"))
+
+			Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+		})
+	})
+})
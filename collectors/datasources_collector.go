@@ -0,0 +1,307 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// datasourceHealthCheck holds the result of the last health check of a
+// datasource, so the health checks are not run on every scrape.
+type datasourceHealthCheck struct {
+	up              float64
+	durationSeconds float64
+	checkedAt       time.Time
+}
+
+// orgDatasource is a datasource along with its org, as the datasources uids
+// are only unique within an org.
+type orgDatasource struct {
+	org        grafana.Org
+	datasource grafana.Datasource
+}
+
+func (d orgDatasource) key() string {
+	return strconv.FormatInt(d.org.ID, 10) + "/" + d.datasource.UID
+}
+
+type DatasourcesCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	healthCheckInterval             time.Duration
+	healthChecks                    map[string]datasourceHealthCheck
+	healthChecksMtx                 sync.Mutex
+	infoDesc                        *prometheus.Desc
+	upDesc                          *prometheus.Desc
+	healthCheckDurationSecondsDesc  *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewDatasourcesCollector returns a collector of the datasources of the orgs
+// matching the org filter. The datasources health checks are run at most once
+// per health check interval, and are disabled if it is 0.
+func NewDatasourcesCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, healthCheckInterval time.Duration) *DatasourcesCollector {
+	infoDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "datasource", "info"),
+		"Grafana Datasource information.",
+		[]string{"uid", "name", "type", "org_id", "org_name", "is_default", "access"},
+		constLabels,
+	)
+
+	upDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "datasource", "up"),
+		"Whether the last health check of the Grafana Datasource was successful (1 for success, 0 for failure).",
+		[]string{"uid", "name", "type", "org_id", "org_name"},
+		constLabels,
+	)
+
+	healthCheckDurationSecondsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "datasource", "health_check_duration_seconds"),
+		"Duration of the last health check of the Grafana Datasource.",
+		[]string{"uid", "name", "type", "org_id", "org_name"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "datasource",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Datasources scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "datasource",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Datasources scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "datasource",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Datasources scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "datasource",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Datasources resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "datasource",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Datasources.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "datasource",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Datasources.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	datasourcesCollector := &DatasourcesCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		healthCheckInterval:             healthCheckInterval,
+		healthChecks:                    map[string]datasourceHealthCheck{},
+		infoDesc:                        infoDesc,
+		upDesc:                          upDesc,
+		healthCheckDurationSecondsDesc:  healthCheckDurationSecondsDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return datasourcesCollector
+}
+
+func (c *DatasourcesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.upDesc
+	ch <- c.healthCheckDurationSecondsDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *DatasourcesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *DatasourcesCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportDatasourcesMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Datasources metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Datasources metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *DatasourcesCollector) reportDatasourcesMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var datasources []orgDatasource
+	err := forEachOrg(ctx, c.grafanaClient, c.orgFilter, "datasources", func(org grafana.Org) error {
+		orgDatasources, err := c.grafanaClient.GetDatasources(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+
+		orgID := strconv.FormatInt(org.ID, 10)
+		for _, datasource := range orgDatasources {
+			ch <- prometheus.MustNewConstMetric(
+				c.infoDesc,
+				prometheus.GaugeValue,
+				1,
+				datasource.UID,
+				datasource.Name,
+				datasource.Type,
+				orgID,
+				org.Name,
+				strconv.FormatBool(datasource.IsDefault),
+				datasource.Access,
+			)
+			datasources = append(datasources, orgDatasource{org: org, datasource: datasource})
+		}
+
+		return nil
+	})
+	if c.healthCheckInterval == 0 || (err != nil && ctx.Err() != nil) {
+		return err
+	}
+
+	healthChecks, healthCheckErr := c.checkDatasourcesHealth(ctx, datasources)
+	for _, datasource := range datasources {
+		healthCheck, ok := healthChecks[datasource.key()]
+		if !ok {
+			continue
+		}
+
+		orgID := strconv.FormatInt(datasource.org.ID, 10)
+		labelValues := []string{datasource.datasource.UID, datasource.datasource.Name, datasource.datasource.Type, orgID, datasource.org.Name}
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, healthCheck.up, labelValues...)
+		ch <- prometheus.MustNewConstMetric(c.healthCheckDurationSecondsDesc, prometheus.GaugeValue, healthCheck.durationSeconds, labelValues...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return healthCheckErr
+}
+
+// checkDatasourcesHealth runs the health checks older than the health check
+// interval, and returns the last health check of every datasource that
+// supports them. The health checks of the deleted datasources are dropped,
+// and the previous ones are kept when the scrape times out.
+func (c *DatasourcesCollector) checkDatasourcesHealth(ctx context.Context, datasources []orgDatasource) (map[string]datasourceHealthCheck, error) {
+	c.healthChecksMtx.Lock()
+	defer c.healthChecksMtx.Unlock()
+
+	healthChecks := map[string]datasourceHealthCheck{}
+	defer func() { c.healthChecks = healthChecks }()
+
+	for _, orgDatasource := range datasources {
+		datasource := orgDatasource.datasource
+		key := orgDatasource.key()
+		healthCheck, ok := c.healthChecks[key]
+		if ok && (time.Since(healthCheck.checkedAt) < c.healthCheckInterval || ctx.Err() != nil) {
+			healthChecks[key] = healthCheck
+			continue
+		}
+		if ctx.Err() != nil {
+			continue
+		}
+
+		begun := time.Now()
+		datasourceHealth, err := c.grafanaClient.GetDatasourceHealth(ctx, orgDatasource.org.ID, datasource.UID)
+		if err != nil {
+			if ctx.Err() != nil {
+				if ok {
+					healthChecks[key] = healthCheck
+				}
+				continue
+			}
+			// The datasources whose plugin does not implement a health check
+			// are not reported.
+			if isNotFound(err) {
+				continue
+			}
+			log.Errorf("Error checking health of Grafana Datasource `%s` of org `%s`: %s", datasource.Name, orgDatasource.org.Name, err)
+		} else if datasourceHealth.Status != "OK" {
+			log.Debugf("Grafana Datasource `%s` health check status: %s", datasource.Name, datasourceHealth.Status)
+		}
+
+		up := float64(0)
+		if err == nil && datasourceHealth.Status == "OK" {
+			up = 1
+		}
+
+		healthChecks[key] = datasourceHealthCheck{
+			up:              up,
+			durationSeconds: time.Since(begun).Seconds(),
+			checkedAt:       begun,
+		}
+	}
+
+	return healthChecks, ctx.Err()
+}
@@ -0,0 +1,402 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("DatasourcesCollector", func() {
+	var (
+		grafanaClient       *grafanafakes.FakeClient
+		constLabels         prometheus.Labels
+		orgFilter           OrgFilter
+		healthCheckInterval time.Duration
+
+		infoDesc                        *prometheus.Desc
+		upDesc                          *prometheus.Desc
+		healthCheckDurationSecondsDesc  *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		datasourcesCollector *DatasourcesCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		healthCheckInterval = 0
+
+		infoDesc = prometheus.NewDesc(
+			"grafana_datasource_info",
+			"Grafana Datasource information.",
+			[]string{"uid", "name", "type", "org_id", "org_name", "is_default", "access"},
+			constLabels,
+		)
+
+		upDesc = prometheus.NewDesc(
+			"grafana_datasource_up",
+			"Whether the last health check of the Grafana Datasource was successful (1 for success, 0 for failure).",
+			[]string{"uid", "name", "type", "org_id", "org_name"},
+			constLabels,
+		)
+
+		healthCheckDurationSecondsDesc = prometheus.NewDesc(
+			"grafana_datasource_health_check_duration_seconds",
+			"Duration of the last health check of the Grafana Datasource.",
+			[]string{"uid", "name", "type", "org_id", "org_name"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "datasource",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Datasources scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "datasource",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Datasources scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "datasource",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Datasources scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "datasource",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Datasources resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "datasource",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Datasources.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "datasource",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Datasources.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		datasourcesCollector = NewDatasourcesCollector(grafanaClient, constLabels, orgFilter, healthCheckInterval)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go datasourcesCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_datasource_info metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(infoDesc)))
+		})
+
+		It("returns a grafana_datasource_up metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(upDesc)))
+		})
+
+		It("returns a grafana_datasource_health_check_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(healthCheckDurationSecondsDesc)))
+		})
+
+		It("returns a grafana_datasource_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_datasource_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_datasource_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_datasource_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_datasource_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_datasource_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			prometheusInfoMetric      prometheus.Metric
+			lokiInfoMetric            prometheus.Metric
+			otherPrometheusInfoMetric prometheus.Metric
+			otherPrometheusUpMetric   prometheus.Metric
+			prometheusUpMetric        prometheus.Metric
+			lokiUpMetric              prometheus.Metric
+
+			metricDesc = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+		)
+
+		BeforeEach(func() {
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetDatasourcesStub = func(ctx context.Context, orgID int64) ([]grafana.Datasource, error) {
+				if orgID == 2 {
+					return []grafana.Datasource{
+						{UID: "fake-prometheus-uid", OrgID: 2, Name: "fake-other-prometheus", Type: "prometheus", Access: "proxy", IsDefault: true},
+					}, nil
+				}
+				return []grafana.Datasource{
+					{UID: "fake-prometheus-uid", OrgID: 1, Name: "fake-prometheus", Type: "prometheus", Access: "proxy", IsDefault: true},
+					{UID: "fake-loki-uid", OrgID: 1, Name: "fake-loki", Type: "loki", Access: "proxy"},
+				}, nil
+			}
+			grafanaClient.GetDatasourceHealthStub = func(ctx context.Context, orgID int64, uid string) (grafana.DatasourceHealth, error) {
+				if uid == "fake-prometheus-uid" && orgID == 1 {
+					return grafana.DatasourceHealth{Status: "OK"}, nil
+				}
+				return grafana.DatasourceHealth{Status: "ERROR"}, nil
+			}
+
+			prometheusInfoMetric = prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "fake-prometheus-uid", "fake-prometheus", "prometheus", "1", "Main Org.", "true", "proxy")
+			lokiInfoMetric = prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "fake-loki-uid", "fake-loki", "loki", "1", "Main Org.", "false", "proxy")
+			otherPrometheusInfoMetric = prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, "fake-prometheus-uid", "fake-other-prometheus", "prometheus", "2", "fake-org", "true", "proxy")
+			prometheusUpMetric = prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1, "fake-prometheus-uid", "fake-prometheus", "prometheus", "1", "Main Org.")
+			lokiUpMetric = prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0, "fake-loki-uid", "fake-loki", "loki", "1", "Main Org.")
+			otherPrometheusUpMetric = prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0, "fake-prometheus-uid", "fake-other-prometheus", "prometheus", "2", "fake-org")
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go datasourcesCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_datasource_info metric for every datasource", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(prometheusInfoMetric)))
+			Eventually(metrics).Should(Receive(PrometheusMetric(lokiInfoMetric)))
+		})
+
+		It("returns a grafana_datasource_info metric for the datasources of every org", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(otherPrometheusInfoMetric)))
+		})
+
+		It("gets the datasources of every org", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+			Expect(grafanaClient.GetDatasourcesCallCount()).To(Equal(2))
+			_, orgID := grafanaClient.GetDatasourcesArgsForCall(1)
+			Expect(orgID).To(Equal(int64(2)))
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter = OrgFilter{Exclude: []string{"fake-org"}}
+			})
+
+			It("does not get the datasources of the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetDatasourcesCallCount()).To(Equal(1))
+				_, orgID := grafanaClient.GetDatasourcesArgsForCall(0)
+				Expect(orgID).To(Equal(int64(1)))
+			})
+		})
+
+		It("does not check the datasources health", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+			Expect(grafanaClient.GetDatasourceHealthCallCount()).To(Equal(0))
+		})
+
+		It("returns a grafana_datasource_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_datasource_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_datasource_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the health checks are enabled", func() {
+			BeforeEach(func() {
+				healthCheckInterval = time.Hour
+			})
+
+			It("checks the health of every datasource in its org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetDatasourceHealthCallCount()).To(Equal(3))
+				_, orgID, uid := grafanaClient.GetDatasourceHealthArgsForCall(0)
+				Expect(orgID).To(Equal(int64(1)))
+				Expect(uid).To(Equal("fake-prometheus-uid"))
+				_, orgID, uid = grafanaClient.GetDatasourceHealthArgsForCall(2)
+				Expect(orgID).To(Equal(int64(2)))
+				Expect(uid).To(Equal("fake-prometheus-uid"))
+			})
+
+			It("returns a grafana_datasource_up metric for the datasources with the same uid in another org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(otherPrometheusUpMetric)))
+			})
+
+			It("returns a grafana_datasource_up metric for a healthy datasource", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(prometheusUpMetric)))
+			})
+
+			It("returns a grafana_datasource_up metric for an unhealthy datasource", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lokiUpMetric)))
+			})
+
+			It("returns a grafana_datasource_health_check_duration_seconds metric", func() {
+				Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(healthCheckDurationSecondsDesc))))
+			})
+
+			It("does not check the datasources health again before the health check interval", func() {
+				Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+
+				go datasourcesCollector.CollectContext(ctx, metrics)
+				Eventually(metrics).Should(Receive(PrometheusMetric(prometheusUpMetric)))
+				Expect(grafanaClient.GetDatasourceHealthCallCount()).To(Equal(3))
+			})
+
+			Context("when a datasource does not implement a health check", func() {
+				BeforeEach(func() {
+					grafanaClient.GetDatasourceHealthStub = func(ctx context.Context, orgID int64, uid string) (grafana.DatasourceHealth, error) {
+						if uid == "fake-prometheus-uid" && orgID == 1 {
+							return grafana.DatasourceHealth{Status: "OK"}, nil
+						}
+						return grafana.DatasourceHealth{}, grafana.StatusCodeError{Resource: "datasource health", StatusCode: http.StatusNotFound}
+					}
+				})
+
+				It("does not return a grafana_datasource_up metric for the datasource", func() {
+					Consistently(metrics).ShouldNot(Receive(PrometheusMetric(lokiUpMetric)))
+				})
+
+				It("returns a grafana_datasource_up metric for the other datasources", func() {
+					Eventually(metrics).Should(Receive(PrometheusMetric(prometheusUpMetric)))
+				})
+			})
+
+			Context("when it fails to check the health of a datasource", func() {
+				BeforeEach(func() {
+					grafanaClient.GetDatasourceHealthStub = func(ctx context.Context, orgID int64, uid string) (grafana.DatasourceHealth, error) {
+						if uid == "fake-prometheus-uid" && orgID == 1 {
+							return grafana.DatasourceHealth{Status: "OK"}, nil
+						}
+						return grafana.DatasourceHealth{}, errors.New("error")
+					}
+				})
+
+				It("returns a grafana_datasource_up metric for the datasource", func() {
+					Eventually(metrics).Should(Receive(PrometheusMetric(lokiUpMetric)))
+				})
+
+				It("returns a grafana_datasource_last_scrape_error metric", func() {
+					Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+				})
+			})
+		})
+
+		Context("when the scrape times out", func() {
+			var (
+				cancel context.CancelFunc
+			)
+
+			BeforeEach(func() {
+				ctx, cancel = context.WithDeadline(context.Background(), time.Now())
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeTimeoutsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("returns a grafana_datasource_scrape_timeouts_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeTimeoutsTotalMetric)))
+			})
+
+			It("returns a grafana_datasource_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the datasources", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDatasourcesReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_datasource_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_datasource_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
@@ -0,0 +1,234 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+type HealthCollector struct {
+	grafanaClient                   grafana.Client
+	buildInfoDesc                   *prometheus.Desc
+	databaseOKDesc                  *prometheus.Desc
+	featureToggleEnabledDesc        *prometheus.Desc
+	licenseInfoDesc                 *prometheus.Desc
+	licenseExpiryDesc               *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewHealthCollector returns a collector of the build, the database health,
+// the feature toggles and the license of the scraped Grafana.
+func NewHealthCollector(grafanaClient grafana.Client, constLabels prometheus.Labels) *HealthCollector {
+	buildInfoDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "", "build_info"),
+		"Grafana build information.",
+		[]string{"version", "commit", "edition"},
+		constLabels,
+	)
+
+	databaseOKDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "database", "ok"),
+		"Whether the Grafana database is ok (1 for ok, 0 for failing).",
+		nil,
+		constLabels,
+	)
+
+	featureToggleEnabledDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "feature_toggle", "enabled"),
+		"Whether the Grafana feature toggle is enabled (1 for enabled, 0 for disabled).",
+		[]string{"feature"},
+		constLabels,
+	)
+
+	licenseInfoDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "license", "info"),
+		"Grafana license information.",
+		[]string{"has_license", "state"},
+		constLabels,
+	)
+
+	licenseExpiryDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "license", "expiry_timestamp_seconds"),
+		"Number of seconds since 1970 until the expiry of the Grafana license.",
+		nil,
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "health",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Health scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "health",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Health scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "health",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Health scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "health",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Health resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "health",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Health.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "health",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Health.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	healthCollector := &HealthCollector{
+		grafanaClient:                   grafanaClient,
+		buildInfoDesc:                   buildInfoDesc,
+		databaseOKDesc:                  databaseOKDesc,
+		featureToggleEnabledDesc:        featureToggleEnabledDesc,
+		licenseInfoDesc:                 licenseInfoDesc,
+		licenseExpiryDesc:               licenseExpiryDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return healthCollector
+}
+
+func (c *HealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.buildInfoDesc
+	ch <- c.databaseOKDesc
+	ch <- c.featureToggleEnabledDesc
+	ch <- c.licenseInfoDesc
+	ch <- c.licenseExpiryDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *HealthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *HealthCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportHealthMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Health metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Health metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *HealthCollector) reportHealthMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	health, err := c.grafanaClient.GetHealth(ctx)
+	if err != nil {
+		return err
+	}
+
+	databaseOK := float64(0)
+	if health.Database == "ok" {
+		databaseOK = float64(1)
+	}
+	ch <- prometheus.MustNewConstMetric(c.databaseOKDesc, prometheus.GaugeValue, databaseOK)
+
+	frontendSettings, err := c.grafanaClient.GetFrontendSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The health lacks the edition, but is the only source of the version
+	// when the frontend settings hide it.
+	buildInfo := frontendSettings.BuildInfo
+	if buildInfo.Version == "" {
+		buildInfo.Version = health.Version
+		buildInfo.Commit = health.Commit
+	}
+	ch <- prometheus.MustNewConstMetric(c.buildInfoDesc, prometheus.GaugeValue, 1, buildInfo.Version, buildInfo.Commit, buildInfo.Edition)
+
+	for feature, enabled := range frontendSettings.FeatureToggles {
+		featureToggleEnabled := float64(0)
+		if enabled {
+			featureToggleEnabled = float64(1)
+		}
+		ch <- prometheus.MustNewConstMetric(c.featureToggleEnabledDesc, prometheus.GaugeValue, featureToggleEnabled, feature)
+	}
+
+	licenseInfo := frontendSettings.LicenseInfo
+	ch <- prometheus.MustNewConstMetric(c.licenseInfoDesc, prometheus.GaugeValue, 1, strconv.FormatBool(licenseInfo.HasLicense), licenseInfo.StateInfo)
+	if licenseInfo.Expiry > 0 {
+		ch <- prometheus.MustNewConstMetric(c.licenseExpiryDesc, prometheus.GaugeValue, float64(licenseInfo.Expiry))
+	}
+
+	return nil
+}
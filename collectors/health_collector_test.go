@@ -0,0 +1,336 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("HealthCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+
+		buildInfoDesc                   *prometheus.Desc
+		databaseOKDesc                  *prometheus.Desc
+		featureToggleEnabledDesc        *prometheus.Desc
+		licenseInfoDesc                 *prometheus.Desc
+		licenseExpiryDesc               *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		healthCollector *HealthCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+
+		buildInfoDesc = prometheus.NewDesc(
+			"grafana_build_info",
+			"Grafana build information.",
+			[]string{"version", "commit", "edition"},
+			constLabels,
+		)
+
+		databaseOKDesc = prometheus.NewDesc(
+			"grafana_database_ok",
+			"Whether the Grafana database is ok (1 for ok, 0 for failing).",
+			nil,
+			constLabels,
+		)
+
+		featureToggleEnabledDesc = prometheus.NewDesc(
+			"grafana_feature_toggle_enabled",
+			"Whether the Grafana feature toggle is enabled (1 for enabled, 0 for disabled).",
+			[]string{"feature"},
+			constLabels,
+		)
+
+		licenseInfoDesc = prometheus.NewDesc(
+			"grafana_license_info",
+			"Grafana license information.",
+			[]string{"has_license", "state"},
+			constLabels,
+		)
+
+		licenseExpiryDesc = prometheus.NewDesc(
+			"grafana_license_expiry_timestamp_seconds",
+			"Number of seconds since 1970 until the expiry of the Grafana license.",
+			nil,
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "health",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Health scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "health",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Health scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "health",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Health scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "health",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Health resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "health",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Health.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "health",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Health.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		healthCollector = NewHealthCollector(grafanaClient, constLabels)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go healthCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_build_info metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(buildInfoDesc)))
+		})
+
+		It("returns a grafana_database_ok metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(databaseOKDesc)))
+		})
+
+		It("returns a grafana_feature_toggle_enabled metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(featureToggleEnabledDesc)))
+		})
+
+		It("returns a grafana_license_info metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(licenseInfoDesc)))
+		})
+
+		It("returns a grafana_license_expiry_timestamp_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(licenseExpiryDesc)))
+		})
+
+		It("returns a grafana_health_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_health_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_health_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_health_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_health_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_health_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			metricDesc  = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+			constMetric = func(desc *prometheus.Desc, value float64, labelValues ...string) OmegaMatcher {
+				return And(
+					WithTransform(metricDesc, Equal(desc)),
+					PrometheusMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)),
+				)
+			}
+		)
+
+		BeforeEach(func() {
+			grafanaClient.GetHealthReturns(grafana.Health{Commit: "fake-commit", Database: "ok", Version: "9.3.2"}, nil)
+			grafanaClient.GetFrontendSettingsReturns(grafana.FrontendSettings{
+				BuildInfo:      grafana.BuildInfo{Version: "9.3.2", Commit: "fake-commit", Edition: "Enterprise"},
+				FeatureToggles: map[string]bool{"publicDashboards": true, "topnav": false},
+				LicenseInfo:    grafana.LicenseInfo{HasLicense: true, Expiry: 1483326245, StateInfo: "Valid"},
+			}, nil)
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go healthCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_build_info metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(buildInfoDesc, 1, "9.3.2", "fake-commit", "Enterprise")))
+		})
+
+		It("returns a grafana_database_ok metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(databaseOKDesc, 1)))
+		})
+
+		It("returns a grafana_feature_toggle_enabled metric for the enabled feature toggles", func() {
+			Eventually(metrics).Should(Receive(constMetric(featureToggleEnabledDesc, 1, "publicDashboards")))
+		})
+
+		It("returns a grafana_feature_toggle_enabled metric for the disabled feature toggles", func() {
+			Eventually(metrics).Should(Receive(constMetric(featureToggleEnabledDesc, 0, "topnav")))
+		})
+
+		It("returns a grafana_license_info metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(licenseInfoDesc, 1, "true", "Valid")))
+		})
+
+		It("returns a grafana_license_expiry_timestamp_seconds metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(licenseExpiryDesc, 1483326245)))
+		})
+
+		It("returns a grafana_health_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_health_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_health_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the database is failing", func() {
+			BeforeEach(func() {
+				grafanaClient.GetHealthReturns(grafana.Health{Database: "failing"}, nil)
+			})
+
+			It("returns a grafana_database_ok metric", func() {
+				Eventually(metrics).Should(Receive(constMetric(databaseOKDesc, 0)))
+			})
+		})
+
+		Context("when the frontend settings hide the version", func() {
+			BeforeEach(func() {
+				grafanaClient.GetFrontendSettingsReturns(grafana.FrontendSettings{BuildInfo: grafana.BuildInfo{Edition: "Open Source"}}, nil)
+			})
+
+			It("returns a grafana_build_info metric with the version of the health", func() {
+				Eventually(metrics).Should(Receive(constMetric(buildInfoDesc, 1, "9.3.2", "fake-commit", "Open Source")))
+			})
+		})
+
+		Context("when the Grafana has no license", func() {
+			BeforeEach(func() {
+				grafanaClient.GetFrontendSettingsReturns(grafana.FrontendSettings{}, nil)
+			})
+
+			It("does not return a grafana_license_expiry_timestamp_seconds metric", func() {
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(licenseExpiryDesc))))
+			})
+		})
+
+		Context("when it fails to get the frontend settings", func() {
+			BeforeEach(func() {
+				grafanaClient.GetFrontendSettingsReturns(grafana.FrontendSettings{}, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_database_ok metric", func() {
+				Eventually(metrics).Should(Receive(constMetric(databaseOKDesc, 1)))
+			})
+
+			It("returns a grafana_health_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_health_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the health", func() {
+			BeforeEach(func() {
+				grafanaClient.GetHealthReturns(grafana.Health{}, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_health_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_health_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
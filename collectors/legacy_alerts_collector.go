@@ -0,0 +1,281 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// legacyAlertStates are the legacy alert states always reported for the
+// dashboards with alerts, even when no alert is in the state.
+var legacyAlertStates = []string{"ok", "alerting", "no_data", "paused", "pending"}
+
+type legacyAlertRulesKey struct {
+	folderUID      string
+	folderTitle    string
+	dashboardUID   string
+	dashboardTitle string
+	state          string
+}
+
+type LegacyAlertsCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	dashboardsFetcher               *DashboardsFetcher
+	alertRulesDesc                  *prometheus.Desc
+	alertRuleStateDesc              *prometheus.Desc
+	alertRuleStateDurationDesc      *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewLegacyAlertsCollector returns a collector of the state of the legacy
+// dashboard alerts of the orgs matching the org filter.
+func NewLegacyAlertsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, dashboardsFetcher *DashboardsFetcher) *LegacyAlertsCollector {
+	alertRulesDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "", "alert_rules"),
+		"Number of Grafana Legacy Alert Rules in the state.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "dashboard_uid", "dashboard_title", "state"},
+		constLabels,
+	)
+
+	alertRuleStateDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alert_rule", "state"),
+		"State of the Grafana Legacy Alert Rule.",
+		[]string{"org_id", "id", "name", "dashboard_uid", "panel_id", "state"},
+		constLabels,
+	)
+
+	alertRuleStateDurationDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "alert_rule", "state_duration_seconds"),
+		"Number of seconds since the Grafana Legacy Alert Rule entered its state.",
+		[]string{"org_id", "id", "name", "dashboard_uid", "panel_id"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "legacy_alerts",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Legacy Alerts scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "legacy_alerts",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Legacy Alerts scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "legacy_alerts",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Legacy Alerts scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "legacy_alerts",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Legacy Alerts resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "legacy_alerts",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Legacy Alerts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "legacy_alerts",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Legacy Alerts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	legacyAlertsCollector := &LegacyAlertsCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		dashboardsFetcher:               dashboardsFetcher,
+		alertRulesDesc:                  alertRulesDesc,
+		alertRuleStateDesc:              alertRuleStateDesc,
+		alertRuleStateDurationDesc:      alertRuleStateDurationDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return legacyAlertsCollector
+}
+
+func (c *LegacyAlertsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.alertRulesDesc
+	ch <- c.alertRuleStateDesc
+	ch <- c.alertRuleStateDurationDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *LegacyAlertsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *LegacyAlertsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportLegacyAlertsMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Legacy Alerts metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Legacy Alerts metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *LegacyAlertsCollector) reportLegacyAlertsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "legacy alerts", func(org grafana.Org) error {
+		orgMetrics, err := c.orgLegacyAlertsMetrics(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range orgMetrics {
+			ch <- metric
+		}
+
+		return nil
+	})
+}
+
+// orgLegacyAlertsMetrics returns the metrics of the legacy alerts of the org.
+// The alerts lack the folder and the title of their dashboard, which are
+// taken from the dashboards search. Legacy alerting was removed in Grafana
+// 11, so an org without the alerts endpoint has no metrics.
+func (c *LegacyAlertsCollector) orgLegacyAlertsMetrics(ctx context.Context, org grafana.Org) ([]prometheus.Metric, error) {
+	var metrics []prometheus.Metric
+
+	alerts, err := c.grafanaClient.GetAlerts(ctx, org.ID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	searchHits, err := c.dashboardsFetcher.OrgSearchHits(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	dashboards := map[string]grafana.SearchHit{}
+	for _, searchHit := range searchHits {
+		dashboards[searchHit.UID] = searchHit
+	}
+
+	now := time.Now()
+	orgID := strconv.FormatInt(org.ID, 10)
+	alertRules := map[legacyAlertRulesKey]int{}
+	for _, alert := range alerts {
+		dashboard := dashboards[alert.DashboardUID]
+		folderTitle := dashboard.FolderTitle
+		if dashboard.FolderUID == "" {
+			folderTitle = generalFolderTitle
+		}
+
+		key := legacyAlertRulesKey{
+			folderUID:      dashboard.FolderUID,
+			folderTitle:    folderTitle,
+			dashboardUID:   alert.DashboardUID,
+			dashboardTitle: dashboard.Title,
+		}
+		for _, state := range legacyAlertStates {
+			key.state = state
+			if _, ok := alertRules[key]; !ok {
+				alertRules[key] = 0
+			}
+		}
+		key.state = alert.State
+		alertRules[key]++
+
+		id := strconv.FormatInt(alert.ID, 10)
+		panelID := strconv.FormatInt(alert.PanelID, 10)
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.alertRuleStateDesc, prometheus.GaugeValue, 1, orgID, id, alert.Name, alert.DashboardUID, panelID, alert.State))
+		if !alert.NewStateDate.IsZero() {
+			metrics = append(metrics, prometheus.MustNewConstMetric(c.alertRuleStateDurationDesc, prometheus.GaugeValue, now.Sub(alert.NewStateDate).Seconds(), orgID, id, alert.Name, alert.DashboardUID, panelID))
+		}
+	}
+
+	for key, count := range alertRules {
+		metrics = append(metrics, prometheus.MustNewConstMetric(
+			c.alertRulesDesc,
+			prometheus.GaugeValue,
+			float64(count),
+			orgID,
+			org.Name,
+			key.folderUID,
+			key.folderTitle,
+			key.dashboardUID,
+			key.dashboardTitle,
+			key.state,
+		))
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,312 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("LegacyAlertsCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+
+		alertRulesDesc                  *prometheus.Desc
+		alertRuleStateDesc              *prometheus.Desc
+		alertRuleStateDurationDesc      *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		legacyAlertsCollector *LegacyAlertsCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+
+		alertRulesDesc = prometheus.NewDesc(
+			"grafana_alert_rules",
+			"Number of Grafana Legacy Alert Rules in the state.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "dashboard_uid", "dashboard_title", "state"},
+			constLabels,
+		)
+
+		alertRuleStateDesc = prometheus.NewDesc(
+			"grafana_alert_rule_state",
+			"State of the Grafana Legacy Alert Rule.",
+			[]string{"org_id", "id", "name", "dashboard_uid", "panel_id", "state"},
+			constLabels,
+		)
+
+		alertRuleStateDurationDesc = prometheus.NewDesc(
+			"grafana_alert_rule_state_duration_seconds",
+			"Number of seconds since the Grafana Legacy Alert Rule entered its state.",
+			[]string{"org_id", "id", "name", "dashboard_uid", "panel_id"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "legacy_alerts",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Legacy Alerts scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "legacy_alerts",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Legacy Alerts scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "legacy_alerts",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Legacy Alerts scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "legacy_alerts",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Legacy Alerts resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "legacy_alerts",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Legacy Alerts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "legacy_alerts",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Legacy Alerts.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		legacyAlertsCollector = NewLegacyAlertsCollector(grafanaClient, constLabels, orgFilter, NewDashboardsFetcher(grafanaClient))
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go legacyAlertsCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_alert_rules metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(alertRulesDesc)))
+		})
+
+		It("returns a grafana_alert_rule_state metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(alertRuleStateDesc)))
+		})
+
+		It("returns a grafana_alert_rule_state_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(alertRuleStateDurationDesc)))
+		})
+
+		It("returns a grafana_legacy_alerts_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_legacy_alerts_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_legacy_alerts_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_legacy_alerts_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_legacy_alerts_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_legacy_alerts_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			metricDesc  = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+			constMetric = func(desc *prometheus.Desc, value float64, labelValues ...string) OmegaMatcher {
+				return And(
+					WithTransform(metricDesc, Equal(desc)),
+					PrometheusMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)),
+				)
+			}
+		)
+
+		BeforeEach(func() {
+			now := time.Now()
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetAlertsStub = func(ctx context.Context, orgID int64) ([]grafana.Alert, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "alerts", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.Alert{
+					{ID: 1, DashboardUID: "fake-dashboard-1", PanelID: 2, Name: "Fake Alerting Alert", State: "alerting", NewStateDate: now.Add(-2 * 24 * time.Hour)},
+					{ID: 2, DashboardUID: "fake-dashboard-1", PanelID: 3, Name: "Fake No Data Alert", State: "no_data", NewStateDate: now.Add(-3 * 24 * time.Hour)},
+					{ID: 3, DashboardUID: "fake-dashboard-2", PanelID: 1, Name: "Fake Ok Alert", State: "ok"},
+				}, nil
+			}
+			grafanaClient.GetDashboardsReturns([]grafana.SearchHit{
+				{UID: "fake-dashboard-1", Title: "Fake Dashboard 1", FolderUID: "fake-folder", FolderTitle: "Fake Folder"},
+				{UID: "fake-dashboard-2", Title: "Fake Dashboard 2"},
+			}, nil)
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go legacyAlertsCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_alert_rules metric for the alerting rules of a dashboard", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertRulesDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-dashboard-1", "Fake Dashboard 1", "alerting")))
+		})
+
+		It("returns a grafana_alert_rules metric for the no data rules of a dashboard", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertRulesDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-dashboard-1", "Fake Dashboard 1", "no_data")))
+		})
+
+		It("returns a grafana_alert_rules metric for the states without rules", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertRulesDesc, 0, "1", "Main Org.", "fake-folder", "Fake Folder", "fake-dashboard-1", "Fake Dashboard 1", "paused")))
+		})
+
+		It("returns a grafana_alert_rules metric for the rules of a dashboard of the General folder", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertRulesDesc, 1, "1", "Main Org.", "", "General", "fake-dashboard-2", "Fake Dashboard 2", "ok")))
+		})
+
+		It("returns a grafana_alert_rule_state metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(alertRuleStateDesc, 1, "1", "2", "Fake No Data Alert", "fake-dashboard-1", "3", "no_data")))
+		})
+
+		It("returns a grafana_alert_rule_state_duration_seconds metric", func() {
+			Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(alertRuleStateDurationDesc))))
+		})
+
+		It("does not request the dashboards of the orgs without legacy alerting", func() {
+			Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+			Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+		})
+
+		It("returns a grafana_legacy_alerts_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_legacy_alerts_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_legacy_alerts_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetAlertsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the dashboards of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("does not return the metrics of the org", func() {
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(alertRulesDesc))))
+			})
+
+			It("returns a grafana_legacy_alerts_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_legacy_alerts_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_legacy_alerts_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_legacy_alerts_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
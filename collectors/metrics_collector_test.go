@@ -1,8 +1,10 @@
 package collectors_test
 
 import (
+	"context"
 	"errors"
 	"flag"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -22,38 +24,67 @@ func init() {
 var _ = Describe("MetricsCollectors", func() {
 	var (
 		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+
+		legacyMetricNames bool
 
 		alertingActiveAlertsMetric             prometheus.Gauge
-		alertingExecutionTimeMetric            *prometheus.GaugeVec
-		alertingNotificationsSentMetric        *prometheus.GaugeVec
-		alertingResultsMetric                  *prometheus.GaugeVec
-		apiAdminUserCreateMetric               prometheus.Gauge
-		apiDashboardGetMetric                  *prometheus.GaugeVec
-		apiDashboardSaveMetric                 *prometheus.GaugeVec
-		apiDashboardSearchMetric               *prometheus.GaugeVec
-		apiDashboardSnapshotCreateMetric       prometheus.Gauge
-		apiDashboardSnapshotExternalMetric     prometheus.Gauge
-		apiDashboardSnapshotGetMetric          prometheus.Gauge
-		apiDataproxyRequestAllMetric           *prometheus.GaugeVec
-		apiLoginOauthMetric                    prometheus.Gauge
-		apiLoginPostMetric                     prometheus.Gauge
-		apiOrgCreateMetric                     prometheus.Gauge
-		apiResponsesMetric                     *prometheus.GaugeVec
-		apiUserSignupsCompletedMetric          prometheus.Gauge
-		apiUserSignupsInviteMetric             prometheus.Gauge
-		apiUserSignupsStartedMetric            prometheus.Gauge
-		awsCloudwatchGetMetricStatisticsMetric prometheus.Gauge
-		awsCloudwatchListMetricsMetric         prometheus.Gauge
-		instanceStartMetric                    prometheus.Gauge
-		modelsDashboardInsertMetric            prometheus.Gauge
-		pageResponsesMetric                    *prometheus.GaugeVec
-		proxyResponsesMetric                   *prometheus.GaugeVec
+		alertingExecutionTimeCountMetric       prometheus.Metric
+		alertingExecutionTimeP99Metric         prometheus.Metric
+		alertingExecutionTimeMinMetric         prometheus.Gauge
+		alertingExecutionTimeMaxMetric         prometheus.Gauge
+		alertingExecutionTimeMeanMetric        prometheus.Gauge
+		alertingExecutionTimeStddevMetric      prometheus.Gauge
+		alertingNotificationsSentMetric        *prometheus.CounterVec
+		alertingResultsMetric                  *prometheus.CounterVec
+		apiAdminUserCreateMetric               prometheus.Counter
+		apiDashboardGetCountMetric             prometheus.Metric
+		apiDashboardGetP99Metric               prometheus.Metric
+		apiDashboardGetMinMetric               prometheus.Gauge
+		apiDashboardGetMaxMetric               prometheus.Gauge
+		apiDashboardGetMeanMetric              prometheus.Gauge
+		apiDashboardGetStddevMetric            prometheus.Gauge
+		apiDashboardSaveCountMetric            prometheus.Metric
+		apiDashboardSaveP99Metric              prometheus.Metric
+		apiDashboardSaveMinMetric              prometheus.Gauge
+		apiDashboardSaveMaxMetric              prometheus.Gauge
+		apiDashboardSaveMeanMetric             prometheus.Gauge
+		apiDashboardSaveStddevMetric           prometheus.Gauge
+		apiDashboardSearchCountMetric          prometheus.Metric
+		apiDashboardSearchP99Metric            prometheus.Metric
+		apiDashboardSearchMinMetric            prometheus.Gauge
+		apiDashboardSearchMaxMetric            prometheus.Gauge
+		apiDashboardSearchMeanMetric           prometheus.Gauge
+		apiDashboardSearchStddevMetric         prometheus.Gauge
+		apiDashboardSnapshotCreateMetric       prometheus.Counter
+		apiDashboardSnapshotExternalMetric     prometheus.Counter
+		apiDashboardSnapshotGetMetric          prometheus.Counter
+		apiDataproxyRequestAllCountMetric      prometheus.Metric
+		apiDataproxyRequestAllP99Metric        prometheus.Metric
+		apiDataproxyRequestAllMinMetric        prometheus.Gauge
+		apiDataproxyRequestAllMaxMetric        prometheus.Gauge
+		apiDataproxyRequestAllMeanMetric       prometheus.Gauge
+		apiDataproxyRequestAllStddevMetric     prometheus.Gauge
+		apiLoginOauthMetric                    prometheus.Counter
+		apiLoginPostMetric                     prometheus.Counter
+		apiOrgCreateMetric                     prometheus.Counter
+		apiResponsesMetric                     *prometheus.CounterVec
+		apiUserSignupsCompletedMetric          prometheus.Counter
+		apiUserSignupsInviteMetric             prometheus.Counter
+		apiUserSignupsStartedMetric            prometheus.Counter
+		awsCloudwatchGetMetricStatisticsMetric prometheus.Counter
+		awsCloudwatchListMetricsMetric         prometheus.Counter
+		instanceStartMetric                    prometheus.Counter
+		modelsDashboardInsertMetric            prometheus.Counter
+		pageResponsesMetric                    *prometheus.CounterVec
+		proxyResponsesMetric                   *prometheus.CounterVec
 		dashboardsMetric                       prometheus.Gauge
 		orgsMetric                             prometheus.Gauge
 		playlistsMetric                        prometheus.Gauge
 		usersMetric                            prometheus.Gauge
 		scrapesTotalMetric                     prometheus.Counter
 		scrapeErrorsTotalMetric                prometheus.Counter
+		scrapeTimeoutsTotalMetric              prometheus.Counter
 		lastScrapeErrorMetric                  prometheus.Gauge
 		lastScrapeTimestampMetric              prometheus.Gauge
 		lastScrapeDurationSecondsMetric        prometheus.Gauge
@@ -157,421 +188,652 @@ var _ = Describe("MetricsCollectors", func() {
 
 	BeforeEach(func() {
 		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		legacyMetricNames = false
 
 		alertingActiveAlertsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "alerting_active_alerts",
-				Help:      "Number of active alerts.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_active_alerts",
+				Help:        "Number of active alerts.",
+				ConstLabels: constLabels,
 			},
 		)
 		alertingActiveAlertsMetric.Set(float64(alertingActiveAlertsValue))
 
-		alertingExecutionTimeMetric = prometheus.NewGaugeVec(
+		alertingExecutionTimeCountMetric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_alerting_execution_time_seconds_count", "Alerting execution time (number of observations).", nil, constLabels),
+			prometheus.CounterValue,
+			float64(alertingExecutionTimeCount),
+		)
+
+		alertingExecutionTimeP99Metric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_alerting_execution_time_seconds", "Alerting execution time.", []string{"quantile"}, constLabels),
+			prometheus.GaugeValue,
+			alertingExecutionTimeP99/1000,
+			"0.99",
+		)
+
+		alertingExecutionTimeMinMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "alerting_execution_time",
-				Help:      "Alerting execution time.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_execution_time_min_seconds",
+				Help:        "Alerting execution time (minimum).",
+				ConstLabels: constLabels,
 			},
-			[]string{"metric"},
 		)
-		alertingExecutionTimeMetric.WithLabelValues("count").Set(float64(alertingExecutionTimeCount))
-		alertingExecutionTimeMetric.WithLabelValues("max").Set(float64(alertingExecutionTimeMax))
-		alertingExecutionTimeMetric.WithLabelValues("mean").Set(alertingExecutionTimeMean)
-		alertingExecutionTimeMetric.WithLabelValues("min").Set(float64(alertingExecutionTimeMin))
-		alertingExecutionTimeMetric.WithLabelValues("p25").Set(alertingExecutionTimeP25)
-		alertingExecutionTimeMetric.WithLabelValues("p75").Set(alertingExecutionTimeP75)
-		alertingExecutionTimeMetric.WithLabelValues("p90").Set(alertingExecutionTimeP90)
-		alertingExecutionTimeMetric.WithLabelValues("p99").Set(alertingExecutionTimeP99)
-		alertingExecutionTimeMetric.WithLabelValues("std").Set(alertingExecutionTimeStd)
-
-		alertingNotificationsSentMetric = prometheus.NewGaugeVec(
+		alertingExecutionTimeMinMetric.Set(float64(alertingExecutionTimeMin) / 1000)
+
+		alertingExecutionTimeMaxMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "alerting_notifications_sent",
-				Help:      "Number of alert notifications sent.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_execution_time_max_seconds",
+				Help:        "Alerting execution time (maximum).",
+				ConstLabels: constLabels,
 			},
-			[]string{"type"},
 		)
-		alertingNotificationsSentMetric.WithLabelValues("line").Set(float64(alertingNotificationsSentLineCount))
-		alertingNotificationsSentMetric.WithLabelValues("dingding").Set(float64(alertingNotificationsSentDingDingCount))
-		alertingNotificationsSentMetric.WithLabelValues("email").Set(float64(alertingNotificationsSentEmailCount))
-		alertingNotificationsSentMetric.WithLabelValues("opsgenie").Set(float64(alertingNotificationsSentOpsgenieCount))
-		alertingNotificationsSentMetric.WithLabelValues("pagerduty").Set(float64(alertingNotificationsSentPagerdutyCount))
-		alertingNotificationsSentMetric.WithLabelValues("pushover").Set(float64(alertingNotificationsSentPushoverCount))
-		alertingNotificationsSentMetric.WithLabelValues("sensu").Set(float64(alertingNotificationsSentSensuCount))
-		alertingNotificationsSentMetric.WithLabelValues("slack").Set(float64(alertingNotificationsSentSlackCount))
-		alertingNotificationsSentMetric.WithLabelValues("telegram").Set(float64(alertingNotificationsSentTelegramCount))
-		alertingNotificationsSentMetric.WithLabelValues("threema").Set(float64(alertingNotificationsSentThreemaCount))
-		alertingNotificationsSentMetric.WithLabelValues("victorops").Set(float64(alertingNotificationsSentVictoropsCount))
-		alertingNotificationsSentMetric.WithLabelValues("webhook").Set(float64(alertingNotificationsSentWebhookCount))
-
-		alertingResultsMetric = prometheus.NewGaugeVec(
+		alertingExecutionTimeMaxMetric.Set(float64(alertingExecutionTimeMax) / 1000)
+
+		alertingExecutionTimeMeanMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "alerting_results",
-				Help:      "Number of alerting results.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_execution_time_mean_seconds",
+				Help:        "Alerting execution time (mean).",
+				ConstLabels: constLabels,
 			},
-			[]string{"state"},
 		)
-		alertingResultsMetric.WithLabelValues("alerting").Set(float64(alertingResultStateAlertingCount))
-		alertingResultsMetric.WithLabelValues("no_data").Set(float64(alertingResultStateNoDataCount))
-		alertingResultsMetric.WithLabelValues("ok").Set(float64(alertingResultStateOkCount))
-		alertingResultsMetric.WithLabelValues("paused").Set(float64(alertingResultStatePausedCount))
-		alertingResultsMetric.WithLabelValues("pending").Set(float64(alertingResultStatePendingCount))
+		alertingExecutionTimeMeanMetric.Set(alertingExecutionTimeMean / 1000)
 
-		apiAdminUserCreateMetric = prometheus.NewGauge(
+		alertingExecutionTimeStddevMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_admin_user_create",
-				Help:      "Number of calls to Admin User Create API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_execution_time_stddev_seconds",
+				Help:        "Alerting execution time (standard deviation).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiAdminUserCreateMetric.Set(float64(apiAdminUserCreateCount))
+		alertingExecutionTimeStddevMetric.Set(alertingExecutionTimeStd / 1000)
 
-		apiDashboardGetMetric = prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dashboard_get",
-				Help:      "Dashboard Get API times.",
+		alertingNotificationsSentMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_notifications_sent_total",
+				Help:        "Number of alert notifications sent.",
+				ConstLabels: constLabels,
 			},
-			[]string{"metric"},
+			[]string{"type"},
 		)
-		apiDashboardGetMetric.WithLabelValues("count").Set(float64(apiDashboardGetCount))
-		apiDashboardGetMetric.WithLabelValues("max").Set(float64(apiDashboardGetMax))
-		apiDashboardGetMetric.WithLabelValues("mean").Set(apiDashboardGetMean)
-		apiDashboardGetMetric.WithLabelValues("min").Set(float64(apiDashboardGetMin))
-		apiDashboardGetMetric.WithLabelValues("p25").Set(apiDashboardGetP25)
-		apiDashboardGetMetric.WithLabelValues("p75").Set(apiDashboardGetP75)
-		apiDashboardGetMetric.WithLabelValues("p90").Set(apiDashboardGetP90)
-		apiDashboardGetMetric.WithLabelValues("p99").Set(apiDashboardGetP99)
-		apiDashboardGetMetric.WithLabelValues("std").Set(apiDashboardGetStd)
-
-		apiDashboardSaveMetric = prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dashboard_save",
-				Help:      "Dashboard Save API times.",
+		alertingNotificationsSentMetric.WithLabelValues("line").Add(float64(alertingNotificationsSentLineCount))
+		alertingNotificationsSentMetric.WithLabelValues("dingding").Add(float64(alertingNotificationsSentDingDingCount))
+		alertingNotificationsSentMetric.WithLabelValues("email").Add(float64(alertingNotificationsSentEmailCount))
+		alertingNotificationsSentMetric.WithLabelValues("opsgenie").Add(float64(alertingNotificationsSentOpsgenieCount))
+		alertingNotificationsSentMetric.WithLabelValues("pagerduty").Add(float64(alertingNotificationsSentPagerdutyCount))
+		alertingNotificationsSentMetric.WithLabelValues("pushover").Add(float64(alertingNotificationsSentPushoverCount))
+		alertingNotificationsSentMetric.WithLabelValues("sensu").Add(float64(alertingNotificationsSentSensuCount))
+		alertingNotificationsSentMetric.WithLabelValues("slack").Add(float64(alertingNotificationsSentSlackCount))
+		alertingNotificationsSentMetric.WithLabelValues("telegram").Add(float64(alertingNotificationsSentTelegramCount))
+		alertingNotificationsSentMetric.WithLabelValues("threema").Add(float64(alertingNotificationsSentThreemaCount))
+		alertingNotificationsSentMetric.WithLabelValues("victorops").Add(float64(alertingNotificationsSentVictoropsCount))
+		alertingNotificationsSentMetric.WithLabelValues("webhook").Add(float64(alertingNotificationsSentWebhookCount))
+
+		alertingResultsMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "alerting_results_total",
+				Help:        "Number of alerting results.",
+				ConstLabels: constLabels,
 			},
-			[]string{"metric"},
+			[]string{"state"},
 		)
-		apiDashboardSaveMetric.WithLabelValues("count").Set(float64(apiDashboardSaveCount))
-		apiDashboardSaveMetric.WithLabelValues("max").Set(float64(apiDashboardSaveMax))
-		apiDashboardSaveMetric.WithLabelValues("mean").Set(apiDashboardSaveMean)
-		apiDashboardSaveMetric.WithLabelValues("min").Set(float64(apiDashboardSaveMin))
-		apiDashboardSaveMetric.WithLabelValues("p25").Set(apiDashboardSaveP25)
-		apiDashboardSaveMetric.WithLabelValues("p75").Set(apiDashboardSaveP75)
-		apiDashboardSaveMetric.WithLabelValues("p90").Set(apiDashboardSaveP90)
-		apiDashboardSaveMetric.WithLabelValues("p99").Set(apiDashboardSaveP99)
-		apiDashboardSaveMetric.WithLabelValues("std").Set(apiDashboardSaveStd)
-
-		apiDashboardSearchMetric = prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dashboard_search",
-				Help:      "Dashboard Search API times.",
+		alertingResultsMetric.WithLabelValues("alerting").Add(float64(alertingResultStateAlertingCount))
+		alertingResultsMetric.WithLabelValues("no_data").Add(float64(alertingResultStateNoDataCount))
+		alertingResultsMetric.WithLabelValues("ok").Add(float64(alertingResultStateOkCount))
+		alertingResultsMetric.WithLabelValues("paused").Add(float64(alertingResultStatePausedCount))
+		alertingResultsMetric.WithLabelValues("pending").Add(float64(alertingResultStatePendingCount))
+
+		apiAdminUserCreateMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_admin_user_create_total",
+				Help:        "Number of calls to Admin User Create API.",
+				ConstLabels: constLabels,
 			},
-			[]string{"metric"},
 		)
-		apiDashboardSearchMetric.WithLabelValues("count").Set(float64(apiDashboardSearchCount))
-		apiDashboardSearchMetric.WithLabelValues("max").Set(float64(apiDashboardSearchMax))
-		apiDashboardSearchMetric.WithLabelValues("mean").Set(apiDashboardSearchMean)
-		apiDashboardSearchMetric.WithLabelValues("min").Set(float64(apiDashboardSearchMin))
-		apiDashboardSearchMetric.WithLabelValues("p25").Set(apiDashboardSearchP25)
-		apiDashboardSearchMetric.WithLabelValues("p75").Set(apiDashboardSearchP75)
-		apiDashboardSearchMetric.WithLabelValues("p90").Set(apiDashboardSearchP90)
-		apiDashboardSearchMetric.WithLabelValues("p99").Set(apiDashboardSearchP99)
-		apiDashboardSearchMetric.WithLabelValues("std").Set(apiDashboardSearchStd)
-
-		apiDashboardSnapshotCreateMetric = prometheus.NewGauge(
+		apiAdminUserCreateMetric.Add(float64(apiAdminUserCreateCount))
+
+		apiDashboardGetCountMetric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dashboard_get_seconds_count", "Dashboard Get API times (number of observations).", nil, constLabels),
+			prometheus.CounterValue,
+			float64(apiDashboardGetCount),
+		)
+
+		apiDashboardGetP99Metric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dashboard_get_seconds", "Dashboard Get API times.", []string{"quantile"}, constLabels),
+			prometheus.GaugeValue,
+			apiDashboardGetP99/1000,
+			"0.99",
+		)
+
+		apiDashboardGetMinMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dashboard_snapshot_create",
-				Help:      "Number of calls to Dashboard Snapshot Create API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_get_min_seconds",
+				Help:        "Dashboard Get API times (minimum).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiDashboardSnapshotCreateMetric.Set(float64(apiDashboardSnapshotCreateCount))
+		apiDashboardGetMinMetric.Set(float64(apiDashboardGetMin) / 1000)
 
-		apiDashboardSnapshotExternalMetric = prometheus.NewGauge(
+		apiDashboardGetMaxMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dashboard_snapshot_external",
-				Help:      "Number of calls to Dashboard Snapshot External API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_get_max_seconds",
+				Help:        "Dashboard Get API times (maximum).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiDashboardSnapshotExternalMetric.Set(float64(apiDashboardSnapshotExternalCount))
+		apiDashboardGetMaxMetric.Set(float64(apiDashboardGetMax) / 1000)
 
-		apiDashboardSnapshotGetMetric = prometheus.NewGauge(
+		apiDashboardGetMeanMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dashboard_snapshot_get",
-				Help:      "Number of calls to Dashboard Snapshot Get API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_get_mean_seconds",
+				Help:        "Dashboard Get API times (mean).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiDashboardSnapshotGetMetric.Set(float64(apiDashboardSnapshotGetCount))
+		apiDashboardGetMeanMetric.Set(apiDashboardGetMean / 1000)
 
-		apiDataproxyRequestAllMetric = prometheus.NewGaugeVec(
+		apiDashboardGetStddevMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_dataproxy_request_all",
-				Help:      "Dataproxy request API times.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_get_stddev_seconds",
+				Help:        "Dashboard Get API times (standard deviation).",
+				ConstLabels: constLabels,
 			},
-			[]string{"metric"},
 		)
-		apiDataproxyRequestAllMetric.WithLabelValues("count").Set(float64(apiDataproxyRequestAllCount))
-		apiDataproxyRequestAllMetric.WithLabelValues("max").Set(float64(apiDataproxyRequestAllMax))
-		apiDataproxyRequestAllMetric.WithLabelValues("mean").Set(apiDataproxyRequestAllMean)
-		apiDataproxyRequestAllMetric.WithLabelValues("min").Set(float64(apiDataproxyRequestAllMin))
-		apiDataproxyRequestAllMetric.WithLabelValues("p25").Set(apiDataproxyRequestAllP25)
-		apiDataproxyRequestAllMetric.WithLabelValues("p75").Set(apiDataproxyRequestAllP75)
-		apiDataproxyRequestAllMetric.WithLabelValues("p90").Set(apiDataproxyRequestAllP90)
-		apiDataproxyRequestAllMetric.WithLabelValues("p99").Set(apiDataproxyRequestAllP99)
-		apiDataproxyRequestAllMetric.WithLabelValues("std").Set(apiDataproxyRequestAllStd)
-
-		apiLoginOauthMetric = prometheus.NewGauge(
+		apiDashboardGetStddevMetric.Set(apiDashboardGetStd / 1000)
+
+		apiDashboardSaveCountMetric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dashboard_save_seconds_count", "Dashboard Save API times (number of observations).", nil, constLabels),
+			prometheus.CounterValue,
+			float64(apiDashboardSaveCount),
+		)
+
+		apiDashboardSaveP99Metric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dashboard_save_seconds", "Dashboard Save API times.", []string{"quantile"}, constLabels),
+			prometheus.GaugeValue,
+			apiDashboardSaveP99/1000,
+			"0.99",
+		)
+
+		apiDashboardSaveMinMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_login_oauth",
-				Help:      "Number of calls to Login OAuth API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_save_min_seconds",
+				Help:        "Dashboard Save API times (minimum).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiLoginOauthMetric.Set(float64(apiLoginOauthCount))
+		apiDashboardSaveMinMetric.Set(float64(apiDashboardSaveMin) / 1000)
 
-		apiLoginPostMetric = prometheus.NewGauge(
+		apiDashboardSaveMaxMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_login_post",
-				Help:      "Number of calls to Login Post API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_save_max_seconds",
+				Help:        "Dashboard Save API times (maximum).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiLoginPostMetric.Set(float64(apiLoginPostCount))
+		apiDashboardSaveMaxMetric.Set(float64(apiDashboardSaveMax) / 1000)
 
-		apiOrgCreateMetric = prometheus.NewGauge(
+		apiDashboardSaveMeanMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_org_create",
-				Help:      "Number of calls to Org Create API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_save_mean_seconds",
+				Help:        "Dashboard Save API times (mean).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiOrgCreateMetric.Set(float64(apiOrgCreateCount))
+		apiDashboardSaveMeanMetric.Set(apiDashboardSaveMean / 1000)
 
-		apiResponsesMetric = prometheus.NewGaugeVec(
+		apiDashboardSaveStddevMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_responses",
-				Help:      "Number of API responses.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_save_stddev_seconds",
+				Help:        "Dashboard Save API times (standard deviation).",
+				ConstLabels: constLabels,
 			},
-			[]string{"code"},
 		)
-		apiResponsesMetric.WithLabelValues("200").Set(float64(apiRespStatusCode200Count))
-		apiResponsesMetric.WithLabelValues("404").Set(float64(apiRespStatusCode404Count))
-		apiResponsesMetric.WithLabelValues("500").Set(float64(apiRespStatusCode500Count))
-		apiResponsesMetric.WithLabelValues("unknown").Set(float64(apiRespStatusCodeUnknownCount))
+		apiDashboardSaveStddevMetric.Set(apiDashboardSaveStd / 1000)
+
+		apiDashboardSearchCountMetric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dashboard_search_seconds_count", "Dashboard Search API times (number of observations).", nil, constLabels),
+			prometheus.CounterValue,
+			float64(apiDashboardSearchCount),
+		)
+
+		apiDashboardSearchP99Metric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dashboard_search_seconds", "Dashboard Search API times.", []string{"quantile"}, constLabels),
+			prometheus.GaugeValue,
+			apiDashboardSearchP99/1000,
+			"0.99",
+		)
 
-		apiUserSignupsCompletedMetric = prometheus.NewGauge(
+		apiDashboardSearchMinMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_user_signups_completed",
-				Help:      "Number of API User Signups completed.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_search_min_seconds",
+				Help:        "Dashboard Search API times (minimum).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiUserSignupsCompletedMetric.Set(float64(apiUserSignupCompletedCount))
+		apiDashboardSearchMinMetric.Set(float64(apiDashboardSearchMin) / 1000)
 
-		apiUserSignupsInviteMetric = prometheus.NewGauge(
+		apiDashboardSearchMaxMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_user_signups_invite",
-				Help:      "Number of API User Signups invite.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_search_max_seconds",
+				Help:        "Dashboard Search API times (maximum).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiUserSignupsInviteMetric.Set(float64(apiUserSignupInviteCount))
+		apiDashboardSearchMaxMetric.Set(float64(apiDashboardSearchMax) / 1000)
 
-		apiUserSignupsStartedMetric = prometheus.NewGauge(
+		apiDashboardSearchMeanMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "api_user_signups_started",
-				Help:      "Number of API User Signups started.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_search_mean_seconds",
+				Help:        "Dashboard Search API times (mean).",
+				ConstLabels: constLabels,
 			},
 		)
-		apiUserSignupsStartedMetric.Set(float64(apiUserSignupStartedCount))
+		apiDashboardSearchMeanMetric.Set(apiDashboardSearchMean / 1000)
 
-		awsCloudwatchGetMetricStatisticsMetric = prometheus.NewGauge(
+		apiDashboardSearchStddevMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "aws_cloudwatch_get_metric_statistics",
-				Help:      "Number of calls to AWS CloudWatch Get Metric Statistics API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_search_stddev_seconds",
+				Help:        "Dashboard Search API times (standard deviation).",
+				ConstLabels: constLabels,
+			},
+		)
+		apiDashboardSearchStddevMetric.Set(apiDashboardSearchStd / 1000)
+
+		apiDashboardSnapshotCreateMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_snapshot_create_total",
+				Help:        "Number of calls to Dashboard Snapshot Create API.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiDashboardSnapshotCreateMetric.Add(float64(apiDashboardSnapshotCreateCount))
+
+		apiDashboardSnapshotExternalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_snapshot_external_total",
+				Help:        "Number of calls to Dashboard Snapshot External API.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiDashboardSnapshotExternalMetric.Add(float64(apiDashboardSnapshotExternalCount))
+
+		apiDashboardSnapshotGetMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dashboard_snapshot_get_total",
+				Help:        "Number of calls to Dashboard Snapshot Get API.",
+				ConstLabels: constLabels,
 			},
 		)
-		awsCloudwatchGetMetricStatisticsMetric.Set(float64(awsCloudwatchGetMetricStatisticsCount))
+		apiDashboardSnapshotGetMetric.Add(float64(apiDashboardSnapshotGetCount))
+
+		apiDataproxyRequestAllCountMetric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dataproxy_request_all_seconds_count", "Dataproxy request API times (number of observations).", nil, constLabels),
+			prometheus.CounterValue,
+			float64(apiDataproxyRequestAllCount),
+		)
+
+		apiDataproxyRequestAllP99Metric = prometheus.MustNewConstMetric(
+			prometheus.NewDesc("grafana_metrics_api_dataproxy_request_all_seconds", "Dataproxy request API times.", []string{"quantile"}, constLabels),
+			prometheus.GaugeValue,
+			apiDataproxyRequestAllP99/1000,
+			"0.99",
+		)
 
-		awsCloudwatchListMetricsMetric = prometheus.NewGauge(
+		apiDataproxyRequestAllMinMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "aws_cloudwatch_list_metrics",
-				Help:      "Number of calls to AWS CloudWatch List Metrics API.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dataproxy_request_all_min_seconds",
+				Help:        "Dataproxy request API times (minimum).",
+				ConstLabels: constLabels,
 			},
 		)
-		awsCloudwatchListMetricsMetric.Set(float64(awsCloudwatchListMetricsCount))
+		apiDataproxyRequestAllMinMetric.Set(float64(apiDataproxyRequestAllMin) / 1000)
 
-		instanceStartMetric = prometheus.NewGauge(
+		apiDataproxyRequestAllMaxMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "instance_start",
-				Help:      "Number of Instance Starts.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dataproxy_request_all_max_seconds",
+				Help:        "Dataproxy request API times (maximum).",
+				ConstLabels: constLabels,
 			},
 		)
-		instanceStartMetric.Set(float64(instanceStartCount))
+		apiDataproxyRequestAllMaxMetric.Set(float64(apiDataproxyRequestAllMax) / 1000)
 
-		modelsDashboardInsertMetric = prometheus.NewGauge(
+		apiDataproxyRequestAllMeanMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "models_dashboard_insert",
-				Help:      "Number of Dashboard inserts.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dataproxy_request_all_mean_seconds",
+				Help:        "Dataproxy request API times (mean).",
+				ConstLabels: constLabels,
 			},
 		)
-		modelsDashboardInsertMetric.Set(float64(modelsDashboardInsertCount))
+		apiDataproxyRequestAllMeanMetric.Set(apiDataproxyRequestAllMean / 1000)
 
-		pageResponsesMetric = prometheus.NewGaugeVec(
+		apiDataproxyRequestAllStddevMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "page_responses",
-				Help:      "Number of Page responses.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_dataproxy_request_all_stddev_seconds",
+				Help:        "Dataproxy request API times (standard deviation).",
+				ConstLabels: constLabels,
+			},
+		)
+		apiDataproxyRequestAllStddevMetric.Set(apiDataproxyRequestAllStd / 1000)
+
+		apiLoginOauthMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_login_oauth_total",
+				Help:        "Number of calls to Login OAuth API.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiLoginOauthMetric.Add(float64(apiLoginOauthCount))
+
+		apiLoginPostMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_login_post_total",
+				Help:        "Number of calls to Login Post API.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiLoginPostMetric.Add(float64(apiLoginPostCount))
+
+		apiOrgCreateMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_org_create_total",
+				Help:        "Number of calls to Org Create API.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiOrgCreateMetric.Add(float64(apiOrgCreateCount))
+
+		apiResponsesMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_responses_total",
+				Help:        "Number of API responses.",
+				ConstLabels: constLabels,
 			},
 			[]string{"code"},
 		)
-		pageResponsesMetric.WithLabelValues("200").Set(float64(pageRespStatusCode200Count))
-		pageResponsesMetric.WithLabelValues("404").Set(float64(pageRespStatusCode404Count))
-		pageResponsesMetric.WithLabelValues("500").Set(float64(pageRespStatusCode500Count))
-		pageResponsesMetric.WithLabelValues("unknown").Set(float64(pageRespStatusCodeUnknownCount))
+		apiResponsesMetric.WithLabelValues("200").Add(float64(apiRespStatusCode200Count))
+		apiResponsesMetric.WithLabelValues("404").Add(float64(apiRespStatusCode404Count))
+		apiResponsesMetric.WithLabelValues("500").Add(float64(apiRespStatusCode500Count))
+		apiResponsesMetric.WithLabelValues("unknown").Add(float64(apiRespStatusCodeUnknownCount))
 
-		proxyResponsesMetric = prometheus.NewGaugeVec(
-			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "proxy_responses",
-				Help:      "Number of Proxy responses.",
+		apiUserSignupsCompletedMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_user_signups_completed_total",
+				Help:        "Number of API User Signups completed.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiUserSignupsCompletedMetric.Add(float64(apiUserSignupCompletedCount))
+
+		apiUserSignupsInviteMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_user_signups_invite_total",
+				Help:        "Number of API User Signups invite.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiUserSignupsInviteMetric.Add(float64(apiUserSignupInviteCount))
+
+		apiUserSignupsStartedMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "api_user_signups_started_total",
+				Help:        "Number of API User Signups started.",
+				ConstLabels: constLabels,
+			},
+		)
+		apiUserSignupsStartedMetric.Add(float64(apiUserSignupStartedCount))
+
+		awsCloudwatchGetMetricStatisticsMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "aws_cloudwatch_get_metric_statistics_total",
+				Help:        "Number of calls to AWS CloudWatch Get Metric Statistics API.",
+				ConstLabels: constLabels,
+			},
+		)
+		awsCloudwatchGetMetricStatisticsMetric.Add(float64(awsCloudwatchGetMetricStatisticsCount))
+
+		awsCloudwatchListMetricsMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "aws_cloudwatch_list_metrics_total",
+				Help:        "Number of calls to AWS CloudWatch List Metrics API.",
+				ConstLabels: constLabels,
+			},
+		)
+		awsCloudwatchListMetricsMetric.Add(float64(awsCloudwatchListMetricsCount))
+
+		instanceStartMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "instance_start_total",
+				Help:        "Number of Instance Starts.",
+				ConstLabels: constLabels,
+			},
+		)
+		instanceStartMetric.Add(float64(instanceStartCount))
+
+		modelsDashboardInsertMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "models_dashboard_insert_total",
+				Help:        "Number of Dashboard inserts.",
+				ConstLabels: constLabels,
+			},
+		)
+		modelsDashboardInsertMetric.Add(float64(modelsDashboardInsertCount))
+
+		pageResponsesMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "page_responses_total",
+				Help:        "Number of Page responses.",
+				ConstLabels: constLabels,
+			},
+			[]string{"code"},
+		)
+		pageResponsesMetric.WithLabelValues("200").Add(float64(pageRespStatusCode200Count))
+		pageResponsesMetric.WithLabelValues("404").Add(float64(pageRespStatusCode404Count))
+		pageResponsesMetric.WithLabelValues("500").Add(float64(pageRespStatusCode500Count))
+		pageResponsesMetric.WithLabelValues("unknown").Add(float64(pageRespStatusCodeUnknownCount))
+
+		proxyResponsesMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "proxy_responses_total",
+				Help:        "Number of Proxy responses.",
+				ConstLabels: constLabels,
 			},
 			[]string{"code"},
 		)
-		proxyResponsesMetric.WithLabelValues("200").Set(float64(proxyRespStatusCode200Count))
-		proxyResponsesMetric.WithLabelValues("404").Set(float64(proxyRespStatusCode404Count))
-		proxyResponsesMetric.WithLabelValues("500").Set(float64(proxyRespStatusCode500Count))
-		proxyResponsesMetric.WithLabelValues("unknown").Set(float64(proxyRespStatusCodeUnknownCount))
+		proxyResponsesMetric.WithLabelValues("200").Add(float64(proxyRespStatusCode200Count))
+		proxyResponsesMetric.WithLabelValues("404").Add(float64(proxyRespStatusCode404Count))
+		proxyResponsesMetric.WithLabelValues("500").Add(float64(proxyRespStatusCode500Count))
+		proxyResponsesMetric.WithLabelValues("unknown").Add(float64(proxyRespStatusCodeUnknownCount))
 
 		dashboardsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "dashboards",
-				Help:      "Number of dashboards.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "dashboards",
+				Help:        "Number of dashboards.",
+				ConstLabels: constLabels,
 			},
 		)
 		dashboardsMetric.Set(float64(statsTotalsStatDashboardsValue))
 
 		orgsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "orgs",
-				Help:      "Number of orgs.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "orgs",
+				Help:        "Number of orgs.",
+				ConstLabels: constLabels,
 			},
 		)
 		orgsMetric.Set(float64(statsTotalsStatOrgsValue))
 
 		playlistsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "playlists",
-				Help:      "Number of playlists.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "playlists",
+				Help:        "Number of playlists.",
+				ConstLabels: constLabels,
 			},
 		)
 		playlistsMetric.Set(float64(statsTotalsStatPlaylistsValue))
 
 		usersMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "users",
-				Help:      "Number of users.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "users",
+				Help:        "Number of users.",
+				ConstLabels: constLabels,
 			},
 		)
 		usersMetric.Set(float64(statsTotalsStatUsersValue))
 
 		scrapesTotalMetric = prometheus.NewCounter(
 			prometheus.CounterOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "scrapes_total",
-				Help:      "Total number of Grafana metrics scrapes.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana metrics scrapes.",
+				ConstLabels: constLabels,
 			},
 		)
 		scrapesTotalMetric.Inc()
 
 		scrapeErrorsTotalMetric = prometheus.NewCounter(
 			prometheus.CounterOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "scrape_errors_total",
-				Help:      "Total number of Grafana metrics scrape errors.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana metrics scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana metrics scrape timeouts.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		lastScrapeErrorMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "last_scrape_error",
-				Help:      "Whether the last metrics scrape from Grafana resulted in an error (1 for error, 0 for success).",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
 			},
 		)
 		lastScrapeErrorMetric.Set(0)
 
 		lastScrapeTimestampMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "last_scrape_timestamp",
-				Help:      "Number of seconds since 1970 since last metrics scrape from Grafana.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana.",
+				ConstLabels: constLabels,
 			},
 		)
 
 		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: "grafana",
-				Subsystem: "metrics",
-				Name:      "last_scrape_duration_seconds",
-				Help:      "Duration of the last metrics scrape from Grafana.",
+				Namespace:   "grafana",
+				Subsystem:   "metrics",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana.",
+				ConstLabels: constLabels,
 			},
 		)
 	})
 
 	JustBeforeEach(func() {
-		metricsCollector = NewMetricsCollector(grafanaClient)
+		metricsCollector = NewMetricsCollector(grafanaClient, constLabels, legacyMetricNames)
 	})
 
 	Describe("Describe", func() {
@@ -587,122 +849,6 @@ var _ = Describe("MetricsCollectors", func() {
 			go metricsCollector.Describe(descriptions)
 		})
 
-		It("returns a grafana_metrics_alerting_active_alerts metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(alertingActiveAlertsMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_alerting_execution_time metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(alertingExecutionTimeMetric.WithLabelValues("count").Desc())))
-		})
-
-		It("returns a grafana_metrics_alerting_notifications_sent metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(alertingNotificationsSentMetric.WithLabelValues("line").Desc())))
-		})
-
-		It("returns a grafana_metrics_alerting_results metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(alertingResultsMetric.WithLabelValues("200").Desc())))
-		})
-
-		It("returns a grafana_metrics_api_admin_user_create metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiAdminUserCreateMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDashboardGetMetric.WithLabelValues("count").Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_save metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDashboardSaveMetric.WithLabelValues("count").Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_search metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDashboardSearchMetric.WithLabelValues("count").Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_snapshot_create metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDashboardSnapshotCreateMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_snapshot_external metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDashboardSnapshotExternalMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_snapshot_get metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDashboardSnapshotGetMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_dataproxy_request_all metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiDataproxyRequestAllMetric.WithLabelValues("count").Desc())))
-		})
-
-		It("returns a grafana_metrics_api_login_oauth metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiLoginOauthMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_login_post metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiLoginPostMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_org_create metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiOrgCreateMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_responses metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiResponsesMetric.WithLabelValues("200").Desc())))
-		})
-
-		It("returns a grafana_metrics_api_user_signups_completed metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiUserSignupsCompletedMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_user_signups_invite metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiUserSignupsInviteMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_api_user_signups_started metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(apiUserSignupsStartedMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_aws_cloudwatch_get_metric_statistics metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(awsCloudwatchGetMetricStatisticsMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_aws_cloudwatch_list_metrics metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(awsCloudwatchListMetricsMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_instance_start metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(instanceStartMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_models_dashboard_insert metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(modelsDashboardInsertMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_page_responses metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(pageResponsesMetric.WithLabelValues("200").Desc())))
-		})
-
-		It("returns a grafana_metrics_proxy_responses metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(proxyResponsesMetric.WithLabelValues("200").Desc())))
-		})
-
-		It("returns a grafana_metrics_dashboards metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(dashboardsMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_orgs metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(orgsMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_playlists metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(playlistsMetric.Desc())))
-		})
-
-		It("returns a grafana_metrics_users metric description", func() {
-			Eventually(descriptions).Should(Receive(Equal(usersMetric.Desc())))
-		})
-
 		It("returns a grafana_metrics_scrapes_total metric description", func() {
 			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
 		})
@@ -711,6 +857,10 @@ var _ = Describe("MetricsCollectors", func() {
 			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
 		})
 
+		It("returns a grafana_metrics_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
 		It("returns a grafana_metrics_last_scrape_error metric description", func() {
 			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
 		})
@@ -728,569 +878,614 @@ var _ = Describe("MetricsCollectors", func() {
 		var (
 			metricsResponse grafana.Metrics
 
+			ctx     context.Context
 			metrics chan prometheus.Metric
 		)
 
 		BeforeEach(func() {
 			metricsResponse = grafana.Metrics{
-				AlertingActiveAlerts: grafana.Gauge{
-					Value: int64(alertingActiveAlertsValue),
+				"alerting.active_alerts": grafana.Metric{
+					Gauge: &grafana.Gauge{
+						Value: float64(alertingActiveAlertsValue),
+					},
 				},
-				AlertingExecutionTime: grafana.Timer{
-					Count: int64(alertingExecutionTimeCount),
-					Max:   int64(alertingExecutionTimeMax),
-					Mean:  float64(alertingExecutionTimeMean),
-					Min:   int64(alertingExecutionTimeMin),
-					P25:   float64(alertingExecutionTimeP25),
-					P75:   float64(alertingExecutionTimeP75),
-					P90:   float64(alertingExecutionTimeP90),
-					P99:   float64(alertingExecutionTimeP99),
-					Std:   float64(alertingExecutionTimeStd),
+				"alerting.execution_time": grafana.Metric{
+					Timer: &grafana.Timer{
+						Count: int64(alertingExecutionTimeCount),
+						Max:   int64(alertingExecutionTimeMax),
+						Mean:  float64(alertingExecutionTimeMean),
+						Min:   int64(alertingExecutionTimeMin),
+						P25:   float64(alertingExecutionTimeP25),
+						P75:   float64(alertingExecutionTimeP75),
+						P90:   float64(alertingExecutionTimeP90),
+						P99:   float64(alertingExecutionTimeP99),
+						Std:   float64(alertingExecutionTimeStd),
+					},
 				},
-				AlertingNotificationsSentLine: grafana.Counter{
-					Count: int64(alertingNotificationsSentLineCount),
+				"alerting.notifications_sent.type_LINE": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentLineCount),
+					},
 				},
-				AlertingNotificationsSentDingDing: grafana.Counter{
-					Count: int64(alertingNotificationsSentDingDingCount),
+				"alerting.notifications_sent.type_dingding": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentDingDingCount),
+					},
 				},
-				AlertingNotificationsSentEmail: grafana.Counter{
-					Count: int64(alertingNotificationsSentEmailCount),
+				"alerting.notifications_sent.type_email": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentEmailCount),
+					},
 				},
-				AlertingNotificationsSentOpsgenie: grafana.Counter{
-					Count: int64(alertingNotificationsSentOpsgenieCount),
+				"alerting.notifications_sent.type_opsgenie": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentOpsgenieCount),
+					},
 				},
-				AlertingNotificationsSentPagerduty: grafana.Counter{
-					Count: int64(alertingNotificationsSentPagerdutyCount),
+				"alerting.notifications_sent.type_pagerduty": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentPagerdutyCount),
+					},
 				},
-				AlertingNotificationsSentPushover: grafana.Counter{
-					Count: int64(alertingNotificationsSentPushoverCount),
+				"alerting.notifications_sent.type_pushover": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentPushoverCount),
+					},
 				},
-				AlertingNotificationsSentSensu: grafana.Counter{
-					Count: int64(alertingNotificationsSentSensuCount),
+				"alerting.notifications_sent.type_sensu": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentSensuCount),
+					},
 				},
-				AlertingNotificationsSentSlack: grafana.Counter{
-					Count: int64(alertingNotificationsSentSlackCount),
+				"alerting.notifications_sent.type_slack": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentSlackCount),
+					},
 				},
-				AlertingNotificationsSentTelegram: grafana.Counter{
-					Count: int64(alertingNotificationsSentTelegramCount),
+				"alerting.notifications_sent.type_telegram": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentTelegramCount),
+					},
 				},
-				AlertingNotificationsSentThreema: grafana.Counter{
-					Count: int64(alertingNotificationsSentThreemaCount),
+				"alerting.notifications_sent.type_threema": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentThreemaCount),
+					},
 				},
-				AlertingNotificationsSentVictorops: grafana.Counter{
-					Count: int64(alertingNotificationsSentVictoropsCount),
+				"alerting.notifications_sent.type_victorops": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentVictoropsCount),
+					},
 				},
-				AlertingNotificationsSentWebhook: grafana.Counter{
-					Count: int64(alertingNotificationsSentWebhookCount),
+				"alerting.notifications_sent.type_webhook": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingNotificationsSentWebhookCount),
+					},
 				},
-				AlertingResultStateAlerting: grafana.Counter{
-					Count: int64(alertingResultStateAlertingCount),
+				"alerting.result.state_alerting": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingResultStateAlertingCount),
+					},
 				},
-				AlertingResultStateNoData: grafana.Counter{
-					Count: int64(alertingResultStateNoDataCount),
+				"alerting.result.state_no_data": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingResultStateNoDataCount),
+					},
 				},
-				AlertingResultStateOk: grafana.Counter{
-					Count: int64(alertingResultStateOkCount),
+				"alerting.result.state_ok": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingResultStateOkCount),
+					},
 				},
-				AlertingResultStatePaused: grafana.Counter{
-					Count: int64(alertingResultStatePausedCount),
+				"alerting.result.state_paused": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingResultStatePausedCount),
+					},
 				},
-				AlertingResultStatePending: grafana.Counter{
-					Count: int64(alertingResultStatePendingCount),
+				"alerting.result.state_pending": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(alertingResultStatePendingCount),
+					},
 				},
-				APIAdminUserCreate: grafana.Counter{
-					Count: int64(apiAdminUserCreateCount),
+				"api.admin.user_create": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiAdminUserCreateCount),
+					},
 				},
-				APIDashboardGet: grafana.Timer{
-					Count: int64(apiDashboardGetCount),
-					Max:   int64(apiDashboardGetMax),
-					Mean:  float64(apiDashboardGetMean),
-					Min:   int64(apiDashboardGetMin),
-					P25:   float64(apiDashboardGetP25),
-					P75:   float64(apiDashboardGetP75),
-					P90:   float64(apiDashboardGetP90),
-					P99:   float64(apiDashboardGetP99),
-					Std:   float64(apiDashboardGetStd),
+				"api.dashboard.get": grafana.Metric{
+					Timer: &grafana.Timer{
+						Count: int64(apiDashboardGetCount),
+						Max:   int64(apiDashboardGetMax),
+						Mean:  float64(apiDashboardGetMean),
+						Min:   int64(apiDashboardGetMin),
+						P25:   float64(apiDashboardGetP25),
+						P75:   float64(apiDashboardGetP75),
+						P90:   float64(apiDashboardGetP90),
+						P99:   float64(apiDashboardGetP99),
+						Std:   float64(apiDashboardGetStd),
+					},
 				},
-				APIDashboardSave: grafana.Timer{
-					Count: int64(apiDashboardSaveCount),
-					Max:   int64(apiDashboardSaveMax),
-					Mean:  float64(apiDashboardSaveMean),
-					Min:   int64(apiDashboardSaveMin),
-					P25:   float64(apiDashboardSaveP25),
-					P75:   float64(apiDashboardSaveP75),
-					P90:   float64(apiDashboardSaveP90),
-					P99:   float64(apiDashboardSaveP99),
-					Std:   float64(apiDashboardSaveStd),
+				"api.dashboard.save": grafana.Metric{
+					Timer: &grafana.Timer{
+						Count: int64(apiDashboardSaveCount),
+						Max:   int64(apiDashboardSaveMax),
+						Mean:  float64(apiDashboardSaveMean),
+						Min:   int64(apiDashboardSaveMin),
+						P25:   float64(apiDashboardSaveP25),
+						P75:   float64(apiDashboardSaveP75),
+						P90:   float64(apiDashboardSaveP90),
+						P99:   float64(apiDashboardSaveP99),
+						Std:   float64(apiDashboardSaveStd),
+					},
 				},
-				APIDashboardSearch: grafana.Timer{
-					Count: int64(apiDashboardSearchCount),
-					Max:   int64(apiDashboardSearchMax),
-					Mean:  float64(apiDashboardSearchMean),
-					Min:   int64(apiDashboardSearchMin),
-					P25:   float64(apiDashboardSearchP25),
-					P75:   float64(apiDashboardSearchP75),
-					P90:   float64(apiDashboardSearchP90),
-					P99:   float64(apiDashboardSearchP99),
-					Std:   float64(apiDashboardSearchStd),
+				"api.dashboard.search": grafana.Metric{
+					Timer: &grafana.Timer{
+						Count: int64(apiDashboardSearchCount),
+						Max:   int64(apiDashboardSearchMax),
+						Mean:  float64(apiDashboardSearchMean),
+						Min:   int64(apiDashboardSearchMin),
+						P25:   float64(apiDashboardSearchP25),
+						P75:   float64(apiDashboardSearchP75),
+						P90:   float64(apiDashboardSearchP90),
+						P99:   float64(apiDashboardSearchP99),
+						Std:   float64(apiDashboardSearchStd),
+					},
 				},
-				APIDashboardSnapshotCreate: grafana.Counter{
-					Count: int64(apiDashboardSnapshotCreateCount),
+				"api.dashboard_snapshot.create": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiDashboardSnapshotCreateCount),
+					},
 				},
-				APIDashboardSnapshotExternal: grafana.Counter{
-					Count: int64(apiDashboardSnapshotExternalCount),
+				"api.dashboard_snapshot.external": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiDashboardSnapshotExternalCount),
+					},
 				},
-				APIDashboardSnapshotGet: grafana.Counter{
-					Count: int64(apiDashboardSnapshotGetCount),
+				"api.dashboard_snapshot.get": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiDashboardSnapshotGetCount),
+					},
 				},
-				APIDataproxyRequestAll: grafana.Timer{
-					Count: int64(apiDataproxyRequestAllCount),
-					Max:   int64(apiDataproxyRequestAllMax),
-					Mean:  float64(apiDataproxyRequestAllMean),
-					Min:   int64(apiDataproxyRequestAllMin),
-					P25:   float64(apiDataproxyRequestAllP25),
-					P75:   float64(apiDataproxyRequestAllP75),
-					P90:   float64(apiDataproxyRequestAllP90),
-					P99:   float64(apiDataproxyRequestAllP99),
-					Std:   float64(apiDataproxyRequestAllStd),
+				"api.dataproxy.request.all": grafana.Metric{
+					Timer: &grafana.Timer{
+						Count: int64(apiDataproxyRequestAllCount),
+						Max:   int64(apiDataproxyRequestAllMax),
+						Mean:  float64(apiDataproxyRequestAllMean),
+						Min:   int64(apiDataproxyRequestAllMin),
+						P25:   float64(apiDataproxyRequestAllP25),
+						P75:   float64(apiDataproxyRequestAllP75),
+						P90:   float64(apiDataproxyRequestAllP90),
+						P99:   float64(apiDataproxyRequestAllP99),
+						Std:   float64(apiDataproxyRequestAllStd),
+					},
 				},
-				APILoginOauth: grafana.Counter{
-					Count: int64(apiLoginOauthCount),
+				"api.login.oauth": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiLoginOauthCount),
+					},
 				},
-				APILoginPost: grafana.Counter{
-					Count: int64(apiLoginPostCount),
+				"api.login.post": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiLoginPostCount),
+					},
 				},
-				APIOrgCreate: grafana.Counter{
-					Count: int64(apiOrgCreateCount),
+				"api.org.create": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiOrgCreateCount),
+					},
 				},
-				APIRespStatusCode200: grafana.Counter{
-					Count: int64(apiRespStatusCode200Count),
+				"api.resp_status.code_200": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiRespStatusCode200Count),
+					},
 				},
-				APIRespStatusCode404: grafana.Counter{
-					Count: int64(apiRespStatusCode404Count),
+				"api.resp_status.code_404": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiRespStatusCode404Count),
+					},
 				},
-				APIRespStatusCode500: grafana.Counter{
-					Count: int64(apiRespStatusCode500Count),
+				"api.resp_status.code_500": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiRespStatusCode500Count),
+					},
 				},
-				APIRespStatusCodeUnknown: grafana.Counter{
-					Count: int64(apiRespStatusCodeUnknownCount),
+				"api.resp_status.code_unknown": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiRespStatusCodeUnknownCount),
+					},
 				},
-				APIUserSignupCompleted: grafana.Counter{
-					Count: int64(apiUserSignupCompletedCount),
+				"api.user.signup_completed": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiUserSignupCompletedCount),
+					},
 				},
-				APIUserSignupInvite: grafana.Counter{
-					Count: int64(apiUserSignupInviteCount),
+				"api.user.signup_invite": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiUserSignupInviteCount),
+					},
 				},
-				APIUserSignupStarted: grafana.Counter{
-					Count: int64(apiUserSignupStartedCount),
+				"api.user.signup_started": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(apiUserSignupStartedCount),
+					},
 				},
-				AWSCloudwatchGetMetricStatistics: grafana.Counter{
-					Count: int64(awsCloudwatchGetMetricStatisticsCount),
+				"aws.cloudwatch.get_metric_statistics": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(awsCloudwatchGetMetricStatisticsCount),
+					},
 				},
-				AWSCloudwatchListMetrics: grafana.Counter{
-					Count: int64(awsCloudwatchListMetricsCount),
+				"aws.cloudwatch.list_metrics": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(awsCloudwatchListMetricsCount),
+					},
 				},
-				InstanceStart: grafana.Counter{
-					Count: int64(instanceStartCount),
+				"instance_start": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(instanceStartCount),
+					},
 				},
-				ModelsDashboardInsert: grafana.Counter{
-					Count: int64(modelsDashboardInsertCount),
+				"models.dashboard.insert": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(modelsDashboardInsertCount),
+					},
 				},
-				PageRespStatusCode200: grafana.Counter{
-					Count: int64(pageRespStatusCode200Count),
+				"page.resp_status.code_200": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(pageRespStatusCode200Count),
+					},
 				},
-				PageRespStatusCode404: grafana.Counter{
-					Count: int64(pageRespStatusCode404Count),
+				"page.resp_status.code_404": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(pageRespStatusCode404Count),
+					},
 				},
-				PageRespStatusCode500: grafana.Counter{
-					Count: int64(pageRespStatusCode500Count),
+				"page.resp_status.code_500": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(pageRespStatusCode500Count),
+					},
 				},
-				PageRespStatusCodeUnknown: grafana.Counter{
-					Count: int64(pageRespStatusCodeUnknownCount),
+				"page.resp_status.code_unknown": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(pageRespStatusCodeUnknownCount),
+					},
 				},
-				ProxyRespStatusCode200: grafana.Counter{
-					Count: int64(proxyRespStatusCode200Count),
+				"proxy.resp_status.code_200": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(proxyRespStatusCode200Count),
+					},
 				},
-				ProxyRespStatusCode404: grafana.Counter{
-					Count: int64(proxyRespStatusCode404Count),
+				"proxy.resp_status.code_404": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(proxyRespStatusCode404Count),
+					},
 				},
-				ProxyRespStatusCode500: grafana.Counter{
-					Count: int64(proxyRespStatusCode500Count),
+				"proxy.resp_status.code_500": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(proxyRespStatusCode500Count),
+					},
 				},
-				ProxyRespStatusCodeUnknown: grafana.Counter{
-					Count: int64(proxyRespStatusCodeUnknownCount),
+				"proxy.resp_status.code_unknown": grafana.Metric{
+					Counter: &grafana.Counter{
+						Count: int64(proxyRespStatusCodeUnknownCount),
+					},
 				},
-				StatsTotalsStatDashboards: grafana.Gauge{
-					Value: int64(statsTotalsStatDashboardsValue),
+				"stat_totals.stat_dashboards": grafana.Metric{
+					Gauge: &grafana.Gauge{
+						Value: float64(statsTotalsStatDashboardsValue),
+					},
 				},
-				StatsTotalsStatOrgs: grafana.Gauge{
-					Value: int64(statsTotalsStatOrgsValue),
+				"stat_totals.stat_orgs": grafana.Metric{
+					Gauge: &grafana.Gauge{
+						Value: float64(statsTotalsStatOrgsValue),
+					},
 				},
-				StatsTotalsStatPlaylists: grafana.Gauge{
-					Value: int64(statsTotalsStatPlaylistsValue),
+				"stat_totals.stat_playlists": grafana.Metric{
+					Gauge: &grafana.Gauge{
+						Value: float64(statsTotalsStatPlaylistsValue),
+					},
 				},
-				StatsTotalsStatUsers: grafana.Gauge{
-					Value: int64(statsTotalsStatUsersValue),
+				"stat_totals.stat_users": grafana.Metric{
+					Gauge: &grafana.Gauge{
+						Value: float64(statsTotalsStatUsersValue),
+					},
 				},
 			}
 			grafanaClient.GetMetricsReturns(metricsResponse, nil)
 
+			ctx = context.Background()
 			metrics = make(chan prometheus.Metric)
 		})
 
 		JustBeforeEach(func() {
-			go metricsCollector.Collect(metrics)
+			go metricsCollector.CollectContext(ctx, metrics)
 		})
 
 		It("returns a grafana_metrics_alerting_active_alerts metric", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingActiveAlertsMetric)))
 		})
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric count", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("count"))))
-		})
-
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric max", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("max"))))
-		})
-
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric mean", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("mean"))))
+		It("returns a grafana_metrics_alerting_execution_time_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeP99Metric)))
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeCountMetric)))
 		})
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric min", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("min"))))
+		It("returns a grafana_metrics_alerting_execution_time_min_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMinMetric)))
 		})
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric p25", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("p25"))))
+		It("returns a grafana_metrics_alerting_execution_time_max_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMaxMetric)))
 		})
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric p75", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("p75"))))
+		It("returns a grafana_metrics_alerting_execution_time_mean_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMeanMetric)))
 		})
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric p90", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("p90"))))
+		It("returns a grafana_metrics_alerting_execution_time_stddev_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeStddevMetric)))
 		})
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric p99", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("p99"))))
-		})
+		It("does not return a sum for the timers", func() {
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(metricsCollector)
 
-		It("returns a grafana_metrics_alerting_execution_time metric with a metric std", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeMetric.WithLabelValues("std"))))
+			metricFamilies, err := registry.Gather()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(metricFamilies).ToNot(BeEmpty())
+			for _, metricFamily := range metricFamilies {
+				Expect(metricFamily.GetName()).ToNot(HaveSuffix("_sum"))
+			}
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type line", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type line", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("line"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type dingding", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type dingding", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("dingding"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type email", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type email", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("email"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type opsgenie", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type opsgenie", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("opsgenie"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type pagerduty", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type pagerduty", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("pagerduty"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type pushover", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type pushover", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("pushover"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type sensu", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type sensu", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("sensu"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type slack", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type slack", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("slack"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type telegram", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type telegram", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("telegram"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type threema", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type threema", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("threema"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type victorops", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type victorops", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("victorops"))))
 		})
 
-		It("returns a grafana_metrics_alerting_notifications_sent metric with a type webhook", func() {
+		It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type webhook", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentMetric.WithLabelValues("webhook"))))
 		})
 
-		It("returns a grafana_metrics_alerting_results metric with a state alerting", func() {
+		It("returns a grafana_metrics_alerting_results_total metric with a state alerting", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingResultsMetric.WithLabelValues("alerting"))))
 		})
 
-		It("returns a grafana_metrics_alerting_results metric with a state no_data", func() {
+		It("returns a grafana_metrics_alerting_results_total metric with a state no_data", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingResultsMetric.WithLabelValues("no_data"))))
 		})
 
-		It("returns a grafana_metrics_alerting_results metric with a state ok", func() {
+		It("returns a grafana_metrics_alerting_results_total metric with a state ok", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingResultsMetric.WithLabelValues("ok"))))
 		})
 
-		It("returns a grafana_metrics_alerting_results metric with a state paused", func() {
+		It("returns a grafana_metrics_alerting_results_total metric with a state paused", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingResultsMetric.WithLabelValues("paused"))))
 		})
 
-		It("returns a grafana_metrics_alerting_results metric with a state pending", func() {
+		It("returns a grafana_metrics_alerting_results_total metric with a state pending", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingResultsMetric.WithLabelValues("pending"))))
 		})
 
-		It("returns a grafana_metrics_api_admin_user_create metric with a state completed", func() {
+		It("returns a grafana_metrics_api_admin_user_create_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiAdminUserCreateMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric count", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("count"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric max", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("max"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric mean", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("mean"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric min", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("min"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric p25", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("p25"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric p75", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("p75"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric p90", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("p90"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric p99", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("p99"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_get metric with a metric std", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMetric.WithLabelValues("std"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric count", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("count"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric max", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("max"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric mean", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("mean"))))
+		It("returns a grafana_metrics_api_dashboard_get_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetP99Metric)))
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetCountMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric min", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("min"))))
+		It("returns a grafana_metrics_api_dashboard_get_min_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMinMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric p25", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("p25"))))
+		It("returns a grafana_metrics_api_dashboard_get_max_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMaxMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric p75", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("p75"))))
+		It("returns a grafana_metrics_api_dashboard_get_mean_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetMeanMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric p90", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("p90"))))
+		It("returns a grafana_metrics_api_dashboard_get_stddev_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardGetStddevMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric p99", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("p99"))))
+		It("returns a grafana_metrics_api_dashboard_save_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveP99Metric)))
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveCountMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_save metric with a metric std", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMetric.WithLabelValues("std"))))
+		It("returns a grafana_metrics_api_dashboard_save_min_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMinMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric count", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("count"))))
+		It("returns a grafana_metrics_api_dashboard_save_max_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMaxMetric)))
 		})
 
-		It("returns a grafana_metrics_api_api_dashboard_search with a metric max", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("max"))))
+		It("returns a grafana_metrics_api_dashboard_save_mean_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveMeanMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric mean", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("mean"))))
+		It("returns a grafana_metrics_api_dashboard_save_stddev_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSaveStddevMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric min", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("min"))))
+		It("returns a grafana_metrics_api_dashboard_search_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchP99Metric)))
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchCountMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric p25", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("p25"))))
+		It("returns a grafana_metrics_api_dashboard_search_min_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMinMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric p75", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("p75"))))
+		It("returns a grafana_metrics_api_dashboard_search_max_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMaxMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric p90", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("p90"))))
+		It("returns a grafana_metrics_api_dashboard_search_mean_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMeanMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric p99", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("p99"))))
+		It("returns a grafana_metrics_api_dashboard_search_stddev_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchStddevMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_search metric with a metric std", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSearchMetric.WithLabelValues("std"))))
-		})
-
-		It("returns a grafana_metrics_api_dashboard_snapshot_create metric with a state completed", func() {
+		It("returns a grafana_metrics_api_dashboard_snapshot_create_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSnapshotCreateMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_snapshot_external metric with a state completed", func() {
+		It("returns a grafana_metrics_api_dashboard_snapshot_external_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSnapshotExternalMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dashboard_snapshot_get metric with a state completed", func() {
+		It("returns a grafana_metrics_api_dashboard_snapshot_get_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDashboardSnapshotGetMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric count", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("count"))))
-		})
-
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric max", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("max"))))
-		})
-
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric mean", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("mean"))))
-		})
-
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric min", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("min"))))
+		It("returns a grafana_metrics_api_dataproxy_request_all_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllP99Metric)))
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllCountMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric p25", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("p25"))))
+		It("returns a grafana_metrics_api_dataproxy_request_all_min_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMinMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric p75", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("p75"))))
+		It("returns a grafana_metrics_api_dataproxy_request_all_max_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMaxMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric p90", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("p90"))))
+		It("returns a grafana_metrics_api_dataproxy_request_all_mean_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMeanMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric p99", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("p99"))))
+		It("returns a grafana_metrics_api_dataproxy_request_all_stddev_seconds metric", func() {
+			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllStddevMetric)))
 		})
 
-		It("returns a grafana_metrics_api_dataproxy_request_all metric with a metric std", func() {
-			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiDataproxyRequestAllMetric.WithLabelValues("std"))))
-		})
-
-		It("returns a grafana_metrics_api_login_post metric with a state completed", func() {
+		It("returns a grafana_metrics_api_login_post_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiLoginPostMetric)))
 		})
 
-		It("returns a grafana_metrics_api_org_create metric with a state completed", func() {
+		It("returns a grafana_metrics_api_org_create_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiOrgCreateMetric)))
 		})
 
-		It("returns a grafana_metrics_api_responses metric with a status_code 200", func() {
+		It("returns a grafana_metrics_api_responses_total metric with a status_code 200", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiResponsesMetric.WithLabelValues("unknown"))))
 		})
 
-		It("returns a grafana_metrics_api_responses metric with a status_code 404", func() {
+		It("returns a grafana_metrics_api_responses_total metric with a status_code 404", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiResponsesMetric.WithLabelValues("404"))))
 		})
 
-		It("returns a grafana_metrics_api_responses metric with a status_code 500", func() {
+		It("returns a grafana_metrics_api_responses_total metric with a status_code 500", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiResponsesMetric.WithLabelValues("500"))))
 		})
 
-		It("returns a grafana_metrics_api_responses metric with a status_code unknown", func() {
+		It("returns a grafana_metrics_api_responses_total metric with a status_code unknown", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiResponsesMetric.WithLabelValues("unknown"))))
 		})
 
-		It("returns a grafana_metrics_api_user_signups_completed metric with a state completed", func() {
+		It("returns a grafana_metrics_api_user_signups_completed_total metric with a state completed", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiUserSignupsCompletedMetric)))
 		})
 
-		It("returns a grafana_metrics_api_user_signups_invite metric with a state inivte", func() {
+		It("returns a grafana_metrics_api_user_signups_invite_total metric with a state inivte", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiUserSignupsInviteMetric)))
 		})
 
-		It("returns a grafana_metrics_api_user_signups_started metric with a state started", func() {
+		It("returns a grafana_metrics_api_user_signups_started_total metric with a state started", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiUserSignupsStartedMetric)))
 		})
 
-		It("returns a grafana_metrics_aws_cloudwatch_get_metric_statistics metric with a state started", func() {
+		It("returns a grafana_metrics_aws_cloudwatch_get_metric_statistics_total metric with a state started", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(awsCloudwatchGetMetricStatisticsMetric)))
 		})
 
-		It("returns a grafana_metrics_aws_cloudwatch_list_metrics metric with a state started", func() {
+		It("returns a grafana_metrics_aws_cloudwatch_list_metrics_total metric with a state started", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(awsCloudwatchListMetricsMetric)))
 		})
 
-		It("returns a grafana_metrics_instance_start metric with a state started", func() {
+		It("returns a grafana_metrics_instance_start_total metric with a state started", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(instanceStartMetric)))
 		})
 
-		It("returns a grafana_metrics_models_dashboard_insert metric with a state started", func() {
+		It("returns a grafana_metrics_models_dashboard_insert_total metric with a state started", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(modelsDashboardInsertMetric)))
 		})
 
-		It("returns a grafana_metrics_page_responses metric with a status_code 200", func() {
+		It("returns a grafana_metrics_page_responses_total metric with a status_code 200", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(pageResponsesMetric.WithLabelValues("200"))))
 		})
 
-		It("returns a grafana_metrics_page_responses metric with a status_code 404", func() {
+		It("returns a grafana_metrics_page_responses_total metric with a status_code 404", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(pageResponsesMetric.WithLabelValues("404"))))
 		})
 
-		It("returns a grafana_metrics_page_responses metric with a status_code 500", func() {
+		It("returns a grafana_metrics_page_responses_total metric with a status_code 500", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(pageResponsesMetric.WithLabelValues("500"))))
 		})
 
-		It("returns a grafana_metrics_page_responses metric with a status_code unknown", func() {
+		It("returns a grafana_metrics_page_responses_total metric with a status_code unknown", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(pageResponsesMetric.WithLabelValues("unknown"))))
 		})
 
-		It("returns a grafana_metrics_proxy_responses metric with a status_code 200", func() {
+		It("returns a grafana_metrics_proxy_responses_total metric with a status_code 200", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(proxyResponsesMetric.WithLabelValues("200"))))
 		})
 
-		It("returns a grafana_metrics_proxy_responses metric with a status_code 404", func() {
+		It("returns a grafana_metrics_proxy_responses_total metric with a status_code 404", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(proxyResponsesMetric.WithLabelValues("404"))))
 		})
 
-		It("returns a grafana_metrics_proxy_responses metric with a status_code 500", func() {
+		It("returns a grafana_metrics_proxy_responses_total metric with a status_code 500", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(proxyResponsesMetric.WithLabelValues("500"))))
 		})
 
-		It("returns a grafana_metrics_proxy_responses metric with a status_code unknown", func() {
+		It("returns a grafana_metrics_proxy_responses_total metric with a status_code unknown", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(proxyResponsesMetric.WithLabelValues("unknown"))))
 		})
 
@@ -1322,6 +1517,199 @@ var _ = Describe("MetricsCollectors", func() {
 			Eventually(metrics, "2s").Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
 		})
 
+		Context("when Grafana returns metrics unknown to the exporter", func() {
+			var (
+				alertingNotificationsSentDiscordMetric prometheus.Counter
+				apiFolderCreateMetric                  prometheus.Counter
+				apiFolderGetCountMetric                prometheus.Metric
+				apiFolderGetP99Metric                  prometheus.Metric
+				statTotalsStatActiveUsersMetric        prometheus.Gauge
+			)
+
+			BeforeEach(func() {
+				metricsResponse["alerting.notifications_sent.type_discord"] = grafana.Metric{
+					Counter: &grafana.Counter{Count: int64(101)},
+				}
+				metricsResponse["api.folder.create"] = grafana.Metric{
+					Counter: &grafana.Counter{Count: int64(102)},
+				}
+				metricsResponse["api.folder.get"] = grafana.Metric{
+					Timer: &grafana.Timer{Count: int64(103), Mean: float64(104), P25: float64(105), P75: float64(106), P90: float64(107), P99: float64(108)},
+				}
+				metricsResponse["stat_totals.stat_active_users"] = grafana.Metric{
+					Gauge: &grafana.Gauge{Value: float64(109)},
+				}
+				metricsResponse["version"] = grafana.Metric{}
+
+				alertingNotificationsSentDiscordMetric = alertingNotificationsSentMetric.WithLabelValues("discord")
+				alertingNotificationsSentDiscordMetric.Add(float64(101))
+
+				apiFolderCreateMetric = prometheus.NewCounter(
+					prometheus.CounterOpts{
+						Namespace:   "grafana",
+						Subsystem:   "metrics",
+						Name:        "api_folder_create_total",
+						Help:        "Grafana metric api.folder.create.",
+						ConstLabels: constLabels,
+					},
+				)
+				apiFolderCreateMetric.Add(float64(102))
+
+				apiFolderGetCountMetric = prometheus.MustNewConstMetric(
+					prometheus.NewDesc("grafana_metrics_api_folder_get_seconds_count", "Grafana metric api.folder.get (number of observations).", nil, constLabels),
+					prometheus.CounterValue,
+					float64(103),
+				)
+
+				apiFolderGetP99Metric = prometheus.MustNewConstMetric(
+					prometheus.NewDesc("grafana_metrics_api_folder_get_seconds", "Grafana metric api.folder.get.", []string{"quantile"}, constLabels),
+					prometheus.GaugeValue,
+					float64(108)/1000,
+					"0.99",
+				)
+
+				statTotalsStatActiveUsersMetric = prometheus.NewGauge(
+					prometheus.GaugeOpts{
+						Namespace:   "grafana",
+						Subsystem:   "metrics",
+						Name:        "stat_totals_stat_active_users",
+						Help:        "Grafana metric stat_totals.stat_active_users.",
+						ConstLabels: constLabels,
+					},
+				)
+				statTotalsStatActiveUsersMetric.Set(float64(109))
+			})
+
+			It("returns a grafana_metrics_alerting_notifications_sent_total metric with a type discord", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentDiscordMetric)))
+			})
+
+			It("returns a grafana_metrics_api_folder_create_total metric", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiFolderCreateMetric)))
+			})
+
+			It("returns a grafana_metrics_api_folder_get_seconds metric", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiFolderGetP99Metric)))
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiFolderGetCountMetric)))
+			})
+
+			It("returns a grafana_metrics_stat_totals_stat_active_users metric", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(statTotalsStatActiveUsersMetric)))
+			})
+		})
+
+		Context("when Grafana returns metrics exported as the same series", func() {
+			var (
+				alertingNotificationsSentLineMetric prometheus.Counter
+				apiFooBarMetric                     prometheus.Gauge
+			)
+
+			BeforeEach(func() {
+				metricsResponse["alerting.notifications_sent.type_line"] = grafana.Metric{
+					Counter: &grafana.Counter{Count: int64(110)},
+				}
+				metricsResponse["api.foo-bar"] = grafana.Metric{
+					Gauge: &grafana.Gauge{Value: float64(111)},
+				}
+				metricsResponse["api.foo_bar"] = grafana.Metric{
+					Gauge: &grafana.Gauge{Value: float64(112)},
+				}
+
+				alertingNotificationsSentLineMetric = alertingNotificationsSentMetric.WithLabelValues("line")
+				alertingNotificationsSentLineMetric.Add(float64(110))
+
+				apiFooBarMetric = prometheus.NewGauge(
+					prometheus.GaugeOpts{
+						Namespace:   "grafana",
+						Subsystem:   "metrics",
+						Name:        "api_foo_bar",
+						Help:        "Grafana metric api.foo-bar.",
+						ConstLabels: constLabels,
+					},
+				)
+				apiFooBarMetric.Set(float64(111))
+			})
+
+			It("returns a grafana_metrics_alerting_notifications_sent_total metric summing up the counters", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingNotificationsSentLineMetric)))
+			})
+
+			It("returns a grafana_metrics_api_foo_bar metric with the first gauge", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiFooBarMetric)))
+			})
+
+			It("can be gathered", func() {
+				registry := prometheus.NewRegistry()
+				registry.MustRegister(metricsCollector)
+
+				_, err := registry.Gather()
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("when legacy metric names are enabled", func() {
+			var (
+				apiLoginPostLegacyMetric          prometheus.Gauge
+				apiResponsesLegacyMetric          *prometheus.GaugeVec
+				alertingExecutionTimeLegacyMetric *prometheus.GaugeVec
+			)
+
+			BeforeEach(func() {
+				legacyMetricNames = true
+
+				apiLoginPostLegacyMetric = prometheus.NewGauge(
+					prometheus.GaugeOpts{
+						Namespace:   "grafana",
+						Subsystem:   "metrics",
+						Name:        "api_login_post",
+						Help:        "Number of calls to Login Post API.",
+						ConstLabels: constLabels,
+					},
+				)
+				apiLoginPostLegacyMetric.Set(float64(apiLoginPostCount))
+
+				apiResponsesLegacyMetric = prometheus.NewGaugeVec(
+					prometheus.GaugeOpts{
+						Namespace:   "grafana",
+						Subsystem:   "metrics",
+						Name:        "api_responses",
+						Help:        "Number of API responses.",
+						ConstLabels: constLabels,
+					},
+					[]string{"code"},
+				)
+				apiResponsesLegacyMetric.WithLabelValues("200").Set(float64(apiRespStatusCode200Count))
+
+				alertingExecutionTimeLegacyMetric = prometheus.NewGaugeVec(
+					prometheus.GaugeOpts{
+						Namespace:   "grafana",
+						Subsystem:   "metrics",
+						Name:        "alerting_execution_time",
+						Help:        "Alerting execution time.",
+						ConstLabels: constLabels,
+					},
+					[]string{"metric"},
+				)
+				alertingExecutionTimeLegacyMetric.WithLabelValues("p99").Set(alertingExecutionTimeP99)
+			})
+
+			It("returns a grafana_metrics_api_login_post_total metric", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiLoginPostMetric)))
+			})
+
+			It("returns a grafana_metrics_api_login_post metric", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiLoginPostLegacyMetric)))
+			})
+
+			It("returns a grafana_metrics_api_responses metric with a code 200", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(apiResponsesLegacyMetric.WithLabelValues("200"))))
+			})
+
+			It("returns a grafana_metrics_alerting_execution_time metric with a metric p99", func() {
+				Eventually(metrics, "2s").Should(Receive(PrometheusMetric(alertingExecutionTimeLegacyMetric.WithLabelValues("p99"))))
+			})
+		})
+
 		Context("when it fails to list the security groups", func() {
 			BeforeEach(func() {
 				grafanaClient.GetMetricsReturns(metricsResponse, errors.New("error"))
@@ -1338,5 +1726,31 @@ var _ = Describe("MetricsCollectors", func() {
 				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
 			})
 		})
+
+		Context("when the scrape times out", func() {
+			var (
+				cancel context.CancelFunc
+			)
+
+			BeforeEach(func() {
+				ctx, cancel = context.WithDeadline(context.Background(), time.Now())
+				grafanaClient.GetMetricsReturns(metricsResponse, errors.New("error"))
+
+				scrapeTimeoutsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("returns a grafana_metrics_scrape_timeouts_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeTimeoutsTotalMetric)))
+			})
+
+			It("returns a grafana_metrics_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
 	})
 })
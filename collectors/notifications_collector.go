@@ -0,0 +1,278 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+type NotificationsCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	notificationChannelInfoDesc     *prometheus.Desc
+	contactPointInfoDesc            *prometheus.Desc
+	lastNotifyAttemptDesc           *prometheus.Desc
+	lastNotifyFailedDesc            *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewNotificationsCollector returns a collector of the legacy notification
+// channels and the Grafana Alerting contact points of the orgs matching the
+// org filter.
+func NewNotificationsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter) *NotificationsCollector {
+	notificationChannelInfoDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "notification_channel", "info"),
+		"Grafana Legacy Notification Channel information.",
+		[]string{"org_id", "org_name", "uid", "name", "type", "is_default", "send_reminder", "disable_resolve"},
+		constLabels,
+	)
+
+	contactPointInfoDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "contact_point", "info"),
+		"Grafana Alerting Contact Point integration information.",
+		[]string{"org_id", "org_name", "uid", "name", "type", "disable_resolve"},
+		constLabels,
+	)
+
+	lastNotifyAttemptDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "contact_point", "last_notify_attempt_timestamp_seconds"),
+		"Number of seconds since 1970 since the last notification attempt of the Grafana Alerting Contact Point integration.",
+		[]string{"org_id", "org_name", "name", "integration"},
+		constLabels,
+	)
+
+	lastNotifyFailedDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "contact_point", "last_notify_failed"),
+		"Whether the last notification attempt of the Grafana Alerting Contact Point integration failed (1 for failure, 0 for success).",
+		[]string{"org_id", "org_name", "name", "integration"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "notifications",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Notifications scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "notifications",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Notifications scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "notifications",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Notifications scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "notifications",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Notifications resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "notifications",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Notifications.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "notifications",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Notifications.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	notificationsCollector := &NotificationsCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		notificationChannelInfoDesc:     notificationChannelInfoDesc,
+		contactPointInfoDesc:            contactPointInfoDesc,
+		lastNotifyAttemptDesc:           lastNotifyAttemptDesc,
+		lastNotifyFailedDesc:            lastNotifyFailedDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return notificationsCollector
+}
+
+func (c *NotificationsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.notificationChannelInfoDesc
+	ch <- c.contactPointInfoDesc
+	ch <- c.lastNotifyAttemptDesc
+	ch <- c.lastNotifyFailedDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *NotificationsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *NotificationsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportNotificationsMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Notifications metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Notifications metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *NotificationsCollector) reportNotificationsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "notifications", func(org grafana.Org) error {
+		orgMetrics, err := c.orgNotificationsMetrics(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range orgMetrics {
+			ch <- metric
+		}
+
+		return nil
+	})
+}
+
+// orgNotificationsMetrics returns the metrics of the notification channels
+// and the contact points of the org. Legacy notification channels were
+// removed in Grafana 11 and contact points appeared in Grafana 9, so an
+// endpoint not found is skipped.
+func (c *NotificationsCollector) orgNotificationsMetrics(ctx context.Context, org grafana.Org) ([]prometheus.Metric, error) {
+	var metrics []prometheus.Metric
+
+	orgID := strconv.FormatInt(org.ID, 10)
+
+	notificationChannels, err := c.grafanaClient.GetNotificationChannels(ctx, org.ID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	for _, notificationChannel := range notificationChannels {
+		metrics = append(metrics, prometheus.MustNewConstMetric(
+			c.notificationChannelInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			orgID,
+			org.Name,
+			notificationChannel.UID,
+			notificationChannel.Name,
+			notificationChannel.Type,
+			strconv.FormatBool(notificationChannel.IsDefault),
+			strconv.FormatBool(notificationChannel.SendReminder),
+			strconv.FormatBool(notificationChannel.DisableResolveMessage),
+		))
+	}
+
+	contactPoints, err := c.grafanaClient.GetContactPoints(ctx, org.ID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	for _, contactPoint := range contactPoints {
+		metrics = append(metrics, prometheus.MustNewConstMetric(
+			c.contactPointInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			orgID,
+			org.Name,
+			contactPoint.UID,
+			contactPoint.Name,
+			contactPoint.Type,
+			strconv.FormatBool(contactPoint.DisableResolveMessage),
+		))
+	}
+
+	receivers, err := c.grafanaClient.GetReceivers(ctx, org.ID)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+
+	for _, receiver := range receivers {
+		for i, integration := range receiver.Integrations {
+			if integration.LastNotifyAttempt.IsZero() {
+				continue
+			}
+
+			// A contact point may have several integrations of the same
+			// type, so they are named after their index like the
+			// Alertmanager does (i.e. `slack[0]`).
+			integrationName := fmt.Sprintf("%s[%d]", integration.Name, i)
+
+			lastNotifyFailed := float64(0)
+			if integration.LastNotifyAttemptError != "" {
+				lastNotifyFailed = float64(1)
+			}
+
+			metrics = append(metrics, prometheus.MustNewConstMetric(c.lastNotifyAttemptDesc, prometheus.GaugeValue, float64(integration.LastNotifyAttempt.Unix()), orgID, org.Name, receiver.Name, integrationName))
+			metrics = append(metrics, prometheus.MustNewConstMetric(c.lastNotifyFailedDesc, prometheus.GaugeValue, lastNotifyFailed, orgID, org.Name, receiver.Name, integrationName))
+		}
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,346 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("NotificationsCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+
+		notificationChannelInfoDesc     *prometheus.Desc
+		contactPointInfoDesc            *prometheus.Desc
+		lastNotifyAttemptDesc           *prometheus.Desc
+		lastNotifyFailedDesc            *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		notificationsCollector *NotificationsCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+
+		notificationChannelInfoDesc = prometheus.NewDesc(
+			"grafana_notification_channel_info",
+			"Grafana Legacy Notification Channel information.",
+			[]string{"org_id", "org_name", "uid", "name", "type", "is_default", "send_reminder", "disable_resolve"},
+			constLabels,
+		)
+
+		contactPointInfoDesc = prometheus.NewDesc(
+			"grafana_contact_point_info",
+			"Grafana Alerting Contact Point integration information.",
+			[]string{"org_id", "org_name", "uid", "name", "type", "disable_resolve"},
+			constLabels,
+		)
+
+		lastNotifyAttemptDesc = prometheus.NewDesc(
+			"grafana_contact_point_last_notify_attempt_timestamp_seconds",
+			"Number of seconds since 1970 since the last notification attempt of the Grafana Alerting Contact Point integration.",
+			[]string{"org_id", "org_name", "name", "integration"},
+			constLabels,
+		)
+
+		lastNotifyFailedDesc = prometheus.NewDesc(
+			"grafana_contact_point_last_notify_failed",
+			"Whether the last notification attempt of the Grafana Alerting Contact Point integration failed (1 for failure, 0 for success).",
+			[]string{"org_id", "org_name", "name", "integration"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "notifications",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Notifications scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "notifications",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Notifications scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "notifications",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Notifications scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "notifications",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Notifications resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "notifications",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Notifications.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "notifications",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Notifications.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		notificationsCollector = NewNotificationsCollector(grafanaClient, constLabels, orgFilter)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go notificationsCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_notification_channel_info metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(notificationChannelInfoDesc)))
+		})
+
+		It("returns a grafana_contact_point_info metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(contactPointInfoDesc)))
+		})
+
+		It("returns a grafana_contact_point_last_notify_attempt_timestamp_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastNotifyAttemptDesc)))
+		})
+
+		It("returns a grafana_contact_point_last_notify_failed metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastNotifyFailedDesc)))
+		})
+
+		It("returns a grafana_notifications_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_notifications_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_notifications_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_notifications_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_notifications_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_notifications_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx               context.Context
+			metrics           chan prometheus.Metric
+			collections       backgroundCollections
+			lastNotifyAttempt time.Time
+
+			metricDesc  = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+			constMetric = func(desc *prometheus.Desc, value float64, labelValues ...string) OmegaMatcher {
+				return And(
+					WithTransform(metricDesc, Equal(desc)),
+					PrometheusMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)),
+				)
+			}
+		)
+
+		BeforeEach(func() {
+			lastNotifyAttempt = time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetNotificationChannelsStub = func(ctx context.Context, orgID int64) ([]grafana.NotificationChannel, error) {
+				if orgID == 2 {
+					return nil, grafana.StatusCodeError{Resource: "notification channels", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.NotificationChannel{
+					{ID: 1, UID: "fake-notification-channel", Name: "Fake Notification Channel", Type: "slack", IsDefault: true, SendReminder: true},
+				}, nil
+			}
+			grafanaClient.GetContactPointsStub = func(ctx context.Context, orgID int64) ([]grafana.ContactPoint, error) {
+				if orgID == 1 {
+					return nil, grafana.StatusCodeError{Resource: "contact points", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.ContactPoint{
+					{UID: "fake-contact-point-1", Name: "Fake Contact Point", Type: "slack"},
+					{UID: "fake-contact-point-2", Name: "Fake Contact Point", Type: "email", DisableResolveMessage: true},
+				}, nil
+			}
+			lastNotifyAttempt := lastNotifyAttempt
+			grafanaClient.GetReceiversStub = func(ctx context.Context, orgID int64) ([]grafana.Receiver, error) {
+				if orgID == 1 {
+					return nil, grafana.StatusCodeError{Resource: "receivers", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.Receiver{
+					{
+						Name:   "Fake Contact Point",
+						Active: true,
+						Integrations: []grafana.ReceiverIntegration{
+							{Name: "slack", LastNotifyAttempt: lastNotifyAttempt, LastNotifyAttemptError: "fake-error"},
+							{Name: "email", LastNotifyAttempt: lastNotifyAttempt},
+							{Name: "email"},
+						},
+					},
+				}, nil
+			}
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			collections.Collect(ctx, notificationsCollector, metrics)
+		})
+
+		AfterEach(func() {
+			collections.Drain(metrics)
+		})
+
+		It("returns a grafana_notification_channel_info metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(notificationChannelInfoDesc, 1, "1", "Main Org.", "fake-notification-channel", "Fake Notification Channel", "slack", "true", "true", "false")))
+		})
+
+		It("returns a grafana_contact_point_info metric for every integration", func() {
+			Eventually(metrics).Should(Receive(constMetric(contactPointInfoDesc, 1, "2", "fake-org", "fake-contact-point-2", "Fake Contact Point", "email", "true")))
+		})
+
+		It("returns a grafana_contact_point_last_notify_attempt_timestamp_seconds metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(lastNotifyAttemptDesc, float64(lastNotifyAttempt.Unix()), "2", "fake-org", "Fake Contact Point", "slack[0]")))
+		})
+
+		It("returns a grafana_contact_point_last_notify_failed metric for the failed integrations", func() {
+			Eventually(metrics).Should(Receive(constMetric(lastNotifyFailedDesc, 1, "2", "fake-org", "Fake Contact Point", "slack[0]")))
+		})
+
+		It("returns a grafana_contact_point_last_notify_failed metric for the successful integrations", func() {
+			Eventually(metrics).Should(Receive(constMetric(lastNotifyFailedDesc, 0, "2", "fake-org", "Fake Contact Point", "email[1]")))
+		})
+
+		It("does not return metrics for the integrations that never notified", func() {
+			Consistently(metrics).ShouldNot(Receive(constMetric(lastNotifyFailedDesc, 0, "2", "fake-org", "Fake Contact Point", "email[2]")))
+		})
+
+		It("returns a grafana_notifications_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_notifications_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_notifications_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetContactPointsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the receivers of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetReceiversReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("does not return the metrics of the org", func() {
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(notificationChannelInfoDesc))))
+			})
+
+			It("returns a grafana_notifications_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_notifications_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_notifications_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_notifications_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
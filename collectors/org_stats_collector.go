@@ -0,0 +1,350 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// OrgFilter selects the Grafana orgs to scrape, to bound the cardinality of
+// the per org metrics. The orgs are matched by id or by name.
+type OrgFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// Matches returns whether the org is included (or the include list is empty)
+// and is not excluded.
+func (f OrgFilter) Matches(org grafana.Org) bool {
+	if len(f.Include) > 0 && !matchesOrg(f.Include, org) {
+		return false
+	}
+
+	return !matchesOrg(f.Exclude, org)
+}
+
+func matchesOrg(orgs []string, org grafana.Org) bool {
+	orgID := strconv.FormatInt(org.ID, 10)
+	for _, o := range orgs {
+		if o == orgID || o == org.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forEachOrg calls fn for every org of the Grafana matched by the filter. It
+// keeps on scraping the other orgs when an org fails, so a single broken org
+// does not blank the metrics of all the others, and returns the errors of the
+// failed orgs at once. It gives up as soon as the context is done.
+func forEachOrg(ctx context.Context, grafanaClient grafana.Client, filter OrgFilter, resource string, fn func(org grafana.Org) error) error {
+	orgs, err := grafanaClient.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var orgErrors []string
+	for _, org := range orgs {
+		if !filter.Matches(org) {
+			continue
+		}
+
+		if err := fn(org); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			orgErrors = append(orgErrors, fmt.Sprintf("Error getting %s of org `%s`: %s", resource, org.Name, err))
+		}
+	}
+
+	if len(orgErrors) > 0 {
+		return errors.New(strings.Join(orgErrors, "; "))
+	}
+
+	return nil
+}
+
+type OrgStatsCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	dashboardsFetcher               *DashboardsFetcher
+	dashboardsDesc                  *prometheus.Desc
+	foldersDesc                     *prometheus.Desc
+	datasourcesDesc                 *prometheus.Desc
+	usersDesc                       *prometheus.Desc
+	alertRulesDesc                  *prometheus.Desc
+	apiKeysDesc                     *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+func NewOrgStatsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, dashboardsFetcher *DashboardsFetcher) *OrgStatsCollector {
+	dashboardsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_stats", "dashboards"),
+		"Number of Grafana Dashboards per Org.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	foldersDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_stats", "folders"),
+		"Number of Grafana Folders per Org.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	datasourcesDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_stats", "datasources"),
+		"Number of Grafana Datasources per Org.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	usersDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_stats", "users"),
+		"Number of Grafana Users per Org.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	alertRulesDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_stats", "alert_rules"),
+		"Number of Grafana Alert Rules per Org.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	apiKeysDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_stats", "api_keys"),
+		"Number of Grafana API Keys per Org.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "org_stats",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Org Stats scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "org_stats",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Org Stats scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "org_stats",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Org Stats scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "org_stats",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Org Stats resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "org_stats",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Org Stats.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "org_stats",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Org Stats.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	orgStatsCollector := &OrgStatsCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		dashboardsFetcher:               dashboardsFetcher,
+		dashboardsDesc:                  dashboardsDesc,
+		foldersDesc:                     foldersDesc,
+		datasourcesDesc:                 datasourcesDesc,
+		usersDesc:                       usersDesc,
+		alertRulesDesc:                  alertRulesDesc,
+		apiKeysDesc:                     apiKeysDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return orgStatsCollector
+}
+
+func (c *OrgStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.dashboardsDesc
+	ch <- c.foldersDesc
+	ch <- c.datasourcesDesc
+	ch <- c.usersDesc
+	ch <- c.alertRulesDesc
+	ch <- c.apiKeysDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *OrgStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *OrgStatsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportOrgStatsMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Org Stats metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Org Stats metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *OrgStatsCollector) reportOrgStatsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "stats", func(org grafana.Org) error {
+		orgMetrics, err := c.orgStatsMetrics(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range orgMetrics {
+			ch <- metric
+		}
+
+		return nil
+	})
+}
+
+// orgStatsMetrics returns the stats metrics of the org. The metrics are built
+// on every scrape, so concurrent scrapes do not reset each other's series.
+func (c *OrgStatsCollector) orgStatsMetrics(ctx context.Context, org grafana.Org) ([]prometheus.Metric, error) {
+	var metrics []prometheus.Metric
+
+	orgID := strconv.FormatInt(org.ID, 10)
+	orgMetric := func(desc *prometheus.Desc, value int) prometheus.Metric {
+		return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value), orgID, org.Name)
+	}
+
+	dashboards, err := c.dashboardsFetcher.OrgSearchHits(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, orgMetric(c.dashboardsDesc, len(dashboards)))
+
+	folders, err := c.grafanaClient.GetFolders(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, orgMetric(c.foldersDesc, len(folders)))
+
+	datasources, err := c.grafanaClient.GetDatasources(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, orgMetric(c.datasourcesDesc, len(datasources)))
+
+	orgUsers, err := c.grafanaClient.GetOrgUsers(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	metrics = append(metrics, orgMetric(c.usersDesc, len(orgUsers)))
+
+	// Grafana Alerting rules are only available since Grafana 9, and legacy
+	// dashboard alerts were removed in Grafana 11.
+	alertRules, err := c.grafanaClient.GetAlertRules(ctx, org.ID)
+	if err == nil {
+		metrics = append(metrics, orgMetric(c.alertRulesDesc, len(alertRules)))
+	} else if isNotFound(err) {
+		alerts, err := c.grafanaClient.GetAlerts(ctx, org.ID)
+		if err == nil {
+			metrics = append(metrics, orgMetric(c.alertRulesDesc, len(alerts)))
+		} else if !isNotFound(err) {
+			return nil, err
+		}
+	} else {
+		return nil, err
+	}
+
+	// API keys were replaced by service accounts in Grafana 12.
+	apiKeys, err := c.grafanaClient.GetAPIKeys(ctx, org.ID)
+	if err == nil {
+		metrics = append(metrics, orgMetric(c.apiKeysDesc, len(apiKeys)))
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// isNotFound returns whether the error is a Grafana `404` http status code
+// error, meaning that the endpoint is not available in the Grafana version.
+func isNotFound(err error) bool {
+	statusCodeErr, ok := err.(grafana.StatusCodeError)
+
+	return ok && statusCodeErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,501 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("OrgFilter", func() {
+	var (
+		orgFilter OrgFilter
+		org       = grafana.Org{ID: 2, Name: "fake-org"}
+	)
+
+	BeforeEach(func() {
+		orgFilter = OrgFilter{}
+	})
+
+	It("matches every org", func() {
+		Expect(orgFilter.Matches(org)).To(BeTrue())
+	})
+
+	Context("when the org is included by id", func() {
+		BeforeEach(func() {
+			orgFilter.Include = []string{"2"}
+		})
+
+		It("matches the org", func() {
+			Expect(orgFilter.Matches(org)).To(BeTrue())
+		})
+	})
+
+	Context("when the org is not included", func() {
+		BeforeEach(func() {
+			orgFilter.Include = []string{"another-org"}
+		})
+
+		It("does not match the org", func() {
+			Expect(orgFilter.Matches(org)).To(BeFalse())
+		})
+	})
+
+	Context("when the org is excluded by name", func() {
+		BeforeEach(func() {
+			orgFilter.Include = []string{"2"}
+			orgFilter.Exclude = []string{"fake-org"}
+		})
+
+		It("does not match the org", func() {
+			Expect(orgFilter.Matches(org)).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("OrgStatsCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+
+		dashboardsMetric                *prometheus.GaugeVec
+		foldersMetric                   *prometheus.GaugeVec
+		datasourcesMetric               *prometheus.GaugeVec
+		usersMetric                     *prometheus.GaugeVec
+		alertRulesMetric                *prometheus.GaugeVec
+		apiKeysMetric                   *prometheus.GaugeVec
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		orgStatsCollector *OrgStatsCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+
+		dashboardsMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "dashboards",
+				Help:        "Number of Grafana Dashboards per Org.",
+				ConstLabels: constLabels,
+			},
+			[]string{"org_id", "org_name"},
+		)
+		dashboardsMetric.WithLabelValues("1", "Main Org.").Set(2)
+		dashboardsMetric.WithLabelValues("2", "fake-org").Set(1)
+
+		foldersMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "folders",
+				Help:        "Number of Grafana Folders per Org.",
+				ConstLabels: constLabels,
+			},
+			[]string{"org_id", "org_name"},
+		)
+		foldersMetric.WithLabelValues("1", "Main Org.").Set(1)
+
+		datasourcesMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "datasources",
+				Help:        "Number of Grafana Datasources per Org.",
+				ConstLabels: constLabels,
+			},
+			[]string{"org_id", "org_name"},
+		)
+		datasourcesMetric.WithLabelValues("1", "Main Org.").Set(1)
+
+		usersMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "users",
+				Help:        "Number of Grafana Users per Org.",
+				ConstLabels: constLabels,
+			},
+			[]string{"org_id", "org_name"},
+		)
+		usersMetric.WithLabelValues("1", "Main Org.").Set(3)
+
+		alertRulesMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "alert_rules",
+				Help:        "Number of Grafana Alert Rules per Org.",
+				ConstLabels: constLabels,
+			},
+			[]string{"org_id", "org_name"},
+		)
+		alertRulesMetric.WithLabelValues("1", "Main Org.").Set(2)
+
+		apiKeysMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "api_keys",
+				Help:        "Number of Grafana API Keys per Org.",
+				ConstLabels: constLabels,
+			},
+			[]string{"org_id", "org_name"},
+		)
+		apiKeysMetric.WithLabelValues("1", "Main Org.").Set(1)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Org Stats scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Org Stats scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Org Stats scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Org Stats resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Org Stats.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "org_stats",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Org Stats.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		orgStatsCollector = NewOrgStatsCollector(grafanaClient, constLabels, orgFilter, NewDashboardsFetcher(grafanaClient))
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go orgStatsCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_org_stats_dashboards metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(dashboardsMetric.WithLabelValues("1", "Main Org.").Desc())))
+		})
+
+		It("returns a grafana_org_stats_folders metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(foldersMetric.WithLabelValues("1", "Main Org.").Desc())))
+		})
+
+		It("returns a grafana_org_stats_datasources metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(datasourcesMetric.WithLabelValues("1", "Main Org.").Desc())))
+		})
+
+		It("returns a grafana_org_stats_users metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(usersMetric.WithLabelValues("1", "Main Org.").Desc())))
+		})
+
+		It("returns a grafana_org_stats_alert_rules metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(alertRulesMetric.WithLabelValues("1", "Main Org.").Desc())))
+		})
+
+		It("returns a grafana_org_stats_api_keys metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(apiKeysMetric.WithLabelValues("1", "Main Org.").Desc())))
+		})
+
+		It("returns a grafana_org_stats_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_org_stats_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_org_stats_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_org_stats_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_org_stats_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_org_stats_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+		)
+
+		BeforeEach(func() {
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetDashboardsStub = func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+				if orgID == 1 {
+					return []grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil
+				}
+				return []grafana.SearchHit{{UID: "fake-dashboard-3"}}, nil
+			}
+			grafanaClient.GetFoldersReturns([]grafana.Folder{{UID: "fake-folder"}}, nil)
+			grafanaClient.GetDatasourcesReturns([]grafana.Datasource{{UID: "fake-datasource"}}, nil)
+			grafanaClient.GetOrgUsersReturns([]grafana.OrgUser{{Login: "admin"}, {Login: "fake-user-1"}, {Login: "fake-user-2"}}, nil)
+			grafanaClient.GetAlertRulesReturns([]grafana.AlertRule{{UID: "fake-alert-rule-1"}, {UID: "fake-alert-rule-2"}}, nil)
+			grafanaClient.GetAPIKeysReturns([]grafana.APIKey{{Name: "fake-api-key"}}, nil)
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go orgStatsCollector.CollectContext(ctx, metrics)
+		})
+
+		It("scrapes each org with its org id", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+			Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(2))
+			_, orgID := grafanaClient.GetDashboardsArgsForCall(0)
+			Expect(orgID).To(Equal(int64(1)))
+			_, orgID = grafanaClient.GetDashboardsArgsForCall(1)
+			Expect(orgID).To(Equal(int64(2)))
+		})
+
+		It("returns a grafana_org_stats_dashboards metric for the first org", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(dashboardsMetric.WithLabelValues("1", "Main Org."))))
+		})
+
+		It("returns a grafana_org_stats_dashboards metric for the second org", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(dashboardsMetric.WithLabelValues("2", "fake-org"))))
+		})
+
+		It("returns a grafana_org_stats_folders metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(foldersMetric.WithLabelValues("1", "Main Org."))))
+		})
+
+		It("returns a grafana_org_stats_datasources metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(datasourcesMetric.WithLabelValues("1", "Main Org."))))
+		})
+
+		It("returns a grafana_org_stats_users metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(usersMetric.WithLabelValues("1", "Main Org."))))
+		})
+
+		It("returns a grafana_org_stats_alert_rules metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(alertRulesMetric.WithLabelValues("1", "Main Org."))))
+		})
+
+		It("returns a grafana_org_stats_api_keys metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(apiKeysMetric.WithLabelValues("1", "Main Org."))))
+		})
+
+		It("returns a grafana_org_stats_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_org_stats_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_org_stats_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"fake-org"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the Grafana Alerting API is not available", func() {
+			BeforeEach(func() {
+				grafanaClient.GetAlertRulesReturns(nil, grafana.StatusCodeError{Resource: "alert rules", StatusCode: http.StatusNotFound})
+				grafanaClient.GetAlertsReturns([]grafana.Alert{{Name: "fake-alert-1"}, {Name: "fake-alert-2"}}, nil)
+			})
+
+			It("returns a grafana_org_stats_alert_rules metric with the legacy alerts", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(alertRulesMetric.WithLabelValues("1", "Main Org."))))
+			})
+		})
+
+		Context("when the API keys API is not available", func() {
+			BeforeEach(func() {
+				grafanaClient.GetAPIKeysReturns(nil, grafana.StatusCodeError{Resource: "api keys", StatusCode: http.StatusNotFound})
+			})
+
+			It("does not return a grafana_org_stats_api_keys metric", func() {
+				metricDesc := func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(apiKeysMetric.WithLabelValues("1", "Main Org.").Desc()))))
+			})
+
+			It("returns a grafana_org_stats_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the stats of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDashboardsStub = func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+					if orgID == 1 {
+						return []grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil
+					}
+					return nil, errors.New("error")
+				}
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_org_stats_dashboards metric for the other orgs", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(dashboardsMetric.WithLabelValues("1", "Main Org."))))
+			})
+
+			It("returns a grafana_org_stats_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_org_stats_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it is scraped concurrently", func() {
+			var (
+				otherCtx      context.Context
+				cancelOther   context.CancelFunc
+				otherMetrics  chan prometheus.Metric
+				scrapeWaiting chan struct{}
+				collections   backgroundCollections
+			)
+
+			BeforeEach(func() {
+				otherCtx, cancelOther = context.WithCancel(context.Background())
+				otherMetrics = make(chan prometheus.Metric)
+				scrapeWaiting = make(chan struct{})
+
+				// The first scrape waits on its last org request until the other
+				// scrape has started, which then hangs on its first org request.
+				other, waiting, otherStarted := otherCtx, scrapeWaiting, make(chan struct{})
+				getDashboards := grafanaClient.GetDashboardsStub
+				grafanaClient.GetDashboardsStub = func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+					if ctx == other {
+						close(otherStarted)
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+					return getDashboards(ctx, orgID)
+				}
+				grafanaClient.GetAPIKeysStub = func(ctx context.Context, orgID int64) ([]grafana.APIKey, error) {
+					if orgID == 1 {
+						close(waiting)
+						<-otherStarted
+					}
+					return []grafana.APIKey{{Name: "fake-api-key"}}, nil
+				}
+			})
+
+			JustBeforeEach(func() {
+				Eventually(scrapeWaiting).Should(BeClosed())
+				collections.Collect(otherCtx, orgStatsCollector, otherMetrics)
+			})
+
+			AfterEach(func() {
+				cancelOther()
+				collections.Drain(otherMetrics)
+			})
+
+			It("returns the grafana_org_stats_dashboards metrics of its own scrape", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(dashboardsMetric.WithLabelValues("1", "Main Org."))))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_org_stats_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_org_stats_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
@@ -0,0 +1,491 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// unknownDatasourceType is the datasource type of the panels whose datasource
+// cannot be resolved (i.e. it was deleted).
+const unknownDatasourceType = "unknown"
+
+// builtInDatasourceTypes maps the names and uids of the Grafana built-in
+// datasources to a datasource type. The server side expressions are referenced
+// by the `__expr__` uid, or by the `Expression` name before Grafana 8.3.
+var builtInDatasourceTypes = map[string]string{
+	"-- Mixed --":     "mixed",
+	"-- Grafana --":   "grafana",
+	"-- Dashboard --": "dashboard",
+	"grafana":         "grafana",
+	"__expr__":        "__expr__",
+	"Expression":      "__expr__",
+}
+
+type panelsKey struct {
+	orgID          string
+	orgName        string
+	panelType      string
+	datasourceType string
+}
+
+type PanelsCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	dashboardsFetcher               *DashboardsFetcher
+	denyTypes                       map[string]bool
+	panelsDesc                      *prometheus.Desc
+	deniedPanelsDesc                *prometheus.Desc
+	brokenDatasourceRefsDesc        *prometheus.Desc
+	dashboardBrokenRefsDesc         *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewPanelsCollector returns a collector of the panels of the dashboards of
+// the orgs matching the org filter. The dashboards using a panel of a deny
+// type are exported as `grafana_dashboard_denied_panels` series, and the
+// dashboards referencing missing datasources as
+// `grafana_dashboard_broken_datasource_refs` series.
+func NewPanelsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, dashboardsFetcher *DashboardsFetcher, denyTypes []string) *PanelsCollector {
+	panelsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "", "panels"),
+		"Number of Grafana Dashboard Panels.",
+		[]string{"org_id", "org_name", "type", "datasource_type"},
+		constLabels,
+	)
+
+	deniedPanelsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "denied_panels"),
+		"Number of Grafana Dashboard Panels of a deny listed type.",
+		[]string{"org_id", "uid", "title", "folder_uid", "folder_title", "type"},
+		constLabels,
+	)
+
+	brokenDatasourceRefsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "", "broken_datasource_refs"),
+		"Number of references to missing Grafana Datasources from Grafana Dashboard Panels.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	dashboardBrokenRefsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "dashboard", "broken_datasource_refs"),
+		"Number of references to missing Grafana Datasources from the Grafana Dashboard Panels.",
+		[]string{"org_id", "dashboard_uid", "folder"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "panels",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Panels scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "panels",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Panels scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "panels",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Panels scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "panels",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Panels resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "panels",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Panels.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "panels",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Panels.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	denyTypesSet := map[string]bool{}
+	for _, denyType := range denyTypes {
+		denyTypesSet[denyType] = true
+	}
+
+	panelsCollector := &PanelsCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		dashboardsFetcher:               dashboardsFetcher,
+		denyTypes:                       denyTypesSet,
+		panelsDesc:                      panelsDesc,
+		deniedPanelsDesc:                deniedPanelsDesc,
+		brokenDatasourceRefsDesc:        brokenDatasourceRefsDesc,
+		dashboardBrokenRefsDesc:         dashboardBrokenRefsDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return panelsCollector
+}
+
+func (c *PanelsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.panelsDesc
+	ch <- c.deniedPanelsDesc
+	ch <- c.brokenDatasourceRefsDesc
+	ch <- c.dashboardBrokenRefsDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *PanelsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *PanelsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportPanelsMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Panels metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Panels metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *PanelsCollector) reportPanelsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	panels := map[panelsKey]int{}
+	err := forEachOrg(ctx, c.grafanaClient, c.orgFilter, "panels", func(org grafana.Org) error {
+		return c.reportOrgPanels(ctx, ch, org, panels)
+	})
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+
+	for key, count := range panels {
+		ch <- prometheus.MustNewConstMetric(c.panelsDesc, prometheus.GaugeValue, float64(count), key.orgID, key.orgName, key.panelType, key.datasourceType)
+	}
+
+	return err
+}
+
+// reportOrgPanels counts the panels of the dashboards of the org and reports
+// the dashboards using deny listed panel types or referencing missing
+// datasources. Nothing is counted when the org fails, so its panels are not
+// partially reported.
+func (c *PanelsCollector) reportOrgPanels(ctx context.Context, ch chan<- prometheus.Metric, org grafana.Org, panels map[panelsKey]int) error {
+	datasources, err := c.grafanaClient.GetDatasources(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+	resolver := newDatasourceTypeResolver(datasources)
+
+	orgDashboards, err := c.dashboardsFetcher.OrgDashboards(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	orgID := strconv.FormatInt(org.ID, 10)
+	orgPanels := map[panelsKey]int{}
+	libraryPanels := map[string]*grafana.Panel{}
+	brokenRefs := 0
+	var dashboardMetrics []prometheus.Metric
+	for _, dashboard := range orgDashboards {
+		deniedPanelsByType := map[string]int{}
+		dashboardBrokenRefs := 0
+		for _, panel := range dashboardPanels(dashboard) {
+			if panel.LibraryPanel != nil {
+				libraryPanel, err := c.getLibraryPanel(ctx, org, panel.LibraryPanel.UID, libraryPanels)
+				if err != nil {
+					return err
+				}
+				if libraryPanel == nil {
+					continue
+				}
+				panel = *libraryPanel
+			}
+
+			orgPanels[panelsKey{
+				orgID:          orgID,
+				orgName:        org.Name,
+				panelType:      panel.Type,
+				datasourceType: resolver.resolve(panel.Datasource),
+			}]++
+
+			if c.denyTypes[panel.Type] {
+				deniedPanelsByType[panel.Type]++
+			}
+
+			for _, datasource := range panelDatasources(panel) {
+				if resolver.isBroken(datasource) {
+					dashboardBrokenRefs++
+				}
+			}
+		}
+
+		folderTitle := dashboard.Meta.FolderTitle
+		if dashboard.Meta.FolderUID == "" {
+			folderTitle = generalFolderTitle
+		}
+		for panelType, count := range deniedPanelsByType {
+			dashboardMetrics = append(dashboardMetrics, prometheus.MustNewConstMetric(
+				c.deniedPanelsDesc,
+				prometheus.GaugeValue,
+				float64(count),
+				orgID,
+				dashboard.Dashboard.UID,
+				dashboard.Dashboard.Title,
+				dashboard.Meta.FolderUID,
+				folderTitle,
+				panelType,
+			))
+		}
+
+		if dashboardBrokenRefs > 0 {
+			brokenRefs += dashboardBrokenRefs
+			dashboardMetrics = append(dashboardMetrics, prometheus.MustNewConstMetric(
+				c.dashboardBrokenRefsDesc,
+				prometheus.GaugeValue,
+				float64(dashboardBrokenRefs),
+				orgID,
+				dashboard.Dashboard.UID,
+				folderTitle,
+			))
+		}
+	}
+
+	for key, count := range orgPanels {
+		panels[key] += count
+	}
+	for _, dashboardMetric := range dashboardMetrics {
+		ch <- dashboardMetric
+	}
+	ch <- prometheus.MustNewConstMetric(c.brokenDatasourceRefsDesc, prometheus.GaugeValue, float64(brokenRefs), orgID, org.Name)
+
+	return nil
+}
+
+// getLibraryPanel returns the model of a library panel, requesting each
+// library panel of the org once per scrape. It returns nil when the library
+// panel was deleted.
+func (c *PanelsCollector) getLibraryPanel(ctx context.Context, org grafana.Org, uid string, libraryPanels map[string]*grafana.Panel) (*grafana.Panel, error) {
+	if panel, ok := libraryPanels[uid]; ok {
+		return panel, nil
+	}
+
+	libraryPanel, err := c.grafanaClient.GetLibraryPanel(ctx, org.ID, uid)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		libraryPanels[uid] = nil
+		return nil, nil
+	}
+
+	panel := libraryPanel.Model
+	if panel.Type == "" {
+		panel.Type = libraryPanel.Type
+	}
+	libraryPanels[uid] = &panel
+
+	return &panel, nil
+}
+
+// dashboardPanels returns the panels of the dashboard, including the panels
+// of collapsed rows and of the rows of the dashboards saved before schema
+// version 16. The rows themselves are left out.
+func dashboardPanels(dashboard grafana.Dashboard) []grafana.Panel {
+	panels := flattenPanels(dashboard.Dashboard.Panels)
+	for _, row := range dashboard.Dashboard.Rows {
+		panels = append(panels, flattenPanels(row.Panels)...)
+	}
+
+	return panels
+}
+
+// panelDatasources returns the datasources referenced by the panel and by its
+// targets.
+func panelDatasources(panel grafana.Panel) []*grafana.PanelDatasource {
+	datasources := []*grafana.PanelDatasource{panel.Datasource}
+	for _, target := range panel.Targets {
+		if target.Datasource != nil {
+			datasources = append(datasources, target.Datasource)
+		}
+	}
+
+	return datasources
+}
+
+func flattenPanels(panels []grafana.Panel) []grafana.Panel {
+	var flattened []grafana.Panel
+	for _, panel := range panels {
+		if panel.Type == "row" {
+			flattened = append(flattened, flattenPanels(panel.Panels)...)
+			continue
+		}
+		flattened = append(flattened, panel)
+	}
+
+	return flattened
+}
+
+// datasourceTypeResolver resolves the type of the datasource referenced by a
+// panel, either by uid or by name, against the datasources of an org.
+type datasourceTypeResolver struct {
+	typesByUID  map[string]string
+	typesByName map[string]string
+	defaultType string
+}
+
+func newDatasourceTypeResolver(datasources []grafana.Datasource) datasourceTypeResolver {
+	resolver := datasourceTypeResolver{
+		typesByUID:  map[string]string{},
+		typesByName: map[string]string{},
+		defaultType: unknownDatasourceType,
+	}
+
+	for _, datasource := range datasources {
+		resolver.typesByUID[datasource.UID] = datasource.Type
+		resolver.typesByName[datasource.Name] = datasource.Type
+		if datasource.IsDefault {
+			resolver.defaultType = datasource.Type
+		}
+	}
+
+	return resolver
+}
+
+// resolve returns the type of the datasource. Panels without a datasource use
+// the default datasource of the org, and panels whose datasource is a
+// template variable get the `variable` type.
+func (r datasourceTypeResolver) resolve(datasource *grafana.PanelDatasource) string {
+	if datasource == nil {
+		return r.defaultType
+	}
+
+	if datasource.Type != "" && datasource.Type != "datasource" {
+		return datasource.Type
+	}
+
+	if ref := datasourceRef(datasource); ref != "" {
+		if datasourceType, ok := r.lookup(ref); ok {
+			return datasourceType
+		}
+		return unknownDatasourceType
+	}
+
+	return r.defaultType
+}
+
+// isBroken returns whether the datasource is referenced by a uid or a name
+// missing from the org, so Grafana renders the panel with a "datasource not
+// found" error.
+func (r datasourceTypeResolver) isBroken(datasource *grafana.PanelDatasource) bool {
+	if datasource == nil {
+		return false
+	}
+
+	if ref := datasourceRef(datasource); ref != "" {
+		_, ok := r.lookup(ref)
+		return !ok
+	}
+
+	return false
+}
+
+// lookup returns the type of the datasource referenced by the uid or name, and
+// whether it exists.
+func (r datasourceTypeResolver) lookup(ref string) (string, bool) {
+	if strings.HasPrefix(ref, "$") {
+		return "variable", true
+	}
+	if datasourceType, ok := builtInDatasourceTypes[ref]; ok {
+		return datasourceType, true
+	}
+	if datasourceType, ok := r.typesByUID[ref]; ok {
+		return datasourceType, true
+	}
+	if datasourceType, ok := r.typesByName[ref]; ok {
+		return datasourceType, true
+	}
+	if ref == "default" {
+		return r.defaultType, true
+	}
+
+	return "", false
+}
+
+// datasourceRef returns the uid of the datasource, or its name when referenced
+// by name.
+func datasourceRef(datasource *grafana.PanelDatasource) string {
+	if datasource.UID != "" {
+		return datasource.UID
+	}
+
+	return datasource.Name
+}
@@ -0,0 +1,534 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("PanelsCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+		denyTypes     []string
+
+		panelsDesc                      *prometheus.Desc
+		deniedPanelsDesc                *prometheus.Desc
+		brokenDatasourceRefsDesc        *prometheus.Desc
+		dashboardBrokenRefsDesc         *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		panelsCollector *PanelsCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		denyTypes = []string{"graph"}
+
+		panelsDesc = prometheus.NewDesc(
+			"grafana_panels",
+			"Number of Grafana Dashboard Panels.",
+			[]string{"org_id", "org_name", "type", "datasource_type"},
+			constLabels,
+		)
+
+		deniedPanelsDesc = prometheus.NewDesc(
+			"grafana_dashboard_denied_panels",
+			"Number of Grafana Dashboard Panels of a deny listed type.",
+			[]string{"org_id", "uid", "title", "folder_uid", "folder_title", "type"},
+			constLabels,
+		)
+
+		brokenDatasourceRefsDesc = prometheus.NewDesc(
+			"grafana_broken_datasource_refs",
+			"Number of references to missing Grafana Datasources from Grafana Dashboard Panels.",
+			[]string{"org_id", "org_name"},
+			constLabels,
+		)
+
+		dashboardBrokenRefsDesc = prometheus.NewDesc(
+			"grafana_dashboard_broken_datasource_refs",
+			"Number of references to missing Grafana Datasources from the Grafana Dashboard Panels.",
+			[]string{"org_id", "dashboard_uid", "folder"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "panels",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Panels scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "panels",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Panels scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "panels",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Panels scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "panels",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Panels resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "panels",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Panels.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "panels",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Panels.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		panelsCollector = NewPanelsCollector(grafanaClient, constLabels, orgFilter, NewDashboardsFetcher(grafanaClient), denyTypes)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go panelsCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_panels metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(panelsDesc)))
+		})
+
+		It("returns a grafana_dashboard_denied_panels metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(deniedPanelsDesc)))
+		})
+
+		It("returns a grafana_broken_datasource_refs metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(brokenDatasourceRefsDesc)))
+		})
+
+		It("returns a grafana_dashboard_broken_datasource_refs metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(dashboardBrokenRefsDesc)))
+		})
+
+		It("returns a grafana_panels_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_panels_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_panels_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_panels_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_panels_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_panels_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx         context.Context
+			metrics     chan prometheus.Metric
+			collections backgroundCollections
+
+			metricDesc = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+		)
+
+		BeforeEach(func() {
+			dashboards := map[string]grafana.Dashboard{
+				"fake-dashboard-1": {
+					Dashboard: grafana.DashboardModel{
+						UID:           "fake-dashboard-1",
+						Title:         "Fake Dashboard 1",
+						SchemaVersion: 36,
+						Panels: []grafana.Panel{
+							{Type: "timeseries", Datasource: &grafana.PanelDatasource{UID: "fake-prometheus-uid", Type: "prometheus"}, Targets: []grafana.PanelTarget{
+								{RefID: "A"},
+								{RefID: "B", Datasource: &grafana.PanelDatasource{Name: "Fake Deleted"}},
+							}},
+							{Type: "row", Panels: []grafana.Panel{
+								{Type: "graph", Datasource: &grafana.PanelDatasource{Name: "Fake Loki"}},
+							}},
+							{LibraryPanel: &grafana.LibraryPanelRef{UID: "fake-library-panel"}},
+						},
+					},
+				},
+				"fake-dashboard-2": {
+					Dashboard: grafana.DashboardModel{
+						UID:           "fake-dashboard-2",
+						Title:         "Fake Dashboard 2",
+						SchemaVersion: 14,
+						Rows: []grafana.DashboardRow{
+							{Panels: []grafana.Panel{
+								{Type: "graph"},
+								{Type: "singlestat", Datasource: &grafana.PanelDatasource{Name: "$datasource"}},
+							}},
+							{Panels: []grafana.Panel{
+								{LibraryPanel: &grafana.LibraryPanelRef{UID: "fake-library-panel"}},
+							}},
+						},
+					},
+					Meta: grafana.DashboardMeta{FolderUID: "fake-folder", FolderTitle: "Fake Folder"},
+				},
+				"fake-dashboard-3": {
+					Dashboard: grafana.DashboardModel{
+						UID:           "fake-dashboard-3",
+						Title:         "Fake Dashboard 3",
+						SchemaVersion: 36,
+						Panels: []grafana.Panel{
+							{Type: "text", Datasource: &grafana.PanelDatasource{UID: "fake-deleted-uid"}},
+						},
+					},
+				},
+			}
+
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetDatasourcesStub = func(ctx context.Context, orgID int64) ([]grafana.Datasource, error) {
+				if orgID == 1 {
+					return []grafana.Datasource{
+						{UID: "fake-prometheus-uid", Name: "Fake Prometheus", Type: "prometheus", IsDefault: true},
+						{UID: "fake-loki-uid", Name: "Fake Loki", Type: "loki"},
+					}, nil
+				}
+				return nil, nil
+			}
+			grafanaClient.GetDashboardsStub = func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+				if orgID == 1 {
+					return []grafana.SearchHit{{UID: "fake-dashboard-1"}, {UID: "fake-dashboard-2"}}, nil
+				}
+				return []grafana.SearchHit{{UID: "fake-dashboard-3"}}, nil
+			}
+			grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+				return dashboards[uid], nil
+			}
+			grafanaClient.GetLibraryPanelReturns(grafana.LibraryPanel{
+				UID:   "fake-library-panel",
+				Type:  "stat",
+				Model: grafana.Panel{Datasource: &grafana.PanelDatasource{UID: "fake-loki-uid"}},
+			}, nil)
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			collections.Collect(ctx, panelsCollector, metrics)
+		})
+
+		AfterEach(func() {
+			collections.Drain(metrics)
+		})
+
+		It("returns a grafana_panels metric for the panels referencing a datasource by uid", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "timeseries", "prometheus"),
+			)))
+		})
+
+		It("returns a grafana_panels metric for the panels of collapsed rows referencing a datasource by name", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "graph", "loki"),
+			)))
+		})
+
+		It("returns a grafana_panels metric for the panels of legacy rows using the default datasource", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "graph", "prometheus"),
+			)))
+		})
+
+		It("returns a grafana_panels metric for the panels using a template variable", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "singlestat", "variable"),
+			)))
+		})
+
+		It("returns a grafana_panels metric for the library panels", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 2, "1", "Main Org.", "stat", "loki"),
+			)))
+		})
+
+		It("requests every library panel once", func() {
+			Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastScrapeDurationSecondsMetric.Desc()))))
+			Expect(grafanaClient.GetLibraryPanelCallCount()).To(Equal(1))
+		})
+
+		It("returns a grafana_panels metric for the panels referencing a deleted datasource", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "2", "fake-org", "text", "unknown"),
+			)))
+		})
+
+		It("returns a grafana_dashboard_denied_panels metric for a dashboard using a denied panel type", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(deniedPanelsDesc, prometheus.GaugeValue, 1, "1", "fake-dashboard-1", "Fake Dashboard 1", "", "General", "graph"),
+			)))
+		})
+
+		It("returns a grafana_dashboard_denied_panels metric for another dashboard using a denied panel type", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(deniedPanelsDesc, prometheus.GaugeValue, 1, "1", "fake-dashboard-2", "Fake Dashboard 2", "fake-folder", "Fake Folder", "graph"),
+			)))
+		})
+
+		It("returns a grafana_dashboard_broken_datasource_refs metric for a dashboard with a target referencing a missing datasource", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(dashboardBrokenRefsDesc, prometheus.GaugeValue, 1, "1", "fake-dashboard-1", "General"),
+			)))
+		})
+
+		It("returns a grafana_dashboard_broken_datasource_refs metric for a dashboard with a panel referencing a missing datasource", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(dashboardBrokenRefsDesc, prometheus.GaugeValue, 1, "2", "fake-dashboard-3", "General"),
+			)))
+		})
+
+		It("does not return a grafana_dashboard_broken_datasource_refs metric for the dashboards without broken references", func() {
+			Consistently(metrics).ShouldNot(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(dashboardBrokenRefsDesc, prometheus.GaugeValue, 0, "1", "fake-dashboard-2", "Fake Folder"),
+			)))
+		})
+
+		It("returns a grafana_broken_datasource_refs metric for every org", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(brokenDatasourceRefsDesc, prometheus.GaugeValue, 1, "1", "Main Org."),
+			)))
+		})
+
+		Context("when no dashboard references a missing datasource", func() {
+			BeforeEach(func() {
+				getDashboard := grafanaClient.GetDashboardStub
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					if uid == "fake-dashboard-1" {
+						return grafana.Dashboard{}, nil
+					}
+					return getDashboard(ctx, orgID, uid)
+				}
+			})
+
+			It("returns a zero grafana_broken_datasource_refs metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(brokenDatasourceRefsDesc, prometheus.GaugeValue, 0, "1", "Main Org."),
+				)))
+			})
+		})
+
+		Context("when a dashboard uses server side expressions", func() {
+			BeforeEach(func() {
+				getDashboard := grafanaClient.GetDashboardStub
+				grafanaClient.GetDashboardStub = func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+					if uid == "fake-dashboard-1" {
+						return grafana.Dashboard{
+							Dashboard: grafana.DashboardModel{
+								UID:   "fake-dashboard-1",
+								Title: "Fake Dashboard 1",
+								Panels: []grafana.Panel{
+									{Type: "timeseries", Datasource: &grafana.PanelDatasource{UID: "fake-prometheus-uid", Type: "prometheus"}, Targets: []grafana.PanelTarget{
+										{RefID: "A"},
+										{RefID: "B", Datasource: &grafana.PanelDatasource{UID: "__expr__", Type: "__expr__"}},
+										{RefID: "C", Datasource: &grafana.PanelDatasource{Name: "Expression"}},
+									}},
+								},
+							},
+						}, nil
+					}
+					return getDashboard(ctx, orgID, uid)
+				}
+			})
+
+			It("does not count the expressions as broken references", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(brokenDatasourceRefsDesc, prometheus.GaugeValue, 0, "1", "Main Org."),
+				)))
+			})
+
+			It("does not return a grafana_dashboard_broken_datasource_refs metric for the dashboard", func() {
+				Consistently(metrics).ShouldNot(Receive(Or(
+					PrometheusMetric(prometheus.MustNewConstMetric(dashboardBrokenRefsDesc, prometheus.GaugeValue, 1, "1", "fake-dashboard-1", "General")),
+					PrometheusMetric(prometheus.MustNewConstMetric(dashboardBrokenRefsDesc, prometheus.GaugeValue, 2, "1", "fake-dashboard-1", "General")),
+				)))
+			})
+		})
+
+		It("returns a grafana_panels_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_panels_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_panels_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when there are no deny types", func() {
+			BeforeEach(func() {
+				denyTypes = nil
+			})
+
+			It("does not return a grafana_dashboard_denied_panels metric", func() {
+				Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(deniedPanelsDesc))))
+			})
+		})
+
+		Context("when a library panel is deleted", func() {
+			BeforeEach(func() {
+				grafanaClient.GetLibraryPanelReturns(grafana.LibraryPanel{}, grafana.StatusCodeError{Resource: "library panel", StatusCode: http.StatusNotFound})
+			})
+
+			It("does not count the library panel", func() {
+				Consistently(metrics).ShouldNot(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 2, "1", "Main Org.", "stat", "loki"),
+				)))
+			})
+
+			It("returns a grafana_panels_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetDashboardsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the datasources of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetDatasourcesStub = func(ctx context.Context, orgID int64) ([]grafana.Datasource, error) {
+					if orgID == 2 {
+						return nil, errors.New("error")
+					}
+					return []grafana.Datasource{{UID: "fake-prometheus-uid", Name: "Fake Prometheus", Type: "prometheus", IsDefault: true}}, nil
+				}
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_panels metric for the other orgs", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "timeseries", "prometheus"),
+				)))
+			})
+
+			It("returns a grafana_panels_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_panels_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get a library panel", func() {
+			BeforeEach(func() {
+				grafanaClient.GetLibraryPanelReturns(grafana.LibraryPanel{}, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("does not count the panels of the org", func() {
+				Consistently(metrics).ShouldNot(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(panelsDesc, prometheus.GaugeValue, 1, "1", "Main Org.", "timeseries", "prometheus"),
+				)))
+			})
+
+			It("returns a grafana_panels_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_panels_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_panels_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
@@ -0,0 +1,311 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// folderPermissionNames maps the folder permission levels to their names, for
+// the Grafana versions not returning the permission name.
+var folderPermissionNames = map[int]string{
+	1: "View",
+	2: "Edit",
+	4: "Admin",
+}
+
+type folderPermissionsKey struct {
+	folderUID   string
+	folderTitle string
+	granteeType string
+	permission  string
+}
+
+type TeamsCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	folderPermissions               bool
+	teamsDesc                       *prometheus.Desc
+	teamMembersDesc                 *prometheus.Desc
+	emptyTeamsDesc                  *prometheus.Desc
+	folderPermissionsDesc           *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewTeamsCollector returns a collector of the teams of the orgs matching the
+// org filter. The grants of the folders permissions are only counted if
+// folder permissions is set, as it requests the permissions of every folder.
+func NewTeamsCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, folderPermissions bool) *TeamsCollector {
+	teamsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "", "teams"),
+		"Number of Grafana Teams.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	teamMembersDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "team", "members"),
+		"Number of members of the Grafana Team.",
+		[]string{"org_id", "org_name", "team"},
+		constLabels,
+	)
+
+	emptyTeamsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "teams", "empty"),
+		"Number of Grafana Teams without members.",
+		[]string{"org_id", "org_name"},
+		constLabels,
+	)
+
+	folderPermissionsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "folder", "permissions"),
+		"Number of grants of a permission on the Grafana Folder.",
+		[]string{"org_id", "org_name", "folder_uid", "folder_title", "grantee_type", "permission"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "teams",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Teams scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "teams",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Teams scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "teams",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Teams scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "teams",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Teams resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "teams",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Teams.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "teams",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Teams.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	teamsCollector := &TeamsCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		folderPermissions:               folderPermissions,
+		teamsDesc:                       teamsDesc,
+		teamMembersDesc:                 teamMembersDesc,
+		emptyTeamsDesc:                  emptyTeamsDesc,
+		folderPermissionsDesc:           folderPermissionsDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return teamsCollector
+}
+
+func (c *TeamsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.teamsDesc
+	ch <- c.teamMembersDesc
+	ch <- c.emptyTeamsDesc
+	ch <- c.folderPermissionsDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *TeamsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *TeamsCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportTeamsMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Teams metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Teams metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *TeamsCollector) reportTeamsMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "teams", func(org grafana.Org) error {
+		orgMetrics, err := c.orgTeamsMetrics(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range orgMetrics {
+			ch <- metric
+		}
+
+		return nil
+	})
+}
+
+// orgTeamsMetrics returns the metrics of the teams, and optionally of the
+// folders permissions, of the org.
+func (c *TeamsCollector) orgTeamsMetrics(ctx context.Context, org grafana.Org) ([]prometheus.Metric, error) {
+	var metrics []prometheus.Metric
+
+	teams, err := c.grafanaClient.GetTeams(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID := strconv.FormatInt(org.ID, 10)
+	emptyTeams := 0
+	for _, team := range teams {
+		if team.MemberCount == 0 {
+			emptyTeams++
+		}
+		metrics = append(metrics, prometheus.MustNewConstMetric(c.teamMembersDesc, prometheus.GaugeValue, float64(team.MemberCount), orgID, org.Name, team.Name))
+	}
+	metrics = append(metrics,
+		prometheus.MustNewConstMetric(c.teamsDesc, prometheus.GaugeValue, float64(len(teams)), orgID, org.Name),
+		prometheus.MustNewConstMetric(c.emptyTeamsDesc, prometheus.GaugeValue, float64(emptyTeams), orgID, org.Name),
+	)
+
+	if !c.folderPermissions {
+		return metrics, nil
+	}
+
+	folders, err := c.grafanaClient.GetFolders(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	folderPermissions := map[folderPermissionsKey]int{}
+	for _, folder := range folders {
+		permissions, err := c.grafanaClient.GetFolderPermissions(ctx, org.ID, folder.UID)
+		if err != nil {
+			// The folder was deleted since it was listed.
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, permission := range permissions {
+			folderPermissions[folderPermissionsKey{
+				folderUID:   folder.UID,
+				folderTitle: folder.Title,
+				granteeType: folderPermissionGranteeType(permission),
+				permission:  folderPermissionName(permission),
+			}]++
+		}
+	}
+
+	for key, count := range folderPermissions {
+		metrics = append(metrics, prometheus.MustNewConstMetric(
+			c.folderPermissionsDesc,
+			prometheus.GaugeValue,
+			float64(count),
+			orgID,
+			org.Name,
+			key.folderUID,
+			key.folderTitle,
+			key.granteeType,
+			key.permission,
+		))
+	}
+
+	return metrics, nil
+}
+
+// folderPermissionGranteeType returns whether the permission is granted to a
+// user, a team or an org role.
+func folderPermissionGranteeType(permission grafana.FolderPermission) string {
+	switch {
+	case permission.UserID != 0:
+		return "user"
+	case permission.TeamID != 0:
+		return "team"
+	default:
+		return "role"
+	}
+}
+
+func folderPermissionName(permission grafana.FolderPermission) string {
+	if permission.PermissionName != "" {
+		return permission.PermissionName
+	}
+
+	if name, ok := folderPermissionNames[permission.Permission]; ok {
+		return name
+	}
+
+	return strconv.Itoa(permission.Permission)
+}
@@ -0,0 +1,385 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("TeamsCollector", func() {
+	var (
+		grafanaClient     *grafanafakes.FakeClient
+		constLabels       prometheus.Labels
+		orgFilter         OrgFilter
+		folderPermissions bool
+
+		teamsDesc                       *prometheus.Desc
+		teamMembersDesc                 *prometheus.Desc
+		emptyTeamsDesc                  *prometheus.Desc
+		folderPermissionsDesc           *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		teamsCollector *TeamsCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		folderPermissions = false
+
+		teamsDesc = prometheus.NewDesc(
+			"grafana_teams",
+			"Number of Grafana Teams.",
+			[]string{"org_id", "org_name"},
+			constLabels,
+		)
+
+		teamMembersDesc = prometheus.NewDesc(
+			"grafana_team_members",
+			"Number of members of the Grafana Team.",
+			[]string{"org_id", "org_name", "team"},
+			constLabels,
+		)
+
+		emptyTeamsDesc = prometheus.NewDesc(
+			"grafana_teams_empty",
+			"Number of Grafana Teams without members.",
+			[]string{"org_id", "org_name"},
+			constLabels,
+		)
+
+		folderPermissionsDesc = prometheus.NewDesc(
+			"grafana_folder_permissions",
+			"Number of grants of a permission on the Grafana Folder.",
+			[]string{"org_id", "org_name", "folder_uid", "folder_title", "grantee_type", "permission"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "teams",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Teams scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "teams",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Teams scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "teams",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Teams scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "teams",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Teams resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "teams",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Teams.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "teams",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Teams.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		teamsCollector = NewTeamsCollector(grafanaClient, constLabels, orgFilter, folderPermissions)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go teamsCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_teams metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(teamsDesc)))
+		})
+
+		It("returns a grafana_team_members metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(teamMembersDesc)))
+		})
+
+		It("returns a grafana_teams_empty metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(emptyTeamsDesc)))
+		})
+
+		It("returns a grafana_folder_permissions metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(folderPermissionsDesc)))
+		})
+
+		It("returns a grafana_teams_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_teams_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_teams_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_teams_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_teams_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_teams_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			metricDesc  = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+			constMetric = func(desc *prometheus.Desc, value float64, labelValues ...string) OmegaMatcher {
+				return And(
+					WithTransform(metricDesc, Equal(desc)),
+					PrometheusMetric(prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)),
+				)
+			}
+		)
+
+		BeforeEach(func() {
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetTeamsStub = func(ctx context.Context, orgID int64) ([]grafana.Team, error) {
+				if orgID == 1 {
+					return []grafana.Team{
+						{ID: 1, Name: "fake-team", MemberCount: 3},
+						{ID: 2, Name: "fake-empty-team"},
+						{ID: 3, Name: "fake-other-empty-team"},
+					}, nil
+				}
+				return []grafana.Team{{ID: 4, Name: "fake-team", MemberCount: 1}}, nil
+			}
+			grafanaClient.GetFoldersStub = func(ctx context.Context, orgID int64) ([]grafana.Folder, error) {
+				if orgID == 1 {
+					return []grafana.Folder{
+						{UID: "fake-folder", Title: "Fake Folder"},
+						{UID: "fake-deleted-folder", Title: "Fake Deleted Folder"},
+					}, nil
+				}
+				return nil, nil
+			}
+			grafanaClient.GetFolderPermissionsStub = func(ctx context.Context, orgID int64, uid string) ([]grafana.FolderPermission, error) {
+				if uid == "fake-deleted-folder" {
+					return nil, grafana.StatusCodeError{Resource: "folder permissions", StatusCode: http.StatusNotFound}
+				}
+				return []grafana.FolderPermission{
+					{UserID: 1, Permission: 4, PermissionName: "Admin"},
+					{UserID: 2, Permission: 4},
+					{TeamID: 1, Permission: 2, PermissionName: "Edit"},
+					{Role: "Viewer", Permission: 1, PermissionName: "View"},
+				}, nil
+			}
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go teamsCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_teams metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(teamsDesc, 3, "1", "Main Org.")))
+		})
+
+		It("returns a grafana_teams metric for another org", func() {
+			Eventually(metrics).Should(Receive(constMetric(teamsDesc, 1, "2", "fake-org")))
+		})
+
+		It("returns a grafana_team_members metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(teamMembersDesc, 3, "1", "Main Org.", "fake-team")))
+		})
+
+		It("returns a grafana_team_members metric for a team without members", func() {
+			Eventually(metrics).Should(Receive(constMetric(teamMembersDesc, 0, "1", "Main Org.", "fake-empty-team")))
+		})
+
+		It("returns a grafana_teams_empty metric", func() {
+			Eventually(metrics).Should(Receive(constMetric(emptyTeamsDesc, 2, "1", "Main Org.")))
+		})
+
+		It("returns a grafana_teams_empty metric for an org without empty teams", func() {
+			Eventually(metrics).Should(Receive(constMetric(emptyTeamsDesc, 0, "2", "fake-org")))
+		})
+
+		It("does not return a grafana_folder_permissions metric", func() {
+			Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(folderPermissionsDesc))))
+			Expect(grafanaClient.GetFoldersCallCount()).To(Equal(0))
+		})
+
+		It("returns a grafana_teams_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_teams_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_teams_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the folder permissions are enabled", func() {
+			BeforeEach(func() {
+				folderPermissions = true
+			})
+
+			It("returns a grafana_folder_permissions metric for the user grants", func() {
+				Eventually(metrics).Should(Receive(constMetric(folderPermissionsDesc, 2, "1", "Main Org.", "fake-folder", "Fake Folder", "user", "Admin")))
+			})
+
+			It("returns a grafana_folder_permissions metric for the team grants", func() {
+				Eventually(metrics).Should(Receive(constMetric(folderPermissionsDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "team", "Edit")))
+			})
+
+			It("returns a grafana_folder_permissions metric for the role grants", func() {
+				Eventually(metrics).Should(Receive(constMetric(folderPermissionsDesc, 1, "1", "Main Org.", "fake-folder", "Fake Folder", "role", "View")))
+			})
+
+			It("returns a grafana_teams_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+
+			Context("when it fails to get the permissions of a folder", func() {
+				BeforeEach(func() {
+					grafanaClient.GetFolderPermissionsReturns(nil, errors.New("error"))
+					grafanaClient.GetFolderPermissionsStub = nil
+
+					scrapeErrorsTotalMetric.Inc()
+					lastScrapeErrorMetric.Set(1)
+				})
+
+				It("does not return the metrics of the org", func() {
+					Consistently(metrics).ShouldNot(Receive(constMetric(teamsDesc, 3, "1", "Main Org.")))
+				})
+
+				It("returns a grafana_teams_last_scrape_error metric", func() {
+					Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+				})
+			})
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetTeamsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the teams of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetTeamsStub = func(ctx context.Context, orgID int64) ([]grafana.Team, error) {
+					if orgID == 2 {
+						return nil, errors.New("error")
+					}
+					return []grafana.Team{{ID: 1, Name: "fake-team", MemberCount: 3}}, nil
+				}
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_teams metric for the other orgs", func() {
+				Eventually(metrics).Should(Receive(constMetric(teamsDesc, 1, "1", "Main Org.")))
+			})
+
+			It("returns a grafana_teams_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_teams_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgsReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_teams_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_teams_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package collectors
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// Grafana reports the timer values in milliseconds.
+const millisecondsPerSecond = 1000
+
+// timerMetric exports a Grafana timer as the quantiles and the count of a
+// Prometheus summary, plus the min, max, mean and standard deviation gauges,
+// all in seconds. The quantiles are exported as gauges with a `quantile` label
+// and the count as a `_count` counter, but there is no `_sum`: Grafana computes
+// the timer mean from a sample reservoir, so a sum derived from it could go
+// down between scrapes and be seen as a counter reset by `rate()`. Use the
+// mean gauge instead. When legacy metric names are enabled, the timer is also
+// exported as a gauge with a `metric` label named without the `_seconds`
+// suffix, as it was exported before being converted to seconds.
+type timerMetric struct {
+	quantileDesc *prometheus.Desc
+	countDesc    *prometheus.Desc
+	minDesc      *prometheus.Desc
+	maxDesc      *prometheus.Desc
+	meanDesc     *prometheus.Desc
+	stddevDesc   *prometheus.Desc
+	legacyDesc   *prometheus.Desc
+}
+
+func newTimerMetric(opts prometheus.Opts, variableLabels []string, legacyMetricNames bool) *timerMetric {
+	name := strings.TrimSuffix(opts.Name, "_seconds")
+	help := strings.TrimSuffix(opts.Help, ".")
+
+	newDesc := func(name string, help string, variableLabels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, opts.Subsystem, name),
+			help,
+			variableLabels,
+			opts.ConstLabels,
+		)
+	}
+
+	// Copy the variable labels before adding a label, so the descriptions
+	// do not share the same backing array.
+	withLabel := func(label string) []string {
+		return append(append([]string{}, variableLabels...), label)
+	}
+
+	timerMetric := &timerMetric{
+		quantileDesc: newDesc(opts.Name, opts.Help, withLabel("quantile")...),
+		countDesc:    newDesc(opts.Name+"_count", help+" (number of observations).", variableLabels...),
+		minDesc:      newDesc(name+"_min_seconds", help+" (minimum).", variableLabels...),
+		maxDesc:      newDesc(name+"_max_seconds", help+" (maximum).", variableLabels...),
+		meanDesc:     newDesc(name+"_mean_seconds", help+" (mean).", variableLabels...),
+		stddevDesc:   newDesc(name+"_stddev_seconds", help+" (standard deviation).", variableLabels...),
+	}
+
+	if legacyMetricNames {
+		timerMetric.legacyDesc = newDesc(name, opts.Help, withLabel("metric")...)
+	}
+
+	return timerMetric
+}
+
+func (m *timerMetric) collect(ch chan<- prometheus.Metric, timer grafana.Timer, labelValues ...string) {
+	quantiles := []struct {
+		quantile string
+		value    float64
+	}{
+		{"0.25", timer.P25},
+		{"0.75", timer.P75},
+		{"0.9", timer.P90},
+		{"0.99", timer.P99},
+	}
+	for _, quantile := range quantiles {
+		ch <- prometheus.MustNewConstMetric(m.quantileDesc, prometheus.GaugeValue, quantile.value/millisecondsPerSecond, append(labelValues, quantile.quantile)...)
+	}
+	ch <- prometheus.MustNewConstMetric(m.countDesc, prometheus.CounterValue, float64(timer.Count), labelValues...)
+	ch <- prometheus.MustNewConstMetric(m.minDesc, prometheus.GaugeValue, float64(timer.Min)/millisecondsPerSecond, labelValues...)
+	ch <- prometheus.MustNewConstMetric(m.maxDesc, prometheus.GaugeValue, float64(timer.Max)/millisecondsPerSecond, labelValues...)
+	ch <- prometheus.MustNewConstMetric(m.meanDesc, prometheus.GaugeValue, timer.Mean/millisecondsPerSecond, labelValues...)
+	ch <- prometheus.MustNewConstMetric(m.stddevDesc, prometheus.GaugeValue, timer.Std/millisecondsPerSecond, labelValues...)
+
+	if m.legacyDesc != nil {
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, float64(timer.Count), append(labelValues, "count")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, float64(timer.Max), append(labelValues, "max")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, timer.Mean, append(labelValues, "mean")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, float64(timer.Min), append(labelValues, "min")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, timer.P25, append(labelValues, "p25")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, timer.P75, append(labelValues, "p75")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, timer.P90, append(labelValues, "p90")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, timer.P99, append(labelValues, "p99")...)
+		ch <- prometheus.MustNewConstMetric(m.legacyDesc, prometheus.GaugeValue, timer.Std, append(labelValues, "std")...)
+	}
+}
@@ -0,0 +1,305 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// basicAuthModule is the auth module of the users without auth labels, which
+// log in with a Grafana password.
+const basicAuthModule = "basic"
+
+// activeUsersPeriods are the periods the active users are counted over.
+var activeUsersPeriods = []struct {
+	label    string
+	duration time.Duration
+}{
+	{label: "1d", duration: 24 * time.Hour},
+	{label: "7d", duration: 7 * 24 * time.Hour},
+	{label: "30d", duration: 30 * 24 * time.Hour},
+}
+
+// orgRoles are the org roles always reported, even when no user has them.
+var orgRoles = []string{"Viewer", "Editor", "Admin"}
+
+type UsersCollector struct {
+	grafanaClient                   grafana.Client
+	orgFilter                       OrgFilter
+	lastSeenAge                     bool
+	activeUsersDesc                 *prometheus.Desc
+	disabledUsersDesc               *prometheus.Desc
+	serverAdminsDesc                *prometheus.Desc
+	usersByAuthModuleDesc           *prometheus.Desc
+	orgUsersByRoleDesc              *prometheus.Desc
+	lastSeenAgeDesc                 *prometheus.Desc
+	scrapesTotalMetric              prometheus.Counter
+	scrapeErrorsTotalMetric         prometheus.Counter
+	scrapeTimeoutsTotalMetric       prometheus.Counter
+	lastScrapeErrorMetric           prometheus.Gauge
+	lastScrapeTimestampMetric       prometheus.Gauge
+	lastScrapeDurationSecondsMetric prometheus.Gauge
+}
+
+// NewUsersCollector returns a collector of aggregates of the Grafana users,
+// and of their roles in the orgs matching the org filter. The users logins
+// are only exported, as `grafana_user_last_seen_age_seconds` series, if last
+// seen age is set.
+func NewUsersCollector(grafanaClient grafana.Client, constLabels prometheus.Labels, orgFilter OrgFilter, lastSeenAge bool) *UsersCollector {
+	activeUsersDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "users", "active"),
+		"Number of Grafana Users seen during the period.",
+		[]string{"period"},
+		constLabels,
+	)
+
+	disabledUsersDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "users", "disabled"),
+		"Number of disabled Grafana Users.",
+		nil,
+		constLabels,
+	)
+
+	serverAdminsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "users", "server_admins"),
+		"Number of Grafana Server Admins.",
+		nil,
+		constLabels,
+	)
+
+	usersByAuthModuleDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "users", "by_auth_module"),
+		"Number of Grafana Users per auth module.",
+		[]string{"auth_module"},
+		constLabels,
+	)
+
+	orgUsersByRoleDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "org_users", "by_role"),
+		"Number of Grafana Users per Org role.",
+		[]string{"org_id", "org_name", "role"},
+		constLabels,
+	)
+
+	lastSeenAgeDesc := prometheus.NewDesc(
+		prometheus.BuildFQName("grafana", "user", "last_seen_age_seconds"),
+		"Number of seconds since the Grafana User was last seen.",
+		[]string{"user_id", "login"},
+		constLabels,
+	)
+
+	scrapesTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "users",
+			Name:        "scrapes_total",
+			Help:        "Total number of Grafana Users scrapes.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeErrorsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "users",
+			Name:        "scrape_errors_total",
+			Help:        "Total number of Grafana Users scrape errors.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	scrapeTimeoutsTotalMetric := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace:   "grafana",
+			Subsystem:   "users",
+			Name:        "scrape_timeouts_total",
+			Help:        "Total number of Grafana Users scrape timeouts.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeErrorMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "users",
+			Name:        "last_scrape_error",
+			Help:        "Whether the last metrics scrape from Grafana Users resulted in an error (1 for error, 0 for success).",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeTimestampMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "users",
+			Name:        "last_scrape_timestamp",
+			Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Users.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	lastScrapeDurationSecondsMetric := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace:   "grafana",
+			Subsystem:   "users",
+			Name:        "last_scrape_duration_seconds",
+			Help:        "Duration of the last metrics scrape from Grafana Users.",
+			ConstLabels: constLabels,
+		},
+	)
+
+	usersCollector := &UsersCollector{
+		grafanaClient:                   grafanaClient,
+		orgFilter:                       orgFilter,
+		lastSeenAge:                     lastSeenAge,
+		activeUsersDesc:                 activeUsersDesc,
+		disabledUsersDesc:               disabledUsersDesc,
+		serverAdminsDesc:                serverAdminsDesc,
+		usersByAuthModuleDesc:           usersByAuthModuleDesc,
+		orgUsersByRoleDesc:              orgUsersByRoleDesc,
+		lastSeenAgeDesc:                 lastSeenAgeDesc,
+		scrapesTotalMetric:              scrapesTotalMetric,
+		scrapeErrorsTotalMetric:         scrapeErrorsTotalMetric,
+		scrapeTimeoutsTotalMetric:       scrapeTimeoutsTotalMetric,
+		lastScrapeErrorMetric:           lastScrapeErrorMetric,
+		lastScrapeTimestampMetric:       lastScrapeTimestampMetric,
+		lastScrapeDurationSecondsMetric: lastScrapeDurationSecondsMetric,
+	}
+
+	return usersCollector
+}
+
+func (c *UsersCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activeUsersDesc
+	ch <- c.disabledUsersDesc
+	ch <- c.serverAdminsDesc
+	ch <- c.usersByAuthModuleDesc
+	ch <- c.orgUsersByRoleDesc
+	ch <- c.lastSeenAgeDesc
+	c.scrapesTotalMetric.Describe(ch)
+	c.scrapeErrorsTotalMetric.Describe(ch)
+	c.scrapeTimeoutsTotalMetric.Describe(ch)
+	c.lastScrapeErrorMetric.Describe(ch)
+	c.lastScrapeTimestampMetric.Describe(ch)
+	c.lastScrapeDurationSecondsMetric.Describe(ch)
+}
+
+func (c *UsersCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *UsersCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	var begun = time.Now()
+
+	errorMetric := float64(0)
+	if err := c.reportUsersMetrics(ctx, ch); err != nil {
+		errorMetric = float64(1)
+		if ctx.Err() == context.DeadlineExceeded {
+			c.scrapeTimeoutsTotalMetric.Inc()
+			log.Errorf("Timeout while getting Grafana Users metrics: %s", err)
+		} else {
+			c.scrapeErrorsTotalMetric.Inc()
+			log.Errorf("Error while getting Grafana Users metrics: %s", err)
+		}
+	}
+	c.scrapeErrorsTotalMetric.Collect(ch)
+	c.scrapeTimeoutsTotalMetric.Collect(ch)
+
+	c.scrapesTotalMetric.Inc()
+	c.scrapesTotalMetric.Collect(ch)
+
+	c.lastScrapeErrorMetric.Set(errorMetric)
+	c.lastScrapeErrorMetric.Collect(ch)
+
+	c.lastScrapeTimestampMetric.Set(float64(time.Now().Unix()))
+	c.lastScrapeTimestampMetric.Collect(ch)
+
+	c.lastScrapeDurationSecondsMetric.Set(time.Since(begun).Seconds())
+	c.lastScrapeDurationSecondsMetric.Collect(ch)
+}
+
+func (c *UsersCollector) reportUsersMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	users, err := c.grafanaClient.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	activeUsers := make([]int, len(activeUsersPeriods))
+	disabledUsers := 0
+	serverAdmins := 0
+	usersByAuthModule := map[string]int{basicAuthModule: 0}
+	for _, user := range users {
+		if user.IsDisabled {
+			disabledUsers++
+		} else {
+			for i, period := range activeUsersPeriods {
+				if now.Sub(user.LastSeenAt) <= period.duration {
+					activeUsers[i]++
+				}
+			}
+		}
+
+		if user.IsAdmin {
+			serverAdmins++
+		}
+
+		if len(user.AuthLabels) == 0 {
+			usersByAuthModule[basicAuthModule]++
+		}
+		for _, authLabel := range user.AuthLabels {
+			usersByAuthModule[strings.ToLower(authLabel)]++
+		}
+
+		if c.lastSeenAge && !user.LastSeenAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				c.lastSeenAgeDesc,
+				prometheus.GaugeValue,
+				now.Sub(user.LastSeenAt).Seconds(),
+				strconv.FormatInt(user.ID, 10),
+				user.Login,
+			)
+		}
+	}
+
+	for i, period := range activeUsersPeriods {
+		ch <- prometheus.MustNewConstMetric(c.activeUsersDesc, prometheus.GaugeValue, float64(activeUsers[i]), period.label)
+	}
+	ch <- prometheus.MustNewConstMetric(c.disabledUsersDesc, prometheus.GaugeValue, float64(disabledUsers))
+	ch <- prometheus.MustNewConstMetric(c.serverAdminsDesc, prometheus.GaugeValue, float64(serverAdmins))
+	for authModule, count := range usersByAuthModule {
+		ch <- prometheus.MustNewConstMetric(c.usersByAuthModuleDesc, prometheus.GaugeValue, float64(count), authModule)
+	}
+
+	return c.reportOrgUsersMetrics(ctx, ch)
+}
+
+func (c *UsersCollector) reportOrgUsersMetrics(ctx context.Context, ch chan<- prometheus.Metric) error {
+	return forEachOrg(ctx, c.grafanaClient, c.orgFilter, "users", func(org grafana.Org) error {
+		orgUsers, err := c.grafanaClient.GetOrgUsers(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+
+		usersByRole := map[string]int{}
+		for _, role := range orgRoles {
+			usersByRole[role] = 0
+		}
+		for _, orgUser := range orgUsers {
+			usersByRole[orgUser.Role]++
+		}
+
+		orgID := strconv.FormatInt(org.ID, 10)
+		for role, count := range usersByRole {
+			ch <- prometheus.MustNewConstMetric(c.orgUsersByRoleDesc, prometheus.GaugeValue, float64(count), orgID, org.Name, role)
+		}
+
+		return nil
+	})
+}
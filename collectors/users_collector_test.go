@@ -0,0 +1,400 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+	. "github.com/frodenas/grafana_exporter/utils/test_matchers"
+)
+
+var _ = Describe("UsersCollector", func() {
+	var (
+		grafanaClient *grafanafakes.FakeClient
+		constLabels   prometheus.Labels
+		orgFilter     OrgFilter
+		lastSeenAge   bool
+
+		activeUsersDesc                 *prometheus.Desc
+		disabledUsersDesc               *prometheus.Desc
+		serverAdminsDesc                *prometheus.Desc
+		usersByAuthModuleDesc           *prometheus.Desc
+		orgUsersByRoleDesc              *prometheus.Desc
+		lastSeenAgeDesc                 *prometheus.Desc
+		scrapesTotalMetric              prometheus.Counter
+		scrapeErrorsTotalMetric         prometheus.Counter
+		scrapeTimeoutsTotalMetric       prometheus.Counter
+		lastScrapeErrorMetric           prometheus.Gauge
+		lastScrapeTimestampMetric       prometheus.Gauge
+		lastScrapeDurationSecondsMetric prometheus.Gauge
+
+		usersCollector *UsersCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		constLabels = prometheus.Labels{"grafana": "fake-grafana"}
+		orgFilter = OrgFilter{}
+		lastSeenAge = false
+
+		activeUsersDesc = prometheus.NewDesc(
+			"grafana_users_active",
+			"Number of Grafana Users seen during the period.",
+			[]string{"period"},
+			constLabels,
+		)
+
+		disabledUsersDesc = prometheus.NewDesc(
+			"grafana_users_disabled",
+			"Number of disabled Grafana Users.",
+			nil,
+			constLabels,
+		)
+
+		serverAdminsDesc = prometheus.NewDesc(
+			"grafana_users_server_admins",
+			"Number of Grafana Server Admins.",
+			nil,
+			constLabels,
+		)
+
+		usersByAuthModuleDesc = prometheus.NewDesc(
+			"grafana_users_by_auth_module",
+			"Number of Grafana Users per auth module.",
+			[]string{"auth_module"},
+			constLabels,
+		)
+
+		orgUsersByRoleDesc = prometheus.NewDesc(
+			"grafana_org_users_by_role",
+			"Number of Grafana Users per Org role.",
+			[]string{"org_id", "org_name", "role"},
+			constLabels,
+		)
+
+		lastSeenAgeDesc = prometheus.NewDesc(
+			"grafana_user_last_seen_age_seconds",
+			"Number of seconds since the Grafana User was last seen.",
+			[]string{"user_id", "login"},
+			constLabels,
+		)
+
+		scrapesTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "users",
+				Name:        "scrapes_total",
+				Help:        "Total number of Grafana Users scrapes.",
+				ConstLabels: constLabels,
+			},
+		)
+		scrapesTotalMetric.Inc()
+
+		scrapeErrorsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "users",
+				Name:        "scrape_errors_total",
+				Help:        "Total number of Grafana Users scrape errors.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		scrapeTimeoutsTotalMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace:   "grafana",
+				Subsystem:   "users",
+				Name:        "scrape_timeouts_total",
+				Help:        "Total number of Grafana Users scrape timeouts.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeErrorMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "users",
+				Name:        "last_scrape_error",
+				Help:        "Whether the last metrics scrape from Grafana Users resulted in an error (1 for error, 0 for success).",
+				ConstLabels: constLabels,
+			},
+		)
+		lastScrapeErrorMetric.Set(0)
+
+		lastScrapeTimestampMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "users",
+				Name:        "last_scrape_timestamp",
+				Help:        "Number of seconds since 1970 since last metrics scrape from Grafana Users.",
+				ConstLabels: constLabels,
+			},
+		)
+
+		lastScrapeDurationSecondsMetric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   "grafana",
+				Subsystem:   "users",
+				Name:        "last_scrape_duration_seconds",
+				Help:        "Duration of the last metrics scrape from Grafana Users.",
+				ConstLabels: constLabels,
+			},
+		)
+	})
+
+	JustBeforeEach(func() {
+		usersCollector = NewUsersCollector(grafanaClient, constLabels, orgFilter, lastSeenAge)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go usersCollector.Describe(descriptions)
+		})
+
+		It("returns a grafana_users_active metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(activeUsersDesc)))
+		})
+
+		It("returns a grafana_users_disabled metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(disabledUsersDesc)))
+		})
+
+		It("returns a grafana_users_server_admins metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(serverAdminsDesc)))
+		})
+
+		It("returns a grafana_users_by_auth_module metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(usersByAuthModuleDesc)))
+		})
+
+		It("returns a grafana_org_users_by_role metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(orgUsersByRoleDesc)))
+		})
+
+		It("returns a grafana_user_last_seen_age_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastSeenAgeDesc)))
+		})
+
+		It("returns a grafana_users_scrapes_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapesTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_users_scrape_errors_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeErrorsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_users_scrape_timeouts_total metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(scrapeTimeoutsTotalMetric.Desc())))
+		})
+
+		It("returns a grafana_users_last_scrape_error metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeErrorMetric.Desc())))
+		})
+
+		It("returns a grafana_users_last_scrape_timestamp metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeTimestampMetric.Desc())))
+		})
+
+		It("returns a grafana_users_last_scrape_duration_seconds metric description", func() {
+			Eventually(descriptions).Should(Receive(Equal(lastScrapeDurationSecondsMetric.Desc())))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			ctx     context.Context
+			metrics chan prometheus.Metric
+
+			metricDesc = func(metric prometheus.Metric) *prometheus.Desc { return metric.Desc() }
+		)
+
+		BeforeEach(func() {
+			now := time.Now()
+			grafanaClient.GetUsersReturns([]grafana.User{
+				{ID: 1, Login: "admin", IsAdmin: true, LastSeenAt: now.Add(-time.Hour)},
+				{ID: 2, Login: "fake-ldap-user", IsAdmin: true, LastSeenAt: now.Add(-3 * 24 * time.Hour), AuthLabels: []string{"LDAP"}},
+				{ID: 3, Login: "fake-oauth-user", IsAdmin: true, LastSeenAt: now.Add(-20 * 24 * time.Hour), AuthLabels: []string{"OAuth"}},
+				{ID: 4, Login: "fake-disabled-user", IsDisabled: true, LastSeenAt: now.Add(-time.Hour), AuthLabels: []string{"LDAP"}},
+				{ID: 5, Login: "fake-unseen-user", IsDisabled: true},
+			}, nil)
+
+			grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}, {ID: 2, Name: "fake-org"}}, nil)
+			grafanaClient.GetOrgUsersStub = func(ctx context.Context, orgID int64) ([]grafana.OrgUser, error) {
+				if orgID == 1 {
+					return []grafana.OrgUser{
+						{UserID: 1, Role: "Admin"},
+						{UserID: 2, Role: "Editor"},
+						{UserID: 3, Role: "Viewer"},
+						{UserID: 4, Role: "Viewer"},
+					}, nil
+				}
+				return []grafana.OrgUser{{UserID: 1, Role: "Admin"}}, nil
+			}
+
+			ctx = context.Background()
+			metrics = make(chan prometheus.Metric)
+		})
+
+		JustBeforeEach(func() {
+			go usersCollector.CollectContext(ctx, metrics)
+		})
+
+		It("returns a grafana_users_active metric for the last day", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(activeUsersDesc, prometheus.GaugeValue, 1, "1d"),
+			)))
+		})
+
+		It("returns a grafana_users_active metric for the last week", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(activeUsersDesc, prometheus.GaugeValue, 2, "7d"),
+			)))
+		})
+
+		It("returns a grafana_users_active metric for the last month", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(activeUsersDesc, prometheus.GaugeValue, 3, "30d"),
+			)))
+		})
+
+		It("returns a grafana_users_disabled metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(disabledUsersDesc, prometheus.GaugeValue, 2),
+			)))
+		})
+
+		It("returns a grafana_users_server_admins metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(serverAdminsDesc, prometheus.GaugeValue, 3),
+			)))
+		})
+
+		It("returns a grafana_users_by_auth_module metric for the basic auth users", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(usersByAuthModuleDesc, prometheus.GaugeValue, 2, "basic"),
+			)))
+		})
+
+		It("returns a grafana_users_by_auth_module metric for the LDAP users", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(usersByAuthModuleDesc, prometheus.GaugeValue, 2, "ldap"),
+			)))
+		})
+
+		It("returns a grafana_users_by_auth_module metric for the OAuth users", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(usersByAuthModuleDesc, prometheus.GaugeValue, 1, "oauth"),
+			)))
+		})
+
+		It("returns a grafana_org_users_by_role metric for a role", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(orgUsersByRoleDesc, prometheus.GaugeValue, 2, "1", "Main Org.", "Viewer"),
+			)))
+		})
+
+		It("returns a grafana_org_users_by_role metric for a role without users", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(
+				prometheus.MustNewConstMetric(orgUsersByRoleDesc, prometheus.GaugeValue, 0, "2", "fake-org", "Editor"),
+			)))
+		})
+
+		It("does not return a grafana_user_last_seen_age_seconds metric", func() {
+			Consistently(metrics).ShouldNot(Receive(WithTransform(metricDesc, Equal(lastSeenAgeDesc))))
+		})
+
+		It("returns a grafana_users_scrapes_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+		})
+
+		It("returns a grafana_users_scrape_errors_total metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+		})
+
+		It("returns a grafana_users_last_scrape_error metric", func() {
+			Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+		})
+
+		Context("when the users last seen age is enabled", func() {
+			BeforeEach(func() {
+				lastSeenAge = true
+			})
+
+			It("returns a grafana_user_last_seen_age_seconds metric", func() {
+				Eventually(metrics).Should(Receive(WithTransform(metricDesc, Equal(lastSeenAgeDesc))))
+			})
+		})
+
+		Context("when an org is excluded", func() {
+			BeforeEach(func() {
+				orgFilter.Exclude = []string{"2"}
+			})
+
+			It("does not scrape the org", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapesTotalMetric)))
+				Expect(grafanaClient.GetOrgUsersCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when it fails to get the users of an org", func() {
+			BeforeEach(func() {
+				grafanaClient.GetOrgUsersStub = func(ctx context.Context, orgID int64) ([]grafana.OrgUser, error) {
+					if orgID == 2 {
+						return nil, errors.New("error")
+					}
+					return []grafana.OrgUser{{UserID: 3, Role: "Viewer"}, {UserID: 4, Role: "Viewer"}}, nil
+				}
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_org_users_by_role metric for the other orgs", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(
+					prometheus.MustNewConstMetric(orgUsersByRoleDesc, prometheus.GaugeValue, 2, "1", "Main Org.", "Viewer"),
+				)))
+			})
+
+			It("returns a grafana_users_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_users_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+
+		Context("when it fails to get the users", func() {
+			BeforeEach(func() {
+				grafanaClient.GetUsersReturns(nil, errors.New("error"))
+
+				scrapeErrorsTotalMetric.Inc()
+				lastScrapeErrorMetric.Set(1)
+			})
+
+			It("returns a grafana_users_scrape_errors_total metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(scrapeErrorsTotalMetric)))
+			})
+
+			It("returns a grafana_users_last_scrape_error metric", func() {
+				Eventually(metrics).Should(Receive(PrometheusMetric(lastScrapeErrorMetric)))
+			})
+		})
+	})
+})
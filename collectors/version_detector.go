@@ -0,0 +1,179 @@
+package collectors
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+// VersionRange is a range of Grafana versions. The min version is included
+// and the max version excluded; an empty version leaves the range unbounded.
+type VersionRange struct {
+	Min string
+	Max string
+}
+
+// Contains returns whether the version is in the range. An unknown version
+// is in every range, so the collectors keep on being scraped when the
+// version cannot be detected.
+func (r VersionRange) Contains(version string) bool {
+	if r.Min == "" && r.Max == "" {
+		return true
+	}
+
+	v, ok := parseVersion(version)
+	if !ok {
+		return true
+	}
+
+	if min, ok := parseVersion(r.Min); ok && compareVersions(v, min) < 0 {
+		return false
+	}
+
+	if max, ok := parseVersion(r.Max); ok && compareVersions(v, max) >= 0 {
+		return false
+	}
+
+	return true
+}
+
+// parseVersion parses the major, minor and patch numbers of a Grafana
+// version, ignoring any pre-release or build suffix (i.e. `11.0.0-preview`).
+func parseVersion(version string) ([3]int, bool) {
+	var numbers [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexFunc(version, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return numbers, false
+	}
+
+	for i, part := range strings.Split(version, ".") {
+		if i == len(numbers) {
+			break
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return numbers, false
+		}
+		numbers[i] = number
+	}
+
+	return numbers, true
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+
+	return 0
+}
+
+// VersionDetector detects the version of a Grafana from its health, falling
+// back to its frontend settings when the health hides it. The version is
+// detected once started and then at most once per interval, whatever the
+// scrape interval, and shared by the collectors of the Grafana. A failed
+// detection is retried after the retry interval.
+type VersionDetector struct {
+	grafanaClient grafana.Client
+	interval      time.Duration
+	retryInterval time.Duration
+
+	mtx       sync.Mutex
+	version   string
+	nextCheck time.Time
+	detecting chan struct{}
+}
+
+func NewVersionDetector(grafanaClient grafana.Client, interval time.Duration, retryInterval time.Duration) *VersionDetector {
+	return &VersionDetector{
+		grafanaClient: grafanaClient,
+		interval:      interval,
+		retryInterval: retryInterval,
+	}
+}
+
+// Start detects the version in the background, so it is known by the first
+// scrape.
+func (d *VersionDetector) Start() {
+	go d.Version(context.Background())
+}
+
+// Version returns the detected Grafana version, or an empty version if it
+// was never detected. A failed detection keeps the previous version, so a
+// Grafana down does not disable its collectors. While the version is being
+// detected, the previous version is returned, or the detection is waited for
+// if the version was never detected.
+func (d *VersionDetector) Version(ctx context.Context) string {
+	d.mtx.Lock()
+	if detecting := d.detecting; detecting != nil {
+		if d.version == "" {
+			d.mtx.Unlock()
+			select {
+			case <-detecting:
+			case <-ctx.Done():
+			}
+			d.mtx.Lock()
+		}
+		defer d.mtx.Unlock()
+		return d.version
+	}
+	if time.Now().Before(d.nextCheck) {
+		defer d.mtx.Unlock()
+		return d.version
+	}
+	detecting := make(chan struct{})
+	d.detecting = detecting
+	d.mtx.Unlock()
+
+	// The lock is not held while requesting Grafana, so the other collectors
+	// are not blocked by a slow Grafana.
+	version, err := d.detectVersion(ctx)
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.detecting = nil
+	close(detecting)
+
+	if err != nil {
+		log.Errorf("Error while detecting Grafana version: %s", err)
+		d.nextCheck = time.Now().Add(d.retryInterval)
+		return d.version
+	}
+	if version != "" && version != d.version {
+		log.Infof("Detected Grafana version `%s`", version)
+	}
+	d.version = version
+	d.nextCheck = time.Now().Add(d.interval)
+
+	return d.version
+}
+
+func (d *VersionDetector) detectVersion(ctx context.Context) (string, error) {
+	health, err := d.grafanaClient.GetHealth(ctx)
+	if err != nil {
+		return "", err
+	}
+	if health.Version != "" {
+		return health.Version, nil
+	}
+
+	frontendSettings, err := d.grafanaClient.GetFrontendSettings(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return frontendSettings.BuildInfo.Version, nil
+}
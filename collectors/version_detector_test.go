@@ -0,0 +1,216 @@
+package collectors_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+)
+
+var _ = Describe("VersionRange", func() {
+	Describe("Contains", func() {
+		It("contains every version when unbounded", func() {
+			Expect(VersionRange{}.Contains("4.6.3")).To(BeTrue())
+		})
+
+		It("contains the versions from the min version", func() {
+			Expect(VersionRange{Min: "8.0.0"}.Contains("8.0.0")).To(BeTrue())
+			Expect(VersionRange{Min: "8.0.0"}.Contains("10.4.1")).To(BeTrue())
+			Expect(VersionRange{Min: "8.0.0"}.Contains("7.5.17")).To(BeFalse())
+		})
+
+		It("contains the versions until the max version", func() {
+			Expect(VersionRange{Max: "11.0.0"}.Contains("10.4.1")).To(BeTrue())
+			Expect(VersionRange{Max: "11.0.0"}.Contains("11.0.0")).To(BeFalse())
+		})
+
+		It("ignores the pre-release suffixes", func() {
+			Expect(VersionRange{Max: "11.0.0"}.Contains("11.0.0-preview")).To(BeFalse())
+		})
+
+		It("parses the versions with a v prefix or without patch number", func() {
+			Expect(VersionRange{Min: "8.0.0"}.Contains("v9.3.2")).To(BeTrue())
+			Expect(VersionRange{Min: "8.0.0"}.Contains("7.5")).To(BeFalse())
+		})
+
+		It("contains the unknown versions", func() {
+			Expect(VersionRange{Min: "8.0.0"}.Contains("")).To(BeTrue())
+			Expect(VersionRange{Min: "8.0.0"}.Contains("unknown")).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("VersionDetector", func() {
+	var (
+		grafanaClient   *grafanafakes.FakeClient
+		interval        time.Duration
+		retryInterval   time.Duration
+		versionDetector *VersionDetector
+		version         string
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "9.3.2"}, nil)
+		interval = time.Hour
+		retryInterval = time.Hour
+	})
+
+	Describe("Version", func() {
+		JustBeforeEach(func() {
+			versionDetector = NewVersionDetector(grafanaClient, interval, retryInterval)
+			version = versionDetector.Version(context.Background())
+		})
+
+		It("returns the version of the health", func() {
+			Expect(version).To(Equal("9.3.2"))
+			Expect(grafanaClient.GetFrontendSettingsCallCount()).To(Equal(0))
+		})
+
+		It("detects the version at most once per interval", func() {
+			grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "10.4.1"}, nil)
+
+			Expect(versionDetector.Version(context.Background())).To(Equal("9.3.2"))
+			Expect(grafanaClient.GetHealthCallCount()).To(Equal(1))
+		})
+
+		Context("when the interval has elapsed", func() {
+			BeforeEach(func() {
+				interval = 0
+			})
+
+			It("detects the version again", func() {
+				grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "10.4.1"}, nil)
+
+				Expect(versionDetector.Version(context.Background())).To(Equal("10.4.1"))
+				Expect(grafanaClient.GetHealthCallCount()).To(Equal(2))
+			})
+
+			It("keeps the previous version when the detection fails", func() {
+				grafanaClient.GetHealthReturns(grafana.Health{}, errors.New("error"))
+
+				Expect(versionDetector.Version(context.Background())).To(Equal("9.3.2"))
+			})
+
+			Context("when the version is being detected", func() {
+				var (
+					detecting chan struct{}
+					release   chan struct{}
+				)
+
+				BeforeEach(func() {
+					detecting = make(chan struct{}, 1)
+					release = make(chan struct{})
+				})
+
+				JustBeforeEach(func() {
+					grafanaClient.GetHealthStub = func(ctx context.Context) (grafana.Health, error) {
+						detecting <- struct{}{}
+						<-release
+						return grafana.Health{Database: "ok", Version: "10.4.1"}, nil
+					}
+					go versionDetector.Version(context.Background())
+					Eventually(detecting).Should(Receive())
+				})
+
+				AfterEach(func() {
+					close(release)
+				})
+
+				It("returns the previous version without waiting for the detection", func() {
+					Expect(versionDetector.Version(context.Background())).To(Equal("9.3.2"))
+					Expect(grafanaClient.GetHealthCallCount()).To(Equal(2))
+				})
+			})
+		})
+
+		Context("when the health hides the version", func() {
+			BeforeEach(func() {
+				grafanaClient.GetHealthReturns(grafana.Health{Database: "ok"}, nil)
+				grafanaClient.GetFrontendSettingsReturns(grafana.FrontendSettings{BuildInfo: grafana.BuildInfo{Version: "10.4.1"}}, nil)
+			})
+
+			It("returns the version of the frontend settings", func() {
+				Expect(version).To(Equal("10.4.1"))
+			})
+		})
+
+		Context("when it fails to get the health", func() {
+			BeforeEach(func() {
+				grafanaClient.GetHealthReturns(grafana.Health{}, errors.New("error"))
+			})
+
+			It("returns an unknown version", func() {
+				Expect(version).To(BeEmpty())
+			})
+
+			It("does not detect the version again before the retry interval", func() {
+				grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "9.3.2"}, nil)
+
+				Expect(versionDetector.Version(context.Background())).To(BeEmpty())
+				Expect(grafanaClient.GetHealthCallCount()).To(Equal(1))
+			})
+
+			Context("when the retry interval has elapsed", func() {
+				BeforeEach(func() {
+					retryInterval = 0
+				})
+
+				It("detects the version again before the interval", func() {
+					grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "9.3.2"}, nil)
+
+					Expect(versionDetector.Version(context.Background())).To(Equal("9.3.2"))
+					Expect(grafanaClient.GetHealthCallCount()).To(Equal(2))
+				})
+			})
+		})
+	})
+
+	Describe("Start", func() {
+		var (
+			startedDetector *VersionDetector
+			detecting       chan struct{}
+			release         chan struct{}
+		)
+
+		BeforeEach(func() {
+			detecting = make(chan struct{}, 1)
+			release = make(chan struct{})
+			grafanaClient.GetHealthStub = func(ctx context.Context) (grafana.Health, error) {
+				detecting <- struct{}{}
+				<-release
+				return grafana.Health{Database: "ok", Version: "10.4.1"}, nil
+			}
+
+			startedDetector = NewVersionDetector(grafanaClient, interval, retryInterval)
+			startedDetector.Start()
+		})
+
+		It("detects the version in the background", func() {
+			Eventually(detecting).Should(Receive())
+			close(release)
+
+			Eventually(func() string { return startedDetector.Version(context.Background()) }).Should(Equal("10.4.1"))
+		})
+
+		It("waits for the detection when the version was never detected", func() {
+			Eventually(detecting).Should(Receive())
+
+			versions := make(chan string, 1)
+			go func() {
+				versions <- startedDetector.Version(context.Background())
+			}()
+			Consistently(versions).ShouldNot(Receive())
+			close(release)
+
+			Eventually(versions).Should(Receive(Equal("10.4.1")))
+		})
+	})
+})
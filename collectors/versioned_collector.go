@@ -0,0 +1,41 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VersionedCollector scrapes a collector only when the detected Grafana
+// version is in the version range of the collector, so the collectors
+// relying on endpoints missing from the scraped Grafana do not report a
+// scrape error on every scrape.
+type VersionedCollector struct {
+	collector       ContextCollector
+	versionDetector *VersionDetector
+	versionRange    VersionRange
+}
+
+func NewVersionedCollector(collector ContextCollector, versionDetector *VersionDetector, versionRange VersionRange) *VersionedCollector {
+	return &VersionedCollector{
+		collector:       collector,
+		versionDetector: versionDetector,
+		versionRange:    versionRange,
+	}
+}
+
+func (c *VersionedCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.collector.Describe(ch)
+}
+
+func (c *VersionedCollector) Collect(ch chan<- prometheus.Metric) {
+	c.CollectContext(context.Background(), ch)
+}
+
+func (c *VersionedCollector) CollectContext(ctx context.Context, ch chan<- prometheus.Metric) {
+	if !c.versionRange.Contains(c.versionDetector.Version(ctx)) {
+		return
+	}
+
+	c.collector.CollectContext(ctx, ch)
+}
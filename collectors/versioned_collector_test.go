@@ -0,0 +1,97 @@
+package collectors_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+	"github.com/frodenas/grafana_exporter/grafana/grafanafakes"
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/frodenas/grafana_exporter/collectors"
+)
+
+var _ = Describe("VersionedCollector", func() {
+	var (
+		grafanaClient   *grafanafakes.FakeClient
+		versionDetector *VersionDetector
+		versionRange    VersionRange
+
+		versionedCollector *VersionedCollector
+	)
+
+	BeforeEach(func() {
+		grafanaClient = &grafanafakes.FakeClient{}
+		grafanaClient.GetHealthReturns(grafana.Health{Database: "ok", Version: "9.3.2"}, nil)
+		grafanaClient.GetOrgsReturns([]grafana.Org{{ID: 1, Name: "Main Org."}}, nil)
+		versionDetector = NewVersionDetector(grafanaClient, time.Hour, time.Hour)
+		versionRange = VersionRange{Min: "8.0.0"}
+	})
+
+	JustBeforeEach(func() {
+		versionedCollector = NewVersionedCollector(NewAlertingCollector(grafanaClient, prometheus.Labels{"grafana": "fake-grafana"}, OrgFilter{}), versionDetector, versionRange)
+	})
+
+	Describe("Describe", func() {
+		var (
+			descriptions chan *prometheus.Desc
+		)
+
+		BeforeEach(func() {
+			descriptions = make(chan *prometheus.Desc)
+		})
+
+		JustBeforeEach(func() {
+			go versionedCollector.Describe(descriptions)
+		})
+
+		It("returns the metric descriptions of the collector", func() {
+			Eventually(descriptions).Should(Receive(Equal(prometheus.NewDesc(
+				"grafana_alerting_rules",
+				"Number of Grafana Alerting Rules in the state.",
+				[]string{"org_id", "org_name", "folder_uid", "folder_title", "group", "state"},
+				prometheus.Labels{"grafana": "fake-grafana"},
+			))))
+		})
+	})
+
+	Describe("Collect", func() {
+		var (
+			metrics chan prometheus.Metric
+			done    chan struct{}
+		)
+
+		BeforeEach(func() {
+			metrics = make(chan prometheus.Metric, 100)
+			done = make(chan struct{})
+		})
+
+		JustBeforeEach(func() {
+			go func() {
+				versionedCollector.CollectContext(context.Background(), metrics)
+				close(done)
+			}()
+		})
+
+		It("scrapes the collector", func() {
+			Eventually(done).Should(BeClosed())
+			Expect(grafanaClient.GetOrgsCallCount()).To(Equal(1))
+			Expect(metrics).ToNot(BeEmpty())
+		})
+
+		Context("when the Grafana version is not in the version range", func() {
+			BeforeEach(func() {
+				versionRange = VersionRange{Min: "10.0.0"}
+			})
+
+			It("does not scrape the collector", func() {
+				Eventually(done).Should(BeClosed())
+				Expect(grafanaClient.GetOrgsCallCount()).To(Equal(0))
+				Expect(metrics).To(BeEmpty())
+			})
+		})
+	})
+})
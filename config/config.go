@@ -0,0 +1,476 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+const (
+	AdminStatsCollector        = "admin_stats"
+	AlertingCollector          = "alerting"
+	APIKeysCollector           = "api_keys"
+	DashboardActivityCollector = "dashboard_activity"
+	DashboardsCollector        = "dashboards"
+	DatasourcesCollector       = "datasources"
+	HealthCollector            = "health"
+	LegacyAlertsCollector      = "legacy_alerts"
+	MetricsCollector           = "metrics"
+	NotificationsCollector     = "notifications"
+	OrgStatsCollector          = "org_stats"
+	PanelsCollector            = "panels"
+	TeamsCollector             = "teams"
+	UsersCollector             = "users"
+)
+
+const GrafanaLabel = "grafana"
+
+// DefaultAPIKeysExpiryWindow is the default window the API keys and service
+// account tokens are reported as expiring within.
+const DefaultAPIKeysExpiryWindow = 7 * 24 * time.Hour
+
+// DefaultDashboardsInfoLimit is the default maximum number of dashboards
+// exported as per dashboard series.
+const DefaultDashboardsInfoLimit = 1000
+
+var (
+	AvailableCollectors = []string{
+		AdminStatsCollector,
+		AlertingCollector,
+		APIKeysCollector,
+		DashboardActivityCollector,
+		DashboardsCollector,
+		DatasourcesCollector,
+		HealthCollector,
+		LegacyAlertsCollector,
+		MetricsCollector,
+		NotificationsCollector,
+		OrgStatsCollector,
+		PanelsCollector,
+		TeamsCollector,
+		UsersCollector,
+	}
+
+	DefaultCollectors = []string{
+		AdminStatsCollector,
+		MetricsCollector,
+	}
+)
+
+type Config struct {
+	Modules  map[string]Module `yaml:"modules,omitempty"`
+	Grafanas []Grafana         `yaml:"grafanas,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+type ScrapeConfig struct {
+	grafana.HTTPClientConfig `yaml:",inline"`
+
+	Collectors  []string    `yaml:"collectors,omitempty"`
+	Orgs        Orgs        `yaml:"orgs,omitempty"`
+	APIKeys     APIKeys     `yaml:"api_keys,omitempty"`
+	Dashboards  Dashboards  `yaml:"dashboards,omitempty"`
+	Datasources Datasources `yaml:"datasources,omitempty"`
+	Panels      Panels      `yaml:"panels,omitempty"`
+	Teams       Teams       `yaml:"teams,omitempty"`
+	Users       Users       `yaml:"users,omitempty"`
+}
+
+// Orgs selects, by id or by name, the orgs scraped by the per org collectors.
+type Orgs struct {
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// APIKeys holds the settings of the api keys collector. The API keys and
+// service account tokens expiring within the expiry window are reported as
+// expiring, DefaultAPIKeysExpiryWindow if it is 0.
+type APIKeys struct {
+	ExpiryWindow time.Duration `yaml:"expiry_window,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// Dashboards holds the settings of the dashboards and dashboard activity
+// collectors. The info limit caps the number of dashboards exported as per
+// dashboard series, DefaultDashboardsInfoLimit if it is 0.
+type Dashboards struct {
+	InfoLimit int `yaml:"info_limit,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// Datasources holds the settings of the datasources collector. The
+// datasources health checks are disabled if the interval is 0.
+type Datasources struct {
+	HealthCheckInterval time.Duration `yaml:"health_check_interval,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// Panels holds the settings of the panels collector. The dashboards using a
+// panel of a deny type are reported.
+type Panels struct {
+	DenyTypes []string `yaml:"deny_types,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// Teams holds the settings of the teams collector. The folders permissions are
+// only requested if folder permissions is set.
+type Teams struct {
+	FolderPermissions bool `yaml:"folder_permissions,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// Users holds the settings of the users collector. The users logins are only
+// exported if last seen age is set.
+type Users struct {
+	LastSeenAge bool `yaml:"last_seen_age,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+// Module holds the settings used to probe Grafana instances. The module only
+// probes the targets matching one of its targets regular expressions, so its
+// credentials are never sent to an arbitrary target.
+type Module struct {
+	ScrapeConfig `yaml:",inline"`
+
+	Targets []string `yaml:"targets,omitempty"`
+
+	targetRegexps []*regexp.Regexp
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+type Grafana struct {
+	Name         string `yaml:"name,omitempty"`
+	URI          string `yaml:"uri"`
+	ScrapeConfig `yaml:",inline"`
+
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Catches all undefined fields and must be empty after parsing.
+	XXX map[string]interface{} `yaml:",inline"`
+}
+
+func Load(s string) (*Config, error) {
+	config := &Config{}
+
+	if err := yaml.Unmarshal([]byte(s), config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func LoadFile(filename string) (*Config, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error reading config file `%s`: %s", filename, err))
+	}
+
+	config, err := Load(string(content))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("Error parsing config file `%s`: %s", filename, err))
+	}
+
+	return config, nil
+}
+
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Config
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+
+	if err := checkOverflow(c.XXX, "config"); err != nil {
+		return err
+	}
+
+	return c.Validate()
+}
+
+// Validate applies the defaults to, and checks the consistency of, the
+// modules and Grafana instances. It must be called again whenever the
+// config is modified after being loaded.
+func (c *Config) Validate() error {
+	for name, module := range c.Modules {
+		if name == "" {
+			return errors.New("module name cannot be empty")
+		}
+		if err := module.validate(); err != nil {
+			return fmt.Errorf("module `%s`: %s", name, err)
+		}
+		c.Modules[name] = module
+	}
+
+	names := map[string]bool{}
+	var labelNames []string
+	for i := range c.Grafanas {
+		grafanaConfig := &c.Grafanas[i]
+		if err := grafanaConfig.validate(); err != nil {
+			return err
+		}
+
+		if names[grafanaConfig.Name] {
+			return fmt.Errorf("grafana `%s` is defined more than once", grafanaConfig.Name)
+		}
+		names[grafanaConfig.Name] = true
+
+		// All collectors of the same type share their metric descriptors, so
+		// every Grafana instance must define the same set of label names.
+		grafanaLabelNames := grafanaConfig.labelNames()
+		if i == 0 {
+			labelNames = grafanaLabelNames
+		} else if strings.Join(labelNames, ",") != strings.Join(grafanaLabelNames, ",") {
+			return fmt.Errorf("grafana `%s` labels (%s) differ from grafana `%s` labels (%s), all grafanas must define the same label names", grafanaConfig.Name, strings.Join(grafanaLabelNames, ", "), c.Grafanas[0].Name, strings.Join(labelNames, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Module
+	if err := unmarshal((*plain)(m)); err != nil {
+		return err
+	}
+
+	return checkOverflow(m.XXX, "module")
+}
+
+func (g *Grafana) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Grafana
+	if err := unmarshal((*plain)(g)); err != nil {
+		return err
+	}
+
+	return checkOverflow(g.XXX, "grafana")
+}
+
+func (o *Orgs) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Orgs
+	if err := unmarshal((*plain)(o)); err != nil {
+		return err
+	}
+
+	return checkOverflow(o.XXX, "orgs")
+}
+
+func (a *APIKeys) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain APIKeys
+	if err := unmarshal((*plain)(a)); err != nil {
+		return err
+	}
+
+	return checkOverflow(a.XXX, "api_keys")
+}
+
+func (d *Dashboards) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Dashboards
+	if err := unmarshal((*plain)(d)); err != nil {
+		return err
+	}
+
+	return checkOverflow(d.XXX, "dashboards")
+}
+
+func (d *Datasources) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Datasources
+	if err := unmarshal((*plain)(d)); err != nil {
+		return err
+	}
+
+	return checkOverflow(d.XXX, "datasources")
+}
+
+func (p *Panels) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Panels
+	if err := unmarshal((*plain)(p)); err != nil {
+		return err
+	}
+
+	return checkOverflow(p.XXX, "panels")
+}
+
+func (t *Teams) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Teams
+	if err := unmarshal((*plain)(t)); err != nil {
+		return err
+	}
+
+	return checkOverflow(t.XXX, "teams")
+}
+
+func (u *Users) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Users
+	if err := unmarshal((*plain)(u)); err != nil {
+		return err
+	}
+
+	return checkOverflow(u.XXX, "users")
+}
+
+// AllowsTarget returns whether the module may probe the target URI, that is
+// whether the URI fully matches one of the module targets.
+func (m *Module) AllowsTarget(target string) bool {
+	for _, targetRegexp := range m.targetRegexps {
+		if targetRegexp.MatchString(target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Module) validate() error {
+	m.targetRegexps = nil
+	for _, target := range m.Targets {
+		targetRegexp, err := regexp.Compile("^(?:" + target + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid target `%s`: %s", target, err)
+		}
+		m.targetRegexps = append(m.targetRegexps, targetRegexp)
+	}
+
+	return m.ScrapeConfig.validate()
+}
+
+// ConstLabels returns the labels attached to every metric exported for the
+// Grafana instance.
+func (g *Grafana) ConstLabels() map[string]string {
+	constLabels := map[string]string{GrafanaLabel: g.Name}
+	for name, value := range g.Labels {
+		constLabels[name] = value
+	}
+
+	return constLabels
+}
+
+func (g *Grafana) validate() error {
+	if g.URI == "" {
+		return errors.New("grafana `uri` is required")
+	}
+	if _, err := url.Parse(g.URI); err != nil {
+		return fmt.Errorf("grafana `%s` has an invalid uri: %s", g.URI, err)
+	}
+
+	if g.Name == "" {
+		g.Name = g.URI
+	}
+
+	for name := range g.Labels {
+		if !model.LabelName(name).IsValid() {
+			return fmt.Errorf("grafana `%s` has an invalid label name `%s`", g.Name, name)
+		}
+		if name == GrafanaLabel {
+			return fmt.Errorf("grafana `%s` cannot override the `%s` label", g.Name, GrafanaLabel)
+		}
+	}
+
+	if err := g.ScrapeConfig.validate(); err != nil {
+		return fmt.Errorf("grafana `%s`: %s", g.Name, err)
+	}
+
+	return nil
+}
+
+func (g *Grafana) labelNames() []string {
+	var labelNames []string
+	for name := range g.Labels {
+		labelNames = append(labelNames, name)
+	}
+	sort.Strings(labelNames)
+
+	return labelNames
+}
+
+func (s *ScrapeConfig) validate() error {
+	if s.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+
+	if s.APIKeys.ExpiryWindow < 0 {
+		return errors.New("api keys expiry window cannot be negative")
+	}
+
+	if s.Dashboards.InfoLimit < 0 {
+		return errors.New("dashboards info limit cannot be negative")
+	}
+
+	if s.Datasources.HealthCheckInterval < 0 {
+		return errors.New("datasources health check interval cannot be negative")
+	}
+
+	if err := s.HTTPClientConfig.Validate(); err != nil {
+		return err
+	}
+
+	if len(s.Collectors) == 0 {
+		s.Collectors = append([]string{}, DefaultCollectors...)
+	}
+
+	if s.APIKeys.ExpiryWindow == 0 {
+		s.APIKeys.ExpiryWindow = DefaultAPIKeysExpiryWindow
+	}
+
+	if s.Dashboards.InfoLimit == 0 {
+		s.Dashboards.InfoLimit = DefaultDashboardsInfoLimit
+	}
+
+	for _, collector := range s.Collectors {
+		if !isAvailableCollector(collector) {
+			return fmt.Errorf("unknown collector `%s`, available collectors are: %s", collector, strings.Join(AvailableCollectors, ", "))
+		}
+	}
+
+	return nil
+}
+
+func isAvailableCollector(name string) bool {
+	for _, collector := range AvailableCollectors {
+		if collector == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkOverflow(m map[string]interface{}, ctx string) error {
+	if len(m) > 0 {
+		var keys []string
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("unknown fields in %s: %s", ctx, strings.Join(keys, ", "))
+	}
+
+	return nil
+}
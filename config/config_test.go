@@ -0,0 +1,421 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/frodenas/grafana_exporter/config"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		content string
+		config  *Config
+		err     error
+	)
+
+	Describe("Load", func() {
+		BeforeEach(func() {
+			content = `
+modules:
+  default:
+    username: fake-username
+    password: fake-password
+    targets:
+      - https://grafana-a\.example\.com
+      - https://grafana-[0-9]+\.example\.com
+  insecure:
+    skip_ssl_verify: true
+`
+		})
+
+		JustBeforeEach(func() {
+			config, err = Load(content)
+		})
+
+		It("returns the modules", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Modules).To(HaveLen(2))
+			Expect(config.Modules["default"].Username).To(Equal("fake-username"))
+			Expect(config.Modules["default"].Password).To(Equal("fake-password"))
+			Expect(config.Modules["default"].SkipSSLVerify).To(BeFalse())
+			Expect(config.Modules["default"].Collectors).To(Equal(DefaultCollectors))
+			Expect(config.Modules["insecure"].SkipSSLVerify).To(BeTrue())
+		})
+
+		It("allows the modules to probe the targets matching their targets", func() {
+			Expect(err).ToNot(HaveOccurred())
+			module := config.Modules["default"]
+			Expect(module.AllowsTarget("https://grafana-a.example.com")).To(BeTrue())
+			Expect(module.AllowsTarget("https://grafana-1.example.com")).To(BeTrue())
+			Expect(module.AllowsTarget("https://grafana-b.example.com")).To(BeFalse())
+			Expect(module.AllowsTarget("https://grafana-a.example.com.attacker.com")).To(BeFalse())
+		})
+
+		It("does not allow the modules without targets to probe any target", func() {
+			Expect(err).ToNot(HaveOccurred())
+			module := config.Modules["insecure"]
+			Expect(module.AllowsTarget("https://grafana-a.example.com")).To(BeFalse())
+		})
+
+		Context("when the config has grafanas", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana-a.example.com
+    username: fake-username
+    password: fake-password
+    timeout: 5s
+    collectors:
+      - metrics
+      - org_stats
+      - datasources
+      - dashboards
+    dashboards:
+      info_limit: 100
+    datasources:
+      health_check_interval: 5m
+    api_keys:
+      expiry_window: 72h
+    orgs:
+      include:
+        - "1"
+        - team-a
+      exclude:
+        - team-b
+    labels:
+      team: a
+  - uri: https://grafana-b.example.com
+    skip_ssl_verify: true
+    labels:
+      team: b
+`
+			})
+
+			It("returns the grafanas", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config.Grafanas).To(HaveLen(2))
+
+				Expect(config.Grafanas[0].Name).To(Equal("team-a"))
+				Expect(config.Grafanas[0].URI).To(Equal("https://grafana-a.example.com"))
+				Expect(config.Grafanas[0].Username).To(Equal("fake-username"))
+				Expect(config.Grafanas[0].Password).To(Equal("fake-password"))
+				Expect(config.Grafanas[0].Timeout).To(Equal(5 * time.Second))
+				Expect(config.Grafanas[0].Collectors).To(Equal([]string{MetricsCollector, OrgStatsCollector, DatasourcesCollector, DashboardsCollector}))
+				Expect(config.Grafanas[0].Dashboards.InfoLimit).To(Equal(100))
+				Expect(config.Grafanas[0].Datasources.HealthCheckInterval).To(Equal(5 * time.Minute))
+				Expect(config.Grafanas[0].APIKeys.ExpiryWindow).To(Equal(72 * time.Hour))
+				Expect(config.Grafanas[0].Orgs.Include).To(Equal([]string{"1", "team-a"}))
+				Expect(config.Grafanas[0].Orgs.Exclude).To(Equal([]string{"team-b"}))
+				Expect(config.Grafanas[0].ConstLabels()).To(Equal(map[string]string{"grafana": "team-a", "team": "a"}))
+
+				Expect(config.Grafanas[1].Name).To(Equal("https://grafana-b.example.com"))
+				Expect(config.Grafanas[1].SkipSSLVerify).To(BeTrue())
+				Expect(config.Grafanas[1].Collectors).To(Equal(DefaultCollectors))
+				Expect(config.Grafanas[1].APIKeys.ExpiryWindow).To(Equal(DefaultAPIKeysExpiryWindow))
+				Expect(config.Grafanas[1].Orgs.Include).To(BeEmpty())
+				Expect(config.Grafanas[1].Orgs.Exclude).To(BeEmpty())
+				Expect(config.Grafanas[1].Dashboards.InfoLimit).To(Equal(DefaultDashboardsInfoLimit))
+				Expect(config.Grafanas[1].Datasources.HealthCheckInterval).To(BeZero())
+				Expect(config.Grafanas[1].ConstLabels()).To(Equal(map[string]string{"grafana": "https://grafana-b.example.com", "team": "b"}))
+			})
+		})
+
+		Context("when a grafana has no uri", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("grafana `uri` is required"))
+			})
+		})
+
+		Context("when a grafana is defined more than once", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - uri: https://grafana.example.com
+  - uri: https://grafana.example.com
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("grafana `https://grafana.example.com` is defined more than once"))
+			})
+		})
+
+		Context("when a grafana has an unknown collector", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana.example.com
+    collectors:
+      - unknown
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("grafana `team-a`: unknown collector `unknown`"))
+			})
+		})
+
+		Context("when a grafana overrides the grafana label", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana.example.com
+    labels:
+      grafana: other
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("grafana `team-a` cannot override the `grafana` label"))
+			})
+		})
+
+		Context("when grafanas define different label names", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana-a.example.com
+    labels:
+      team: a
+  - name: team-b
+    uri: https://grafana-b.example.com
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("all grafanas must define the same label names"))
+			})
+		})
+
+		Context("when the config is empty", func() {
+			BeforeEach(func() {
+				content = ""
+			})
+
+			It("returns an empty config", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config.Modules).To(BeEmpty())
+			})
+		})
+
+		Context("when the config has unknown fields", func() {
+			BeforeEach(func() {
+				content = `
+unknown: true
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown fields in config: unknown"))
+			})
+		})
+
+		Context("when a module has unknown fields", func() {
+			BeforeEach(func() {
+				content = `
+modules:
+  default:
+    user: fake-username
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown fields in module: user"))
+			})
+		})
+
+		Context("when a module has an invalid target", func() {
+			BeforeEach(func() {
+				content = `
+modules:
+  default:
+    targets:
+      - https://grafana-(a
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("module `default`: invalid target `https://grafana-(a`"))
+			})
+		})
+
+		Context("when a grafana has a negative api keys expiry window", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana.example.com
+    api_keys:
+      expiry_window: -1h
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("grafana `team-a`: api keys expiry window cannot be negative"))
+			})
+		})
+
+		Context("when a grafana has a negative dashboards info limit", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana.example.com
+    dashboards:
+      info_limit: -1
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("grafana `team-a`: dashboards info limit cannot be negative"))
+			})
+		})
+
+		Context("when a grafana has a negative datasources health check interval", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - name: team-a
+    uri: https://grafana.example.com
+    datasources:
+      health_check_interval: -1m
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("grafana `team-a`: datasources health check interval cannot be negative"))
+			})
+		})
+
+		Context("when the orgs have unknown fields", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - uri: https://grafana.example.com
+    orgs:
+      includes:
+        - team-a
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown fields in orgs: includes"))
+			})
+		})
+
+		Context("when the panels have unknown fields", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - uri: https://grafana.example.com
+    panels:
+      deny_type: graph
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown fields in panels: deny_type"))
+			})
+		})
+
+		Context("when the teams have unknown fields", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - uri: https://grafana.example.com
+    teams:
+      permissions: true
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown fields in teams: permissions"))
+			})
+		})
+
+		Context("when the users have unknown fields", func() {
+			BeforeEach(func() {
+				content = `
+grafanas:
+  - uri: https://grafana.example.com
+    users:
+      last_seen: true
+`
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown fields in users: last_seen"))
+			})
+		})
+	})
+
+	Describe("LoadFile", func() {
+		var (
+			filename string
+		)
+
+		BeforeEach(func() {
+			file, err := ioutil.TempFile("", "grafana_exporter")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = file.WriteString("modules:\n  default:\n    username: fake-username\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			filename = file.Name()
+		})
+
+		AfterEach(func() {
+			os.Remove(filename)
+		})
+
+		JustBeforeEach(func() {
+			config, err = LoadFile(filename)
+		})
+
+		It("returns the config", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Modules["default"].Username).To(Equal("fake-username"))
+		})
+
+		Context("when the file does not exist", func() {
+			BeforeEach(func() {
+				os.Remove(filename)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Error reading config file"))
+			})
+		})
+	})
+})
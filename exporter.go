@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/frodenas/grafana_exporter/collectors"
+	"github.com/frodenas/grafana_exporter/config"
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+var (
+	configLastReloadSuccessfulMetric = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "grafana_exporter",
+			Subsystem: "config",
+			Name:      "last_reload_successful",
+			Help:      "Whether the last configuration reload attempt was successful (1 for success, 0 for failure).",
+		},
+	)
+
+	configLastReloadSuccessTimestampMetric = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "grafana_exporter",
+			Subsystem: "config",
+			Name:      "last_reload_success_timestamp_seconds",
+			Help:      "Number of seconds since 1970 since the last successful configuration reload.",
+		},
+	)
+)
+
+// versionCheckInterval is the interval between two detections of the version
+// of a Grafana.
+const versionCheckInterval = 10 * time.Minute
+
+// versionRetryInterval is the interval before detecting again the version of
+// a Grafana whose detection failed.
+const versionRetryInterval = 30 * time.Second
+
+// collectorVersionRanges are the Grafana versions supported by the collectors
+// relying on endpoints added or removed by a Grafana release: the JSON
+// metrics were dropped for the Prometheus `/metrics` endpoint in Grafana 5,
+// folders, dashboard uids and teams came with Grafana 5, Grafana Alerting with
+// Grafana 8, and legacy alerting was removed in Grafana 11. The other
+// collectors are enabled whatever the Grafana version; the api keys and
+// notifications collectors skip the endpoints missing from the Grafana version
+// themselves.
+var collectorVersionRanges = map[string]collectors.VersionRange{
+	config.AlertingCollector:          {Min: "8.0.0"},
+	config.DashboardActivityCollector: {Min: "5.0.0"},
+	config.DashboardsCollector:        {Min: "5.0.0"},
+	config.LegacyAlertsCollector:      {Min: "5.0.0", Max: "11.0.0"},
+	config.MetricsCollector:           {Max: "5.0.0"},
+	config.OrgStatsCollector:          {Min: "5.0.0"},
+	config.PanelsCollector:            {Min: "5.0.0"},
+	config.TeamsCollector:             {Min: "5.0.0"},
+}
+
+type grafanaInstance struct {
+	config     config.Grafana
+	collectors []collectors.ContextCollector
+}
+
+// exporter holds the Grafana collectors built from the current configuration.
+// Reloading checks that every collector can be created and registered before
+// swapping them in, so a bad configuration never replaces a working one.
+type exporter struct {
+	loadConfig func() (*config.Config, error)
+
+	reloadMtx sync.Mutex
+	mtx       sync.RWMutex
+	config    *config.Config
+	instances map[string]grafanaInstance
+}
+
+func newExporter(loadConfig func() (*config.Config, error)) *exporter {
+	return &exporter{
+		loadConfig: loadConfig,
+		config:     &config.Config{},
+		instances:  map[string]grafanaInstance{},
+	}
+}
+
+func (e *exporter) Config() *config.Config {
+	e.mtx.RLock()
+	defer e.mtx.RUnlock()
+
+	return e.config
+}
+
+// Gatherer returns a prometheus.Gatherer that scrapes the Grafana instances
+// bound to the given context.
+func (e *exporter) Gatherer(ctx context.Context) prometheus.Gatherer {
+	e.mtx.RLock()
+	instances := e.instances
+	e.mtx.RUnlock()
+
+	registry := prometheus.NewRegistry()
+	for _, instance := range instances {
+		registry.MustRegister(withContext(ctx, instance.collectors)...)
+	}
+
+	return registry
+}
+
+func (e *exporter) Reload() error {
+	e.reloadMtx.Lock()
+	defer e.reloadMtx.Unlock()
+
+	if err := e.reload(); err != nil {
+		configLastReloadSuccessfulMetric.Set(0)
+		return err
+	}
+
+	configLastReloadSuccessfulMetric.Set(1)
+	configLastReloadSuccessTimestampMetric.Set(float64(time.Now().Unix()))
+
+	return nil
+}
+
+func (e *exporter) reload() error {
+	exporterConfig, err := e.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	registry := prometheus.NewRegistry()
+	instances := map[string]grafanaInstance{}
+	for _, grafanaConfig := range exporterConfig.Grafanas {
+		// Keep the collectors of unchanged Grafana instances, so their
+		// counters survive the reload.
+		instance, ok := e.instances[grafanaConfig.Name]
+		if !ok || !reflect.DeepEqual(instance.config, grafanaConfig) {
+			grafanaCollectors, err := newGrafanaCollectors(grafanaConfig)
+			if err != nil {
+				return fmt.Errorf("Error creating collectors for grafana `%s`: %s", grafanaConfig.Name, err)
+			}
+			instance = grafanaInstance{config: grafanaConfig, collectors: grafanaCollectors}
+		}
+
+		for _, grafanaCollector := range instance.collectors {
+			if err := registry.Register(grafanaCollector); err != nil {
+				return fmt.Errorf("Error registering collectors for grafana `%s`: %s", grafanaConfig.Name, err)
+			}
+		}
+		instances[grafanaConfig.Name] = instance
+	}
+
+	e.mtx.Lock()
+	e.config = exporterConfig
+	e.instances = instances
+	e.mtx.Unlock()
+
+	return nil
+}
+
+func newCollectors(grafanaClient grafana.Client, scrapeConfig config.ScrapeConfig, constLabels prometheus.Labels) []collectors.ContextCollector {
+	var grafanaCollectors []collectors.ContextCollector
+
+	versionDetector := collectors.NewVersionDetector(grafanaClient, versionCheckInterval, versionRetryInterval)
+	dashboardsFetcher := collectors.NewDashboardsFetcher(grafanaClient)
+	versionRanges := map[string]collectors.VersionRange{}
+	for _, collectorName := range scrapeConfig.Collectors {
+		var grafanaCollector collectors.ContextCollector
+		switch collectorName {
+		case config.AdminStatsCollector:
+			grafanaCollector = collectors.NewAdminStatsCollector(grafanaClient, constLabels)
+		case config.AlertingCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewAlertingCollector(grafanaClient, constLabels, orgFilter)
+		case config.APIKeysCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewAPIKeysCollector(grafanaClient, constLabels, orgFilter, scrapeConfig.APIKeys.ExpiryWindow)
+		case config.DashboardActivityCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewDashboardActivityCollector(grafanaClient, constLabels, orgFilter, dashboardsFetcher, scrapeConfig.Dashboards.InfoLimit)
+		case config.DashboardsCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewDashboardsCollector(grafanaClient, constLabels, orgFilter, dashboardsFetcher, scrapeConfig.Dashboards.InfoLimit)
+		case config.DatasourcesCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewDatasourcesCollector(grafanaClient, constLabels, orgFilter, scrapeConfig.Datasources.HealthCheckInterval)
+		case config.HealthCollector:
+			grafanaCollector = collectors.NewHealthCollector(grafanaClient, constLabels)
+		case config.LegacyAlertsCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewLegacyAlertsCollector(grafanaClient, constLabels, orgFilter, dashboardsFetcher)
+		case config.MetricsCollector:
+			grafanaCollector = collectors.NewMetricsCollector(grafanaClient, constLabels, *legacyMetricNames)
+		case config.NotificationsCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewNotificationsCollector(grafanaClient, constLabels, orgFilter)
+		case config.OrgStatsCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewOrgStatsCollector(grafanaClient, constLabels, orgFilter, dashboardsFetcher)
+		case config.PanelsCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewPanelsCollector(grafanaClient, constLabels, orgFilter, dashboardsFetcher, scrapeConfig.Panels.DenyTypes)
+		case config.TeamsCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewTeamsCollector(grafanaClient, constLabels, orgFilter, scrapeConfig.Teams.FolderPermissions)
+		case config.UsersCollector:
+			orgFilter := collectors.OrgFilter{Include: scrapeConfig.Orgs.Include, Exclude: scrapeConfig.Orgs.Exclude}
+			grafanaCollector = collectors.NewUsersCollector(grafanaClient, constLabels, orgFilter, scrapeConfig.Users.LastSeenAge)
+		default:
+			continue
+		}
+
+		// Keep the collectors of the Grafana versions lacking their endpoints
+		// from reporting a scrape error on every scrape.
+		versionRange, ok := collectorVersionRanges[collectorName]
+		if ok {
+			grafanaCollector = collectors.NewVersionedCollector(grafanaCollector, versionDetector, versionRange)
+		}
+		versionRanges[collectorName] = versionRange
+		grafanaCollectors = append(grafanaCollectors, grafanaCollector)
+	}
+	grafanaCollectors = append(grafanaCollectors, collectors.NewCollectorEnabledCollector(versionDetector, constLabels, versionRanges))
+
+	// Detect the version before the first scrape, unless no collector is
+	// bound to a version range.
+	for _, versionRange := range versionRanges {
+		if versionRange != (collectors.VersionRange{}) {
+			versionDetector.Start()
+			break
+		}
+	}
+
+	return grafanaCollectors
+}
+
+func newGrafanaCollectors(grafanaConfig config.Grafana) ([]collectors.ContextCollector, error) {
+	grafanaClient, err := grafana.NewHTTPClient(grafanaConfig.URI, grafanaConfig.HTTPClientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCollectors(grafanaClient, grafanaConfig.ScrapeConfig, grafanaConfig.ConstLabels()), nil
+}
+
+func withContext(ctx context.Context, contextCollectors []collectors.ContextCollector) []prometheus.Collector {
+	var grafanaCollectors []prometheus.Collector
+	for _, contextCollector := range contextCollectors {
+		grafanaCollectors = append(grafanaCollectors, collectors.WithContext(ctx, contextCollector))
+	}
+
+	return grafanaCollectors
+}
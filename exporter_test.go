@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/frodenas/grafana_exporter/config"
+)
+
+var _ = Describe("exporter", func() {
+	var (
+		exporterConfig  *config.Config
+		loadErr         error
+		grafanaExporter *exporter
+	)
+
+	loadConfig := func(s string) *config.Config {
+		exporterConfig, err := config.Load(s)
+		Expect(err).ToNot(HaveOccurred())
+		return exporterConfig
+	}
+
+	gaugeValue := func(gauge prometheus.Gauge) float64 {
+		metric := &dto.Metric{}
+		Expect(gauge.Write(metric)).To(Succeed())
+		return metric.GetGauge().GetValue()
+	}
+
+	BeforeEach(func() {
+		exporterConfig = loadConfig(`
+grafanas:
+  - name: team-a
+    uri: http://grafana-a.example.com
+  - name: team-b
+    uri: http://grafana-b.example.com
+`)
+		loadErr = nil
+
+		grafanaExporter = newExporter(func() (*config.Config, error) {
+			return exporterConfig, loadErr
+		})
+		Expect(grafanaExporter.Reload()).To(Succeed())
+	})
+
+	Describe("Reload", func() {
+		It("builds the collectors of every Grafana", func() {
+			Expect(grafanaExporter.Config()).To(Equal(exporterConfig))
+			Expect(grafanaExporter.instances).To(HaveLen(2))
+			Expect(grafanaExporter.instances["team-a"].collectors).ToNot(BeEmpty())
+			Expect(grafanaExporter.instances["team-b"].collectors).ToNot(BeEmpty())
+		})
+
+		It("sets the last reload metrics", func() {
+			Expect(gaugeValue(configLastReloadSuccessfulMetric)).To(Equal(float64(1)))
+			Expect(gaugeValue(configLastReloadSuccessTimestampMetric)).To(BeNumerically("~", time.Now().Unix(), 5))
+		})
+
+		Context("when the configuration is reloaded", func() {
+			var previousInstances map[string]grafanaInstance
+
+			BeforeEach(func() {
+				previousInstances = grafanaExporter.instances
+				exporterConfig = loadConfig(`
+grafanas:
+  - name: team-a
+    uri: http://grafana-a.example.com
+  - name: team-b
+    uri: http://grafana-b.example.com
+    timeout: 5s
+  - name: team-c
+    uri: http://grafana-c.example.com
+`)
+				Expect(grafanaExporter.Reload()).To(Succeed())
+			})
+
+			It("swaps the new configuration in", func() {
+				Expect(grafanaExporter.Config()).To(Equal(exporterConfig))
+				Expect(grafanaExporter.instances).To(HaveLen(3))
+			})
+
+			It("reuses the collectors of the unchanged Grafanas", func() {
+				Expect(grafanaExporter.instances["team-a"].collectors[0]).To(BeIdenticalTo(previousInstances["team-a"].collectors[0]))
+			})
+
+			It("rebuilds the collectors of the changed Grafanas", func() {
+				Expect(grafanaExporter.instances["team-b"].collectors[0]).ToNot(BeIdenticalTo(previousInstances["team-b"].collectors[0]))
+			})
+		})
+
+		Context("when the reload fails", func() {
+			var (
+				previousConfig    *config.Config
+				previousInstances map[string]grafanaInstance
+				previousTimestamp float64
+				reloadErr         error
+			)
+
+			BeforeEach(func() {
+				previousConfig = grafanaExporter.Config()
+				previousInstances = grafanaExporter.instances
+				configLastReloadSuccessTimestampMetric.Set(1)
+				previousTimestamp = gaugeValue(configLastReloadSuccessTimestampMetric)
+			})
+
+			JustBeforeEach(func() {
+				reloadErr = grafanaExporter.Reload()
+			})
+
+			Context("because the configuration cannot be loaded", func() {
+				BeforeEach(func() {
+					loadErr = errors.New("fake-error")
+				})
+
+				It("returns the error", func() {
+					Expect(reloadErr).To(MatchError("fake-error"))
+				})
+
+				It("keeps the previous configuration and collectors", func() {
+					Expect(grafanaExporter.Config()).To(BeIdenticalTo(previousConfig))
+					Expect(grafanaExporter.instances).To(Equal(previousInstances))
+				})
+
+				It("sets the last reload metrics", func() {
+					Expect(gaugeValue(configLastReloadSuccessfulMetric)).To(Equal(float64(0)))
+					Expect(gaugeValue(configLastReloadSuccessTimestampMetric)).To(Equal(previousTimestamp))
+				})
+			})
+
+			Context("because the collectors of a Grafana cannot be created", func() {
+				BeforeEach(func() {
+					exporterConfig = loadConfig(`
+grafanas:
+  - name: team-a
+    uri: http://grafana-a.example.com
+  - name: team-b
+    uri: https://grafana-b.example.com
+    ca_file: /non-existent-ca-file
+`)
+				})
+
+				It("returns the error", func() {
+					Expect(reloadErr).To(HaveOccurred())
+					Expect(reloadErr.Error()).To(HavePrefix("Error creating collectors for grafana `team-b`"))
+				})
+
+				It("keeps the previous configuration and collectors", func() {
+					Expect(grafanaExporter.Config()).To(BeIdenticalTo(previousConfig))
+					Expect(grafanaExporter.instances).To(Equal(previousInstances))
+				})
+
+				It("sets the last reload metrics", func() {
+					Expect(gaugeValue(configLastReloadSuccessfulMetric)).To(Equal(float64(0)))
+					Expect(gaugeValue(configLastReloadSuccessTimestampMetric)).To(Equal(previousTimestamp))
+				})
+			})
+		})
+	})
+})
@@ -1,8 +1,69 @@
 package grafana
 
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
 type Client interface {
-	GetAdminStats() (AdminStats, error)
-	GetMetrics() (Metrics, error)
+	GetAdminStats(ctx context.Context) (AdminStats, error)
+	GetMetrics(ctx context.Context) (Metrics, error)
+	GetHealth(ctx context.Context) (Health, error)
+	GetFrontendSettings(ctx context.Context) (FrontendSettings, error)
+	GetOrgs(ctx context.Context) ([]Org, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	GetDashboards(ctx context.Context, orgID int64) ([]SearchHit, error)
+	GetDashboard(ctx context.Context, orgID int64, uid string) (Dashboard, error)
+	GetDashboardVersions(ctx context.Context, orgID int64, uid string, limit int) (DashboardVersions, error)
+	GetLibraryPanel(ctx context.Context, orgID int64, uid string) (LibraryPanel, error)
+	GetFolders(ctx context.Context, orgID int64) ([]Folder, error)
+	GetFolderPermissions(ctx context.Context, orgID int64, uid string) ([]FolderPermission, error)
+	GetTeams(ctx context.Context, orgID int64) ([]Team, error)
+	GetDatasources(ctx context.Context, orgID int64) ([]Datasource, error)
+	GetDatasourceHealth(ctx context.Context, orgID int64, uid string) (DatasourceHealth, error)
+	GetOrgUsers(ctx context.Context, orgID int64) ([]OrgUser, error)
+	GetAlertRules(ctx context.Context, orgID int64) ([]AlertRule, error)
+	GetAlerts(ctx context.Context, orgID int64) ([]Alert, error)
+	GetRuleGroups(ctx context.Context, orgID int64) ([]RuleGroup, error)
+	GetAlertmanagerAlerts(ctx context.Context, orgID int64) ([]AlertmanagerAlert, error)
+	GetSilences(ctx context.Context, orgID int64) ([]Silence, error)
+	GetNotificationChannels(ctx context.Context, orgID int64) ([]NotificationChannel, error)
+	GetContactPoints(ctx context.Context, orgID int64) ([]ContactPoint, error)
+	GetReceivers(ctx context.Context, orgID int64) ([]Receiver, error)
+	GetAPIKeys(ctx context.Context, orgID int64) ([]APIKey, error)
+	GetServiceAccounts(ctx context.Context, orgID int64) ([]ServiceAccount, error)
+	GetServiceAccountTokens(ctx context.Context, orgID int64, serviceAccountID int64) ([]ServiceAccountToken, error)
+}
+
+// Health is the health of a Grafana, as returned by the `/api/health`
+// endpoint. The database is `ok` or `failing`.
+type Health struct {
+	Commit   string `json:"commit"`
+	Database string `json:"database"`
+	Version  string `json:"version"`
+}
+
+// FrontendSettings are the settings of a Grafana exposed to its frontend, as
+// returned by the `/api/frontend/settings` endpoint.
+type FrontendSettings struct {
+	BuildInfo      BuildInfo       `json:"buildInfo"`
+	FeatureToggles map[string]bool `json:"featureToggles"`
+	LicenseInfo    LicenseInfo     `json:"licenseInfo"`
+}
+
+type BuildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Edition string `json:"edition"`
+}
+
+// LicenseInfo is the license of a Grafana. The expiry is a unix timestamp,
+// zero when the Grafana has no license.
+type LicenseInfo struct {
+	HasLicense bool   `json:"hasLicense"`
+	Expiry     int64  `json:"expiry"`
+	StateInfo  string `json:"stateInfo"`
 }
 
 type AdminStats struct {
@@ -17,78 +78,453 @@ type AdminStats struct {
 	UserCount       int `json:"user_count"`
 }
 
-type Metrics struct {
-	AlertingActiveAlerts               Gauge   `json:"alerting.active_alerts"`
-	AlertingExecutionTime              Timer   `json:"alerting.execution_time"`
-	AlertingNotificationsSentLine      Counter `json:"alerting.notifications_sent.type_LINE"`
-	AlertingNotificationsSentDingDing  Counter `json:"alerting.notifications_sent.type_dingding"`
-	AlertingNotificationsSentEmail     Counter `json:"alerting.notifications_sent.type_email"`
-	AlertingNotificationsSentOpsgenie  Counter `json:"alerting.notifications_sent.type_opsgenie"`
-	AlertingNotificationsSentPagerduty Counter `json:"alerting.notifications_sent.type_pagerduty"`
-	AlertingNotificationsSentPushover  Counter `json:"alerting.notifications_sent.type_pushover"`
-	AlertingNotificationsSentSensu     Counter `json:"alerting.notifications_sent.type_sensu"`
-	AlertingNotificationsSentSlack     Counter `json:"alerting.notifications_sent.type_slack"`
-	AlertingNotificationsSentTelegram  Counter `json:"alerting.notifications_sent.type_telegram"`
-	AlertingNotificationsSentThreema   Counter `json:"alerting.notifications_sent.type_threema"`
-	AlertingNotificationsSentVictorops Counter `json:"alerting.notifications_sent.type_victorops"`
-	AlertingNotificationsSentWebhook   Counter `json:"alerting.notifications_sent.type_webhook"`
-	AlertingResultStateAlerting        Counter `json:"alerting.result.state_alerting"`
-	AlertingResultStateNoData          Counter `json:"alerting.result.state_no_data"`
-	AlertingResultStateOk              Counter `json:"alerting.result.state_ok"`
-	AlertingResultStatePaused          Counter `json:"alerting.result.state_paused"`
-	AlertingResultStatePending         Counter `json:"alerting.result.state_pending"`
-	APIAdminUserCreate                 Counter `json:"api.admin.user_create"`
-	APIDashboardGet                    Timer   `json:"api.dashboard.get"`
-	APIDashboardSave                   Timer   `json:"api.dashboard.save"`
-	APIDashboardSearch                 Timer   `json:"api.dashboard.search"`
-	APIDashboardSnapshotCreate         Counter `json:"api.dashboard_snapshot.create"`
-	APIDashboardSnapshotExternal       Counter `json:"api.dashboard_snapshot.external"`
-	APIDashboardSnapshotGet            Counter `json:"api.dashboard_snapshot.get"`
-	APIDataproxyRequestAll             Timer   `json:"api.dataproxy.request.all"`
-	APILoginOauth                      Counter `json:"api.login.oauth"`
-	APILoginPost                       Counter `json:"api.login.post"`
-	APIOrgCreate                       Counter `json:"api.org.create"`
-	APIRespStatusCode200               Counter `json:"api.resp_status.code_200"`
-	APIRespStatusCode404               Counter `json:"api.resp_status.code_404"`
-	APIRespStatusCode500               Counter `json:"api.resp_status.code_500"`
-	APIRespStatusCodeUnknown           Counter `json:"api.resp_status.code_unknown"`
-	APIUserSignupCompleted             Counter `json:"api.user.signup_completed"`
-	APIUserSignupInvite                Counter `json:"api.user.signup_invite"`
-	APIUserSignupStarted               Counter `json:"api.user.signup_started"`
-	AWSCloudwatchGetMetricStatistics   Counter `json:"aws.cloudwatch.get_metric_statistics"`
-	AWSCloudwatchListMetrics           Counter `json:"aws.cloudwatch.list_metrics"`
-	InstanceStart                      Counter `json:"instance_start"`
-	ModelsDashboardInsert              Counter `json:"models.dashboard.insert"`
-	PageRespStatusCode200              Counter `json:"page.resp_status.code_200"`
-	PageRespStatusCode404              Counter `json:"page.resp_status.code_404"`
-	PageRespStatusCode500              Counter `json:"page.resp_status.code_500"`
-	PageRespStatusCodeUnknown          Counter `json:"page.resp_status.code_unknown"`
-	ProxyRespStatusCode200             Counter `json:"proxy.resp_status.code_200"`
-	ProxyRespStatusCode404             Counter `json:"proxy.resp_status.code_404"`
-	ProxyRespStatusCode500             Counter `json:"proxy.resp_status.code_500"`
-	ProxyRespStatusCodeUnknown         Counter `json:"proxy.resp_status.code_unknown"`
-	StatsTotalsStatDashboards          Gauge   `json:"stat_totals.stat_dashboards"`
-	StatsTotalsStatOrgs                Gauge   `json:"stat_totals.stat_orgs"`
-	StatsTotalsStatPlaylists           Gauge   `json:"stat_totals.stat_playlists"`
-	StatsTotalsStatUsers               Gauge   `json:"stat_totals.stat_users"`
+type Org struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// User is a Grafana user, as returned by the users search. The auth labels
+// name the auth modules the user logged in with (i.e. `LDAP`, `OAuth`), none
+// for the basic auth users.
+type User struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Login      string    `json:"login"`
+	Email      string    `json:"email"`
+	IsAdmin    bool      `json:"isAdmin"`
+	IsDisabled bool      `json:"isDisabled"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	AuthLabels []string  `json:"authLabels"`
+}
+
+type SearchHit struct {
+	ID          int64    `json:"id"`
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Type        string   `json:"type"`
+	Tags        []string `json:"tags"`
+	FolderID    int64    `json:"folderId"`
+	FolderUID   string   `json:"folderUid"`
+	FolderTitle string   `json:"folderTitle"`
+}
+
+// Dashboard holds a dashboard as returned by `/api/dashboards/uid/<uid>`: its
+// model, and the metadata Grafana keeps about it.
+type Dashboard struct {
+	Dashboard DashboardModel `json:"dashboard"`
+	Meta      DashboardMeta  `json:"meta"`
+}
+
+type DashboardModel struct {
+	UID           string         `json:"uid"`
+	Title         string         `json:"title"`
+	Tags          []string       `json:"tags"`
+	SchemaVersion int            `json:"schemaVersion"`
+	Version       int            `json:"version"`
+	Panels        []Panel        `json:"panels,omitempty"`
+	Rows          []DashboardRow `json:"rows,omitempty"`
+}
+
+// DashboardRow holds the panels of a row of the dashboards saved before
+// schema version 16, which nest their panels in rows.
+type DashboardRow struct {
+	Title  string  `json:"title"`
+	Panels []Panel `json:"panels"`
+}
+
+// Panel holds the fields of a dashboard panel the exporter knows about. The
+// panels of a collapsed row are nested in the row panel, and a library panel
+// only references its library element.
+type Panel struct {
+	ID           int64            `json:"id"`
+	Type         string           `json:"type"`
+	Title        string           `json:"title"`
+	Datasource   *PanelDatasource `json:"datasource,omitempty"`
+	Targets      []PanelTarget    `json:"targets,omitempty"`
+	Panels       []Panel          `json:"panels,omitempty"`
+	LibraryPanel *LibraryPanelRef `json:"libraryPanel,omitempty"`
+}
+
+// PanelTarget holds a query of a panel. A target without a datasource uses
+// the datasource of its panel.
+type PanelTarget struct {
+	RefID      string           `json:"refId"`
+	Datasource *PanelDatasource `json:"datasource,omitempty"`
+}
+
+// PanelDatasource references the datasource of a panel: by name up to
+// Grafana 8.2, and by uid and type since.
+type PanelDatasource struct {
+	UID  string `json:"uid,omitempty"`
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type LibraryPanelRef struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+type LibraryPanel struct {
+	UID   string `json:"uid"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Model Panel  `json:"model"`
+}
+
+type DashboardMeta struct {
+	Slug        string    `json:"slug"`
+	FolderID    int64     `json:"folderId"`
+	FolderUID   string    `json:"folderUid"`
+	FolderTitle string    `json:"folderTitle"`
+	Provisioned bool      `json:"provisioned"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+	CreatedBy   string    `json:"createdBy"`
+	UpdatedBy   string    `json:"updatedBy"`
+	Version     int       `json:"version"`
+}
+
+// DashboardVersions holds the saved versions of a dashboard, the most recent
+// first.
+type DashboardVersions []DashboardVersion
+
+type DashboardVersion struct {
+	ID            int64     `json:"id"`
+	ParentVersion int       `json:"parentVersion"`
+	RestoredFrom  int       `json:"restoredFrom"`
+	Version       int       `json:"version"`
+	Created       time.Time `json:"created"`
+	CreatedBy     string    `json:"createdBy"`
+	Message       string    `json:"message"`
+}
+
+type Folder struct {
+	ID    int64  `json:"id"`
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+// FolderPermission is a grant of a permission on a folder to either a user, a
+// team or an org role.
+type FolderPermission struct {
+	UserID         int64  `json:"userId"`
+	UserLogin      string `json:"userLogin"`
+	TeamID         int64  `json:"teamId"`
+	Team           string `json:"team"`
+	Role           string `json:"role"`
+	Permission     int    `json:"permission"`
+	PermissionName string `json:"permissionName"`
+}
+
+type Team struct {
+	ID          int64  `json:"id"`
+	OrgID       int64  `json:"orgId"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	MemberCount int    `json:"memberCount"`
+}
+
+type Datasource struct {
+	ID        int64  `json:"id"`
+	UID       string `json:"uid"`
+	OrgID     int64  `json:"orgId"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Access    string `json:"access"`
+	IsDefault bool   `json:"isDefault"`
+	ReadOnly  bool   `json:"readOnly"`
+}
+
+// DatasourceHealth holds the result of a datasource health check. The status
+// is `OK` when the datasource is healthy.
+type DatasourceHealth struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type OrgUser struct {
+	OrgID      int64     `json:"orgId"`
+	UserID     int64     `json:"userId"`
+	Email      string    `json:"email"`
+	Login      string    `json:"login"`
+	Role       string    `json:"role"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
+
+// AlertRule is a Grafana Alerting rule, as returned by the alerting
+// provisioning API (Grafana 9+).
+type AlertRule struct {
+	ID        int64  `json:"id"`
+	UID       string `json:"uid"`
+	OrgID     int64  `json:"orgID"`
+	FolderUID string `json:"folderUID"`
+	RuleGroup string `json:"ruleGroup"`
+	Title     string `json:"title"`
+	IsPaused  bool   `json:"isPaused"`
+}
+
+// Alert is a legacy dashboard alert, as returned by the `/api/alerts`
+// endpoint (before Grafana 11). The new state date is the time the alert
+// entered its current state.
+type Alert struct {
+	ID           int64     `json:"id"`
+	DashboardID  int64     `json:"dashboardId"`
+	DashboardUID string    `json:"dashboardUid"`
+	PanelID      int64     `json:"panelId"`
+	Name         string    `json:"name"`
+	State        string    `json:"state"`
+	NewStateDate time.Time `json:"newStateDate"`
+	URL          string    `json:"url"`
+}
+
+// RuleGroup is a Grafana Alerting rule group, as returned by the
+// Prometheus-compatible `/api/prometheus/grafana/api/v1/rules` endpoint.
+// The file is the title of the folder of the group. The folder uid is only
+// returned by the recent Grafana versions.
+type RuleGroup struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	FolderUID string `json:"folderUid"`
+	Rules     []Rule `json:"rules"`
+}
+
+// Rule is the evaluation status of a Grafana Alerting rule. The state is
+// one of `inactive`, `pending` or `firing`, and the health one of `ok`,
+// `error` or `nodata`.
+type Rule struct {
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Health    string `json:"health"`
+	LastError string `json:"lastError"`
+	Type      string `json:"type"`
+}
+
+// AlertmanagerAlert is an alert instance of the Grafana Alertmanager.
+type AlertmanagerAlert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Labels      map[string]string `json:"labels"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Status      AlertStatus       `json:"status"`
+}
+
+// AlertStatus is the status of an Alertmanager alert. The state is one of
+// `active`, `suppressed` or `unprocessed`.
+type AlertStatus struct {
+	State string `json:"state"`
+}
+
+// Silence is a silence of the Grafana Alertmanager.
+type Silence struct {
+	ID       string        `json:"id"`
+	Comment  string        `json:"comment"`
+	StartsAt time.Time     `json:"startsAt"`
+	EndsAt   time.Time     `json:"endsAt"`
+	Status   SilenceStatus `json:"status"`
+}
+
+// SilenceStatus is the status of a silence. The state is one of `active`,
+// `pending` or `expired`.
+type SilenceStatus struct {
+	State string `json:"state"`
+}
+
+// NotificationChannel is a legacy alerting notification channel, as returned
+// by the `/api/alert-notifications` endpoint (before Grafana 11).
+type NotificationChannel struct {
+	ID                    int64  `json:"id"`
+	UID                   string `json:"uid"`
+	Name                  string `json:"name"`
+	Type                  string `json:"type"`
+	IsDefault             bool   `json:"isDefault"`
+	SendReminder          bool   `json:"sendReminder"`
+	DisableResolveMessage bool   `json:"disableResolveMessage"`
+}
+
+// ContactPoint is an integration of a Grafana Alerting contact point, as
+// returned by the alerting provisioning API (Grafana 9+). The integrations of
+// a contact point share its name.
+type ContactPoint struct {
+	UID                   string `json:"uid"`
+	Name                  string `json:"name"`
+	Type                  string `json:"type"`
+	DisableResolveMessage bool   `json:"disableResolveMessage"`
+}
+
+// Receiver is the notification status of a contact point of the Grafana
+// Alertmanager.
+type Receiver struct {
+	Name         string                `json:"name"`
+	Active       bool                  `json:"active"`
+	Integrations []ReceiverIntegration `json:"integrations"`
+}
+
+// ReceiverIntegration is the notification status of an integration of a
+// contact point. The last notify attempt is zero when the integration never
+// notified.
+type ReceiverIntegration struct {
+	Name                   string    `json:"name"`
+	LastNotifyAttempt      time.Time `json:"lastNotifyAttempt"`
+	LastNotifyAttemptError string    `json:"lastNotifyAttemptError"`
+	SendResolved           bool      `json:"sendResolved"`
+}
+
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+}
+
+// ServiceAccount is a Grafana service account (Grafana 9+). Tokens is the
+// number of tokens of the service account.
+type ServiceAccount struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Login      string `json:"login"`
+	Role       string `json:"role"`
+	IsDisabled bool   `json:"isDisabled"`
+	Tokens     int    `json:"tokens"`
+}
+
+type ServiceAccountToken struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Created    time.Time  `json:"created"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+	HasExpired bool       `json:"hasExpired"`
+}
+
+// Metrics holds every metric returned by the `/api/metrics` endpoint, keyed by
+// its dotted Grafana name (i.e. `alerting.notifications_sent.type_email`).
+type Metrics map[string]Metric
+
+// Metric is a Grafana metric. As the `/api/metrics` endpoint carries no
+// schema, the kind of the metric is inferred from the shape of its value, and
+// only the matching field is set. None is set for unknown shapes.
+type Metric struct {
+	Counter *Counter
+	Gauge   *Gauge
+	Timer   *Timer
 }
 
 type Counter struct {
-	Count int64 `json:"count,omitempty"`
+	Count int64 `json:"count"`
 }
 
 type Gauge struct {
-	Value int64 `json:"value,omitempty"`
+	Value float64 `json:"value"`
 }
 
 type Timer struct {
-	Count int64   `json:"count,omitempty"`
-	Max   int64   `json:"max,omitempty"`
-	Mean  float64 `json:"mean,omitempty"`
-	Min   int64   `json:"min,omitempty"`
-	P25   float64 `json:"p25,omitempty"`
-	P75   float64 `json:"p75,omitempty"`
-	P90   float64 `json:"p90,omitempty"`
-	P99   float64 `json:"p99,omitempty"`
-	Std   float64 `json:"std,omitempty"`
+	Count int64   `json:"count"`
+	Max   int64   `json:"max"`
+	Mean  float64 `json:"mean"`
+	Min   int64   `json:"min"`
+	P25   float64 `json:"p25"`
+	P75   float64 `json:"p75"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Std   float64 `json:"std"`
+}
+
+// timerFields are the fields that tell a timer apart from a counter, as both
+// carry a `count` field.
+var timerFields = []string{"max", "mean", "min", "p25", "p75", "p90", "p99", "std"}
+
+func (m *Metric) UnmarshalJSON(data []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not an object, so not a metric this exporter knows about.
+		return nil
+	}
+
+	values := map[string]float64{}
+	for name, field := range fields {
+		if value, ok := field.(float64); ok {
+			values[name] = value
+		}
+	}
+
+	*m = Metric{}
+	switch {
+	case hasField(values, "value"):
+		m.Gauge = &Gauge{Value: values["value"]}
+	case hasField(values, timerFields...):
+		m.Timer = &Timer{
+			Count: int64(values["count"]),
+			Max:   int64(values["max"]),
+			Mean:  values["mean"],
+			Min:   int64(values["min"]),
+			P25:   values["p25"],
+			P75:   values["p75"],
+			P90:   values["p90"],
+			P99:   values["p99"],
+			Std:   values["std"],
+		}
+	case hasField(values, "count"):
+		m.Counter = &Counter{Count: int64(values["count"])}
+	}
+
+	return nil
+}
+
+func (m Metric) MarshalJSON() ([]byte, error) {
+	switch {
+	case m.Gauge != nil:
+		return json.Marshal(m.Gauge)
+	case m.Timer != nil:
+		return json.Marshal(m.Timer)
+	case m.Counter != nil:
+		return json.Marshal(m.Counter)
+	}
+
+	return []byte("{}"), nil
+}
+
+func hasField(values map[string]float64, names ...string) bool {
+	for _, name := range names {
+		if _, ok := values[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UnmarshalJSON accepts both the versions list returned by Grafana up to
+// version 10 and the paginated object, holding the list in its `versions`
+// field, returned by the later versions.
+func (v *DashboardVersions) UnmarshalJSON(data []byte) error {
+	var versions []DashboardVersion
+	if err := json.Unmarshal(data, &versions); err == nil {
+		*v = versions
+		return nil
+	}
+
+	var page struct {
+		Versions []DashboardVersion `json:"versions"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return err
+	}
+	*v = page.Versions
+
+	return nil
+}
+
+// UnmarshalJSON accepts both a datasource name and a datasource reference
+// object.
+func (d *PanelDatasource) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*d = PanelDatasource{Name: name}
+		return nil
+	}
+
+	type plain PanelDatasource
+	return json.Unmarshal(data, (*plain)(d))
 }
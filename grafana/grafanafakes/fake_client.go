@@ -2,16 +2,19 @@
 package grafanafakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/frodenas/grafana_exporter/grafana"
 )
 
 type FakeClient struct {
-	GetAdminStatsStub        func() (grafana.AdminStats, error)
+	GetAdminStatsStub        func(ctx context.Context) (grafana.AdminStats, error)
 	getAdminStatsMutex       sync.RWMutex
-	getAdminStatsArgsForCall []struct{}
-	getAdminStatsReturns     struct {
+	getAdminStatsArgsForCall []struct {
+		ctx context.Context
+	}
+	getAdminStatsReturns struct {
 		result1 grafana.AdminStats
 		result2 error
 	}
@@ -19,10 +22,12 @@ type FakeClient struct {
 		result1 grafana.AdminStats
 		result2 error
 	}
-	GetMetricsStub        func() (grafana.Metrics, error)
+	GetMetricsStub        func(ctx context.Context) (grafana.Metrics, error)
 	getMetricsMutex       sync.RWMutex
-	getMetricsArgsForCall []struct{}
-	getMetricsReturns     struct {
+	getMetricsArgsForCall []struct {
+		ctx context.Context
+	}
+	getMetricsReturns struct {
 		result1 grafana.Metrics
 		result2 error
 	}
@@ -30,18 +35,373 @@ type FakeClient struct {
 		result1 grafana.Metrics
 		result2 error
 	}
+	GetHealthStub        func(ctx context.Context) (grafana.Health, error)
+	getHealthMutex       sync.RWMutex
+	getHealthArgsForCall []struct {
+		ctx context.Context
+	}
+	getHealthReturns struct {
+		result1 grafana.Health
+		result2 error
+	}
+	getHealthReturnsOnCall map[int]struct {
+		result1 grafana.Health
+		result2 error
+	}
+	GetFrontendSettingsStub        func(ctx context.Context) (grafana.FrontendSettings, error)
+	getFrontendSettingsMutex       sync.RWMutex
+	getFrontendSettingsArgsForCall []struct {
+		ctx context.Context
+	}
+	getFrontendSettingsReturns struct {
+		result1 grafana.FrontendSettings
+		result2 error
+	}
+	getFrontendSettingsReturnsOnCall map[int]struct {
+		result1 grafana.FrontendSettings
+		result2 error
+	}
+	GetOrgsStub        func(ctx context.Context) ([]grafana.Org, error)
+	getOrgsMutex       sync.RWMutex
+	getOrgsArgsForCall []struct {
+		ctx context.Context
+	}
+	getOrgsReturns struct {
+		result1 []grafana.Org
+		result2 error
+	}
+	getOrgsReturnsOnCall map[int]struct {
+		result1 []grafana.Org
+		result2 error
+	}
+	GetUsersStub        func(ctx context.Context) ([]grafana.User, error)
+	getUsersMutex       sync.RWMutex
+	getUsersArgsForCall []struct {
+		ctx context.Context
+	}
+	getUsersReturns struct {
+		result1 []grafana.User
+		result2 error
+	}
+	getUsersReturnsOnCall map[int]struct {
+		result1 []grafana.User
+		result2 error
+	}
+	GetDashboardsStub        func(ctx context.Context, orgID int64) ([]grafana.SearchHit, error)
+	getDashboardsMutex       sync.RWMutex
+	getDashboardsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getDashboardsReturns struct {
+		result1 []grafana.SearchHit
+		result2 error
+	}
+	getDashboardsReturnsOnCall map[int]struct {
+		result1 []grafana.SearchHit
+		result2 error
+	}
+	GetDashboardStub        func(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error)
+	getDashboardMutex       sync.RWMutex
+	getDashboardArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}
+	getDashboardReturns struct {
+		result1 grafana.Dashboard
+		result2 error
+	}
+	getDashboardReturnsOnCall map[int]struct {
+		result1 grafana.Dashboard
+		result2 error
+	}
+	GetDashboardVersionsStub        func(ctx context.Context, orgID int64, uid string, limit int) (grafana.DashboardVersions, error)
+	getDashboardVersionsMutex       sync.RWMutex
+	getDashboardVersionsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+		limit int
+	}
+	getDashboardVersionsReturns struct {
+		result1 grafana.DashboardVersions
+		result2 error
+	}
+	getDashboardVersionsReturnsOnCall map[int]struct {
+		result1 grafana.DashboardVersions
+		result2 error
+	}
+	GetLibraryPanelStub        func(ctx context.Context, orgID int64, uid string) (grafana.LibraryPanel, error)
+	getLibraryPanelMutex       sync.RWMutex
+	getLibraryPanelArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}
+	getLibraryPanelReturns struct {
+		result1 grafana.LibraryPanel
+		result2 error
+	}
+	getLibraryPanelReturnsOnCall map[int]struct {
+		result1 grafana.LibraryPanel
+		result2 error
+	}
+	GetFoldersStub        func(ctx context.Context, orgID int64) ([]grafana.Folder, error)
+	getFoldersMutex       sync.RWMutex
+	getFoldersArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getFoldersReturns struct {
+		result1 []grafana.Folder
+		result2 error
+	}
+	getFoldersReturnsOnCall map[int]struct {
+		result1 []grafana.Folder
+		result2 error
+	}
+	GetFolderPermissionsStub        func(ctx context.Context, orgID int64, uid string) ([]grafana.FolderPermission, error)
+	getFolderPermissionsMutex       sync.RWMutex
+	getFolderPermissionsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}
+	getFolderPermissionsReturns struct {
+		result1 []grafana.FolderPermission
+		result2 error
+	}
+	getFolderPermissionsReturnsOnCall map[int]struct {
+		result1 []grafana.FolderPermission
+		result2 error
+	}
+	GetTeamsStub        func(ctx context.Context, orgID int64) ([]grafana.Team, error)
+	getTeamsMutex       sync.RWMutex
+	getTeamsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getTeamsReturns struct {
+		result1 []grafana.Team
+		result2 error
+	}
+	getTeamsReturnsOnCall map[int]struct {
+		result1 []grafana.Team
+		result2 error
+	}
+	GetDatasourcesStub        func(ctx context.Context, orgID int64) ([]grafana.Datasource, error)
+	getDatasourcesMutex       sync.RWMutex
+	getDatasourcesArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getDatasourcesReturns struct {
+		result1 []grafana.Datasource
+		result2 error
+	}
+	getDatasourcesReturnsOnCall map[int]struct {
+		result1 []grafana.Datasource
+		result2 error
+	}
+	GetDatasourceHealthStub        func(ctx context.Context, orgID int64, uid string) (grafana.DatasourceHealth, error)
+	getDatasourceHealthMutex       sync.RWMutex
+	getDatasourceHealthArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}
+	getDatasourceHealthReturns struct {
+		result1 grafana.DatasourceHealth
+		result2 error
+	}
+	getDatasourceHealthReturnsOnCall map[int]struct {
+		result1 grafana.DatasourceHealth
+		result2 error
+	}
+	GetOrgUsersStub        func(ctx context.Context, orgID int64) ([]grafana.OrgUser, error)
+	getOrgUsersMutex       sync.RWMutex
+	getOrgUsersArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getOrgUsersReturns struct {
+		result1 []grafana.OrgUser
+		result2 error
+	}
+	getOrgUsersReturnsOnCall map[int]struct {
+		result1 []grafana.OrgUser
+		result2 error
+	}
+	GetAlertRulesStub        func(ctx context.Context, orgID int64) ([]grafana.AlertRule, error)
+	getAlertRulesMutex       sync.RWMutex
+	getAlertRulesArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getAlertRulesReturns struct {
+		result1 []grafana.AlertRule
+		result2 error
+	}
+	getAlertRulesReturnsOnCall map[int]struct {
+		result1 []grafana.AlertRule
+		result2 error
+	}
+	GetAlertsStub        func(ctx context.Context, orgID int64) ([]grafana.Alert, error)
+	getAlertsMutex       sync.RWMutex
+	getAlertsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getAlertsReturns struct {
+		result1 []grafana.Alert
+		result2 error
+	}
+	getAlertsReturnsOnCall map[int]struct {
+		result1 []grafana.Alert
+		result2 error
+	}
+	GetRuleGroupsStub        func(ctx context.Context, orgID int64) ([]grafana.RuleGroup, error)
+	getRuleGroupsMutex       sync.RWMutex
+	getRuleGroupsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getRuleGroupsReturns struct {
+		result1 []grafana.RuleGroup
+		result2 error
+	}
+	getRuleGroupsReturnsOnCall map[int]struct {
+		result1 []grafana.RuleGroup
+		result2 error
+	}
+	GetAlertmanagerAlertsStub        func(ctx context.Context, orgID int64) ([]grafana.AlertmanagerAlert, error)
+	getAlertmanagerAlertsMutex       sync.RWMutex
+	getAlertmanagerAlertsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getAlertmanagerAlertsReturns struct {
+		result1 []grafana.AlertmanagerAlert
+		result2 error
+	}
+	getAlertmanagerAlertsReturnsOnCall map[int]struct {
+		result1 []grafana.AlertmanagerAlert
+		result2 error
+	}
+	GetSilencesStub        func(ctx context.Context, orgID int64) ([]grafana.Silence, error)
+	getSilencesMutex       sync.RWMutex
+	getSilencesArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getSilencesReturns struct {
+		result1 []grafana.Silence
+		result2 error
+	}
+	getSilencesReturnsOnCall map[int]struct {
+		result1 []grafana.Silence
+		result2 error
+	}
+	GetNotificationChannelsStub        func(ctx context.Context, orgID int64) ([]grafana.NotificationChannel, error)
+	getNotificationChannelsMutex       sync.RWMutex
+	getNotificationChannelsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getNotificationChannelsReturns struct {
+		result1 []grafana.NotificationChannel
+		result2 error
+	}
+	getNotificationChannelsReturnsOnCall map[int]struct {
+		result1 []grafana.NotificationChannel
+		result2 error
+	}
+	GetContactPointsStub        func(ctx context.Context, orgID int64) ([]grafana.ContactPoint, error)
+	getContactPointsMutex       sync.RWMutex
+	getContactPointsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getContactPointsReturns struct {
+		result1 []grafana.ContactPoint
+		result2 error
+	}
+	getContactPointsReturnsOnCall map[int]struct {
+		result1 []grafana.ContactPoint
+		result2 error
+	}
+	GetReceiversStub        func(ctx context.Context, orgID int64) ([]grafana.Receiver, error)
+	getReceiversMutex       sync.RWMutex
+	getReceiversArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getReceiversReturns struct {
+		result1 []grafana.Receiver
+		result2 error
+	}
+	getReceiversReturnsOnCall map[int]struct {
+		result1 []grafana.Receiver
+		result2 error
+	}
+	GetAPIKeysStub        func(ctx context.Context, orgID int64) ([]grafana.APIKey, error)
+	getAPIKeysMutex       sync.RWMutex
+	getAPIKeysArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getAPIKeysReturns struct {
+		result1 []grafana.APIKey
+		result2 error
+	}
+	getAPIKeysReturnsOnCall map[int]struct {
+		result1 []grafana.APIKey
+		result2 error
+	}
+	GetServiceAccountsStub        func(ctx context.Context, orgID int64) ([]grafana.ServiceAccount, error)
+	getServiceAccountsMutex       sync.RWMutex
+	getServiceAccountsArgsForCall []struct {
+		ctx   context.Context
+		orgID int64
+	}
+	getServiceAccountsReturns struct {
+		result1 []grafana.ServiceAccount
+		result2 error
+	}
+	getServiceAccountsReturnsOnCall map[int]struct {
+		result1 []grafana.ServiceAccount
+		result2 error
+	}
+	GetServiceAccountTokensStub        func(ctx context.Context, orgID int64, serviceAccountID int64) ([]grafana.ServiceAccountToken, error)
+	getServiceAccountTokensMutex       sync.RWMutex
+	getServiceAccountTokensArgsForCall []struct {
+		ctx              context.Context
+		orgID            int64
+		serviceAccountID int64
+	}
+	getServiceAccountTokensReturns struct {
+		result1 []grafana.ServiceAccountToken
+		result2 error
+	}
+	getServiceAccountTokensReturnsOnCall map[int]struct {
+		result1 []grafana.ServiceAccountToken
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeClient) GetAdminStats() (grafana.AdminStats, error) {
+func (fake *FakeClient) GetAdminStats(ctx context.Context) (grafana.AdminStats, error) {
 	fake.getAdminStatsMutex.Lock()
 	ret, specificReturn := fake.getAdminStatsReturnsOnCall[len(fake.getAdminStatsArgsForCall)]
-	fake.getAdminStatsArgsForCall = append(fake.getAdminStatsArgsForCall, struct{}{})
-	fake.recordInvocation("GetAdminStats", []interface{}{})
+	fake.getAdminStatsArgsForCall = append(fake.getAdminStatsArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetAdminStats", []interface{}{ctx})
 	fake.getAdminStatsMutex.Unlock()
 	if fake.GetAdminStatsStub != nil {
-		return fake.GetAdminStatsStub()
+		return fake.GetAdminStatsStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -55,6 +415,12 @@ func (fake *FakeClient) GetAdminStatsCallCount() int {
 	return len(fake.getAdminStatsArgsForCall)
 }
 
+func (fake *FakeClient) GetAdminStatsArgsForCall(i int) context.Context {
+	fake.getAdminStatsMutex.RLock()
+	defer fake.getAdminStatsMutex.RUnlock()
+	return fake.getAdminStatsArgsForCall[i].ctx
+}
+
 func (fake *FakeClient) GetAdminStatsReturns(result1 grafana.AdminStats, result2 error) {
 	fake.GetAdminStatsStub = nil
 	fake.getAdminStatsReturns = struct {
@@ -77,14 +443,16 @@ func (fake *FakeClient) GetAdminStatsReturnsOnCall(i int, result1 grafana.AdminS
 	}{result1, result2}
 }
 
-func (fake *FakeClient) GetMetrics() (grafana.Metrics, error) {
+func (fake *FakeClient) GetMetrics(ctx context.Context) (grafana.Metrics, error) {
 	fake.getMetricsMutex.Lock()
 	ret, specificReturn := fake.getMetricsReturnsOnCall[len(fake.getMetricsArgsForCall)]
-	fake.getMetricsArgsForCall = append(fake.getMetricsArgsForCall, struct{}{})
-	fake.recordInvocation("GetMetrics", []interface{}{})
+	fake.getMetricsArgsForCall = append(fake.getMetricsArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetMetrics", []interface{}{ctx})
 	fake.getMetricsMutex.Unlock()
 	if fake.GetMetricsStub != nil {
-		return fake.GetMetricsStub()
+		return fake.GetMetricsStub(ctx)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -98,6 +466,12 @@ func (fake *FakeClient) GetMetricsCallCount() int {
 	return len(fake.getMetricsArgsForCall)
 }
 
+func (fake *FakeClient) GetMetricsArgsForCall(i int) context.Context {
+	fake.getMetricsMutex.RLock()
+	defer fake.getMetricsMutex.RUnlock()
+	return fake.getMetricsArgsForCall[i].ctx
+}
+
 func (fake *FakeClient) GetMetricsReturns(result1 grafana.Metrics, result2 error) {
 	fake.GetMetricsStub = nil
 	fake.getMetricsReturns = struct {
@@ -120,6 +494,1309 @@ func (fake *FakeClient) GetMetricsReturnsOnCall(i int, result1 grafana.Metrics,
 	}{result1, result2}
 }
 
+func (fake *FakeClient) GetHealth(ctx context.Context) (grafana.Health, error) {
+	fake.getHealthMutex.Lock()
+	ret, specificReturn := fake.getHealthReturnsOnCall[len(fake.getHealthArgsForCall)]
+	fake.getHealthArgsForCall = append(fake.getHealthArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetHealth", []interface{}{ctx})
+	fake.getHealthMutex.Unlock()
+	if fake.GetHealthStub != nil {
+		return fake.GetHealthStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getHealthReturns.result1, fake.getHealthReturns.result2
+}
+
+func (fake *FakeClient) GetHealthCallCount() int {
+	fake.getHealthMutex.RLock()
+	defer fake.getHealthMutex.RUnlock()
+	return len(fake.getHealthArgsForCall)
+}
+
+func (fake *FakeClient) GetHealthArgsForCall(i int) context.Context {
+	fake.getHealthMutex.RLock()
+	defer fake.getHealthMutex.RUnlock()
+	return fake.getHealthArgsForCall[i].ctx
+}
+
+func (fake *FakeClient) GetHealthReturns(result1 grafana.Health, result2 error) {
+	fake.GetHealthStub = nil
+	fake.getHealthReturns = struct {
+		result1 grafana.Health
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetHealthReturnsOnCall(i int, result1 grafana.Health, result2 error) {
+	fake.GetHealthStub = nil
+	if fake.getHealthReturnsOnCall == nil {
+		fake.getHealthReturnsOnCall = make(map[int]struct {
+			result1 grafana.Health
+			result2 error
+		})
+	}
+	fake.getHealthReturnsOnCall[i] = struct {
+		result1 grafana.Health
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFrontendSettings(ctx context.Context) (grafana.FrontendSettings, error) {
+	fake.getFrontendSettingsMutex.Lock()
+	ret, specificReturn := fake.getFrontendSettingsReturnsOnCall[len(fake.getFrontendSettingsArgsForCall)]
+	fake.getFrontendSettingsArgsForCall = append(fake.getFrontendSettingsArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetFrontendSettings", []interface{}{ctx})
+	fake.getFrontendSettingsMutex.Unlock()
+	if fake.GetFrontendSettingsStub != nil {
+		return fake.GetFrontendSettingsStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getFrontendSettingsReturns.result1, fake.getFrontendSettingsReturns.result2
+}
+
+func (fake *FakeClient) GetFrontendSettingsCallCount() int {
+	fake.getFrontendSettingsMutex.RLock()
+	defer fake.getFrontendSettingsMutex.RUnlock()
+	return len(fake.getFrontendSettingsArgsForCall)
+}
+
+func (fake *FakeClient) GetFrontendSettingsArgsForCall(i int) context.Context {
+	fake.getFrontendSettingsMutex.RLock()
+	defer fake.getFrontendSettingsMutex.RUnlock()
+	return fake.getFrontendSettingsArgsForCall[i].ctx
+}
+
+func (fake *FakeClient) GetFrontendSettingsReturns(result1 grafana.FrontendSettings, result2 error) {
+	fake.GetFrontendSettingsStub = nil
+	fake.getFrontendSettingsReturns = struct {
+		result1 grafana.FrontendSettings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFrontendSettingsReturnsOnCall(i int, result1 grafana.FrontendSettings, result2 error) {
+	fake.GetFrontendSettingsStub = nil
+	if fake.getFrontendSettingsReturnsOnCall == nil {
+		fake.getFrontendSettingsReturnsOnCall = make(map[int]struct {
+			result1 grafana.FrontendSettings
+			result2 error
+		})
+	}
+	fake.getFrontendSettingsReturnsOnCall[i] = struct {
+		result1 grafana.FrontendSettings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrgs(ctx context.Context) ([]grafana.Org, error) {
+	fake.getOrgsMutex.Lock()
+	ret, specificReturn := fake.getOrgsReturnsOnCall[len(fake.getOrgsArgsForCall)]
+	fake.getOrgsArgsForCall = append(fake.getOrgsArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetOrgs", []interface{}{ctx})
+	fake.getOrgsMutex.Unlock()
+	if fake.GetOrgsStub != nil {
+		return fake.GetOrgsStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getOrgsReturns.result1, fake.getOrgsReturns.result2
+}
+
+func (fake *FakeClient) GetOrgsCallCount() int {
+	fake.getOrgsMutex.RLock()
+	defer fake.getOrgsMutex.RUnlock()
+	return len(fake.getOrgsArgsForCall)
+}
+
+func (fake *FakeClient) GetOrgsArgsForCall(i int) context.Context {
+	fake.getOrgsMutex.RLock()
+	defer fake.getOrgsMutex.RUnlock()
+	return fake.getOrgsArgsForCall[i].ctx
+}
+
+func (fake *FakeClient) GetOrgsReturns(result1 []grafana.Org, result2 error) {
+	fake.GetOrgsStub = nil
+	fake.getOrgsReturns = struct {
+		result1 []grafana.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrgsReturnsOnCall(i int, result1 []grafana.Org, result2 error) {
+	fake.GetOrgsStub = nil
+	if fake.getOrgsReturnsOnCall == nil {
+		fake.getOrgsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Org
+			result2 error
+		})
+	}
+	fake.getOrgsReturnsOnCall[i] = struct {
+		result1 []grafana.Org
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetUsers(ctx context.Context) ([]grafana.User, error) {
+	fake.getUsersMutex.Lock()
+	ret, specificReturn := fake.getUsersReturnsOnCall[len(fake.getUsersArgsForCall)]
+	fake.getUsersArgsForCall = append(fake.getUsersArgsForCall, struct {
+		ctx context.Context
+	}{ctx})
+	fake.recordInvocation("GetUsers", []interface{}{ctx})
+	fake.getUsersMutex.Unlock()
+	if fake.GetUsersStub != nil {
+		return fake.GetUsersStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getUsersReturns.result1, fake.getUsersReturns.result2
+}
+
+func (fake *FakeClient) GetUsersCallCount() int {
+	fake.getUsersMutex.RLock()
+	defer fake.getUsersMutex.RUnlock()
+	return len(fake.getUsersArgsForCall)
+}
+
+func (fake *FakeClient) GetUsersArgsForCall(i int) context.Context {
+	fake.getUsersMutex.RLock()
+	defer fake.getUsersMutex.RUnlock()
+	return fake.getUsersArgsForCall[i].ctx
+}
+
+func (fake *FakeClient) GetUsersReturns(result1 []grafana.User, result2 error) {
+	fake.GetUsersStub = nil
+	fake.getUsersReturns = struct {
+		result1 []grafana.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetUsersReturnsOnCall(i int, result1 []grafana.User, result2 error) {
+	fake.GetUsersStub = nil
+	if fake.getUsersReturnsOnCall == nil {
+		fake.getUsersReturnsOnCall = make(map[int]struct {
+			result1 []grafana.User
+			result2 error
+		})
+	}
+	fake.getUsersReturnsOnCall[i] = struct {
+		result1 []grafana.User
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDashboards(ctx context.Context, orgID int64) ([]grafana.SearchHit, error) {
+	fake.getDashboardsMutex.Lock()
+	ret, specificReturn := fake.getDashboardsReturnsOnCall[len(fake.getDashboardsArgsForCall)]
+	fake.getDashboardsArgsForCall = append(fake.getDashboardsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetDashboards", []interface{}{ctx, orgID})
+	fake.getDashboardsMutex.Unlock()
+	if fake.GetDashboardsStub != nil {
+		return fake.GetDashboardsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getDashboardsReturns.result1, fake.getDashboardsReturns.result2
+}
+
+func (fake *FakeClient) GetDashboardsCallCount() int {
+	fake.getDashboardsMutex.RLock()
+	defer fake.getDashboardsMutex.RUnlock()
+	return len(fake.getDashboardsArgsForCall)
+}
+
+func (fake *FakeClient) GetDashboardsArgsForCall(i int) (context.Context, int64) {
+	fake.getDashboardsMutex.RLock()
+	defer fake.getDashboardsMutex.RUnlock()
+	return fake.getDashboardsArgsForCall[i].ctx, fake.getDashboardsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetDashboardsReturns(result1 []grafana.SearchHit, result2 error) {
+	fake.GetDashboardsStub = nil
+	fake.getDashboardsReturns = struct {
+		result1 []grafana.SearchHit
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDashboardsReturnsOnCall(i int, result1 []grafana.SearchHit, result2 error) {
+	fake.GetDashboardsStub = nil
+	if fake.getDashboardsReturnsOnCall == nil {
+		fake.getDashboardsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.SearchHit
+			result2 error
+		})
+	}
+	fake.getDashboardsReturnsOnCall[i] = struct {
+		result1 []grafana.SearchHit
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDashboard(ctx context.Context, orgID int64, uid string) (grafana.Dashboard, error) {
+	fake.getDashboardMutex.Lock()
+	ret, specificReturn := fake.getDashboardReturnsOnCall[len(fake.getDashboardArgsForCall)]
+	fake.getDashboardArgsForCall = append(fake.getDashboardArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}{ctx, orgID, uid})
+	fake.recordInvocation("GetDashboard", []interface{}{ctx, orgID, uid})
+	fake.getDashboardMutex.Unlock()
+	if fake.GetDashboardStub != nil {
+		return fake.GetDashboardStub(ctx, orgID, uid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getDashboardReturns.result1, fake.getDashboardReturns.result2
+}
+
+func (fake *FakeClient) GetDashboardCallCount() int {
+	fake.getDashboardMutex.RLock()
+	defer fake.getDashboardMutex.RUnlock()
+	return len(fake.getDashboardArgsForCall)
+}
+
+func (fake *FakeClient) GetDashboardArgsForCall(i int) (context.Context, int64, string) {
+	fake.getDashboardMutex.RLock()
+	defer fake.getDashboardMutex.RUnlock()
+	return fake.getDashboardArgsForCall[i].ctx, fake.getDashboardArgsForCall[i].orgID, fake.getDashboardArgsForCall[i].uid
+}
+
+func (fake *FakeClient) GetDashboardReturns(result1 grafana.Dashboard, result2 error) {
+	fake.GetDashboardStub = nil
+	fake.getDashboardReturns = struct {
+		result1 grafana.Dashboard
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDashboardReturnsOnCall(i int, result1 grafana.Dashboard, result2 error) {
+	fake.GetDashboardStub = nil
+	if fake.getDashboardReturnsOnCall == nil {
+		fake.getDashboardReturnsOnCall = make(map[int]struct {
+			result1 grafana.Dashboard
+			result2 error
+		})
+	}
+	fake.getDashboardReturnsOnCall[i] = struct {
+		result1 grafana.Dashboard
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDashboardVersions(ctx context.Context, orgID int64, uid string, limit int) (grafana.DashboardVersions, error) {
+	fake.getDashboardVersionsMutex.Lock()
+	ret, specificReturn := fake.getDashboardVersionsReturnsOnCall[len(fake.getDashboardVersionsArgsForCall)]
+	fake.getDashboardVersionsArgsForCall = append(fake.getDashboardVersionsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+		limit int
+	}{ctx, orgID, uid, limit})
+	fake.recordInvocation("GetDashboardVersions", []interface{}{ctx, orgID, uid, limit})
+	fake.getDashboardVersionsMutex.Unlock()
+	if fake.GetDashboardVersionsStub != nil {
+		return fake.GetDashboardVersionsStub(ctx, orgID, uid, limit)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getDashboardVersionsReturns.result1, fake.getDashboardVersionsReturns.result2
+}
+
+func (fake *FakeClient) GetDashboardVersionsCallCount() int {
+	fake.getDashboardVersionsMutex.RLock()
+	defer fake.getDashboardVersionsMutex.RUnlock()
+	return len(fake.getDashboardVersionsArgsForCall)
+}
+
+func (fake *FakeClient) GetDashboardVersionsArgsForCall(i int) (context.Context, int64, string, int) {
+	fake.getDashboardVersionsMutex.RLock()
+	defer fake.getDashboardVersionsMutex.RUnlock()
+	return fake.getDashboardVersionsArgsForCall[i].ctx, fake.getDashboardVersionsArgsForCall[i].orgID, fake.getDashboardVersionsArgsForCall[i].uid, fake.getDashboardVersionsArgsForCall[i].limit
+}
+
+func (fake *FakeClient) GetDashboardVersionsReturns(result1 grafana.DashboardVersions, result2 error) {
+	fake.GetDashboardVersionsStub = nil
+	fake.getDashboardVersionsReturns = struct {
+		result1 grafana.DashboardVersions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDashboardVersionsReturnsOnCall(i int, result1 grafana.DashboardVersions, result2 error) {
+	fake.GetDashboardVersionsStub = nil
+	if fake.getDashboardVersionsReturnsOnCall == nil {
+		fake.getDashboardVersionsReturnsOnCall = make(map[int]struct {
+			result1 grafana.DashboardVersions
+			result2 error
+		})
+	}
+	fake.getDashboardVersionsReturnsOnCall[i] = struct {
+		result1 grafana.DashboardVersions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetLibraryPanel(ctx context.Context, orgID int64, uid string) (grafana.LibraryPanel, error) {
+	fake.getLibraryPanelMutex.Lock()
+	ret, specificReturn := fake.getLibraryPanelReturnsOnCall[len(fake.getLibraryPanelArgsForCall)]
+	fake.getLibraryPanelArgsForCall = append(fake.getLibraryPanelArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}{ctx, orgID, uid})
+	fake.recordInvocation("GetLibraryPanel", []interface{}{ctx, orgID, uid})
+	fake.getLibraryPanelMutex.Unlock()
+	if fake.GetLibraryPanelStub != nil {
+		return fake.GetLibraryPanelStub(ctx, orgID, uid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getLibraryPanelReturns.result1, fake.getLibraryPanelReturns.result2
+}
+
+func (fake *FakeClient) GetLibraryPanelCallCount() int {
+	fake.getLibraryPanelMutex.RLock()
+	defer fake.getLibraryPanelMutex.RUnlock()
+	return len(fake.getLibraryPanelArgsForCall)
+}
+
+func (fake *FakeClient) GetLibraryPanelArgsForCall(i int) (context.Context, int64, string) {
+	fake.getLibraryPanelMutex.RLock()
+	defer fake.getLibraryPanelMutex.RUnlock()
+	return fake.getLibraryPanelArgsForCall[i].ctx, fake.getLibraryPanelArgsForCall[i].orgID, fake.getLibraryPanelArgsForCall[i].uid
+}
+
+func (fake *FakeClient) GetLibraryPanelReturns(result1 grafana.LibraryPanel, result2 error) {
+	fake.GetLibraryPanelStub = nil
+	fake.getLibraryPanelReturns = struct {
+		result1 grafana.LibraryPanel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetLibraryPanelReturnsOnCall(i int, result1 grafana.LibraryPanel, result2 error) {
+	fake.GetLibraryPanelStub = nil
+	if fake.getLibraryPanelReturnsOnCall == nil {
+		fake.getLibraryPanelReturnsOnCall = make(map[int]struct {
+			result1 grafana.LibraryPanel
+			result2 error
+		})
+	}
+	fake.getLibraryPanelReturnsOnCall[i] = struct {
+		result1 grafana.LibraryPanel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFolders(ctx context.Context, orgID int64) ([]grafana.Folder, error) {
+	fake.getFoldersMutex.Lock()
+	ret, specificReturn := fake.getFoldersReturnsOnCall[len(fake.getFoldersArgsForCall)]
+	fake.getFoldersArgsForCall = append(fake.getFoldersArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetFolders", []interface{}{ctx, orgID})
+	fake.getFoldersMutex.Unlock()
+	if fake.GetFoldersStub != nil {
+		return fake.GetFoldersStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getFoldersReturns.result1, fake.getFoldersReturns.result2
+}
+
+func (fake *FakeClient) GetFoldersCallCount() int {
+	fake.getFoldersMutex.RLock()
+	defer fake.getFoldersMutex.RUnlock()
+	return len(fake.getFoldersArgsForCall)
+}
+
+func (fake *FakeClient) GetFoldersArgsForCall(i int) (context.Context, int64) {
+	fake.getFoldersMutex.RLock()
+	defer fake.getFoldersMutex.RUnlock()
+	return fake.getFoldersArgsForCall[i].ctx, fake.getFoldersArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetFoldersReturns(result1 []grafana.Folder, result2 error) {
+	fake.GetFoldersStub = nil
+	fake.getFoldersReturns = struct {
+		result1 []grafana.Folder
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFoldersReturnsOnCall(i int, result1 []grafana.Folder, result2 error) {
+	fake.GetFoldersStub = nil
+	if fake.getFoldersReturnsOnCall == nil {
+		fake.getFoldersReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Folder
+			result2 error
+		})
+	}
+	fake.getFoldersReturnsOnCall[i] = struct {
+		result1 []grafana.Folder
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFolderPermissions(ctx context.Context, orgID int64, uid string) ([]grafana.FolderPermission, error) {
+	fake.getFolderPermissionsMutex.Lock()
+	ret, specificReturn := fake.getFolderPermissionsReturnsOnCall[len(fake.getFolderPermissionsArgsForCall)]
+	fake.getFolderPermissionsArgsForCall = append(fake.getFolderPermissionsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}{ctx, orgID, uid})
+	fake.recordInvocation("GetFolderPermissions", []interface{}{ctx, orgID, uid})
+	fake.getFolderPermissionsMutex.Unlock()
+	if fake.GetFolderPermissionsStub != nil {
+		return fake.GetFolderPermissionsStub(ctx, orgID, uid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getFolderPermissionsReturns.result1, fake.getFolderPermissionsReturns.result2
+}
+
+func (fake *FakeClient) GetFolderPermissionsCallCount() int {
+	fake.getFolderPermissionsMutex.RLock()
+	defer fake.getFolderPermissionsMutex.RUnlock()
+	return len(fake.getFolderPermissionsArgsForCall)
+}
+
+func (fake *FakeClient) GetFolderPermissionsArgsForCall(i int) (context.Context, int64, string) {
+	fake.getFolderPermissionsMutex.RLock()
+	defer fake.getFolderPermissionsMutex.RUnlock()
+	return fake.getFolderPermissionsArgsForCall[i].ctx, fake.getFolderPermissionsArgsForCall[i].orgID, fake.getFolderPermissionsArgsForCall[i].uid
+}
+
+func (fake *FakeClient) GetFolderPermissionsReturns(result1 []grafana.FolderPermission, result2 error) {
+	fake.GetFolderPermissionsStub = nil
+	fake.getFolderPermissionsReturns = struct {
+		result1 []grafana.FolderPermission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetFolderPermissionsReturnsOnCall(i int, result1 []grafana.FolderPermission, result2 error) {
+	fake.GetFolderPermissionsStub = nil
+	if fake.getFolderPermissionsReturnsOnCall == nil {
+		fake.getFolderPermissionsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.FolderPermission
+			result2 error
+		})
+	}
+	fake.getFolderPermissionsReturnsOnCall[i] = struct {
+		result1 []grafana.FolderPermission
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetTeams(ctx context.Context, orgID int64) ([]grafana.Team, error) {
+	fake.getTeamsMutex.Lock()
+	ret, specificReturn := fake.getTeamsReturnsOnCall[len(fake.getTeamsArgsForCall)]
+	fake.getTeamsArgsForCall = append(fake.getTeamsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetTeams", []interface{}{ctx, orgID})
+	fake.getTeamsMutex.Unlock()
+	if fake.GetTeamsStub != nil {
+		return fake.GetTeamsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getTeamsReturns.result1, fake.getTeamsReturns.result2
+}
+
+func (fake *FakeClient) GetTeamsCallCount() int {
+	fake.getTeamsMutex.RLock()
+	defer fake.getTeamsMutex.RUnlock()
+	return len(fake.getTeamsArgsForCall)
+}
+
+func (fake *FakeClient) GetTeamsArgsForCall(i int) (context.Context, int64) {
+	fake.getTeamsMutex.RLock()
+	defer fake.getTeamsMutex.RUnlock()
+	return fake.getTeamsArgsForCall[i].ctx, fake.getTeamsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetTeamsReturns(result1 []grafana.Team, result2 error) {
+	fake.GetTeamsStub = nil
+	fake.getTeamsReturns = struct {
+		result1 []grafana.Team
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetTeamsReturnsOnCall(i int, result1 []grafana.Team, result2 error) {
+	fake.GetTeamsStub = nil
+	if fake.getTeamsReturnsOnCall == nil {
+		fake.getTeamsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Team
+			result2 error
+		})
+	}
+	fake.getTeamsReturnsOnCall[i] = struct {
+		result1 []grafana.Team
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDatasources(ctx context.Context, orgID int64) ([]grafana.Datasource, error) {
+	fake.getDatasourcesMutex.Lock()
+	ret, specificReturn := fake.getDatasourcesReturnsOnCall[len(fake.getDatasourcesArgsForCall)]
+	fake.getDatasourcesArgsForCall = append(fake.getDatasourcesArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetDatasources", []interface{}{ctx, orgID})
+	fake.getDatasourcesMutex.Unlock()
+	if fake.GetDatasourcesStub != nil {
+		return fake.GetDatasourcesStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getDatasourcesReturns.result1, fake.getDatasourcesReturns.result2
+}
+
+func (fake *FakeClient) GetDatasourcesCallCount() int {
+	fake.getDatasourcesMutex.RLock()
+	defer fake.getDatasourcesMutex.RUnlock()
+	return len(fake.getDatasourcesArgsForCall)
+}
+
+func (fake *FakeClient) GetDatasourcesArgsForCall(i int) (context.Context, int64) {
+	fake.getDatasourcesMutex.RLock()
+	defer fake.getDatasourcesMutex.RUnlock()
+	return fake.getDatasourcesArgsForCall[i].ctx, fake.getDatasourcesArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetDatasourcesReturns(result1 []grafana.Datasource, result2 error) {
+	fake.GetDatasourcesStub = nil
+	fake.getDatasourcesReturns = struct {
+		result1 []grafana.Datasource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDatasourcesReturnsOnCall(i int, result1 []grafana.Datasource, result2 error) {
+	fake.GetDatasourcesStub = nil
+	if fake.getDatasourcesReturnsOnCall == nil {
+		fake.getDatasourcesReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Datasource
+			result2 error
+		})
+	}
+	fake.getDatasourcesReturnsOnCall[i] = struct {
+		result1 []grafana.Datasource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDatasourceHealth(ctx context.Context, orgID int64, uid string) (grafana.DatasourceHealth, error) {
+	fake.getDatasourceHealthMutex.Lock()
+	ret, specificReturn := fake.getDatasourceHealthReturnsOnCall[len(fake.getDatasourceHealthArgsForCall)]
+	fake.getDatasourceHealthArgsForCall = append(fake.getDatasourceHealthArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+		uid   string
+	}{ctx, orgID, uid})
+	fake.recordInvocation("GetDatasourceHealth", []interface{}{ctx, orgID, uid})
+	fake.getDatasourceHealthMutex.Unlock()
+	if fake.GetDatasourceHealthStub != nil {
+		return fake.GetDatasourceHealthStub(ctx, orgID, uid)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getDatasourceHealthReturns.result1, fake.getDatasourceHealthReturns.result2
+}
+
+func (fake *FakeClient) GetDatasourceHealthCallCount() int {
+	fake.getDatasourceHealthMutex.RLock()
+	defer fake.getDatasourceHealthMutex.RUnlock()
+	return len(fake.getDatasourceHealthArgsForCall)
+}
+
+func (fake *FakeClient) GetDatasourceHealthArgsForCall(i int) (context.Context, int64, string) {
+	fake.getDatasourceHealthMutex.RLock()
+	defer fake.getDatasourceHealthMutex.RUnlock()
+	return fake.getDatasourceHealthArgsForCall[i].ctx, fake.getDatasourceHealthArgsForCall[i].orgID, fake.getDatasourceHealthArgsForCall[i].uid
+}
+
+func (fake *FakeClient) GetDatasourceHealthReturns(result1 grafana.DatasourceHealth, result2 error) {
+	fake.GetDatasourceHealthStub = nil
+	fake.getDatasourceHealthReturns = struct {
+		result1 grafana.DatasourceHealth
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetDatasourceHealthReturnsOnCall(i int, result1 grafana.DatasourceHealth, result2 error) {
+	fake.GetDatasourceHealthStub = nil
+	if fake.getDatasourceHealthReturnsOnCall == nil {
+		fake.getDatasourceHealthReturnsOnCall = make(map[int]struct {
+			result1 grafana.DatasourceHealth
+			result2 error
+		})
+	}
+	fake.getDatasourceHealthReturnsOnCall[i] = struct {
+		result1 grafana.DatasourceHealth
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrgUsers(ctx context.Context, orgID int64) ([]grafana.OrgUser, error) {
+	fake.getOrgUsersMutex.Lock()
+	ret, specificReturn := fake.getOrgUsersReturnsOnCall[len(fake.getOrgUsersArgsForCall)]
+	fake.getOrgUsersArgsForCall = append(fake.getOrgUsersArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetOrgUsers", []interface{}{ctx, orgID})
+	fake.getOrgUsersMutex.Unlock()
+	if fake.GetOrgUsersStub != nil {
+		return fake.GetOrgUsersStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getOrgUsersReturns.result1, fake.getOrgUsersReturns.result2
+}
+
+func (fake *FakeClient) GetOrgUsersCallCount() int {
+	fake.getOrgUsersMutex.RLock()
+	defer fake.getOrgUsersMutex.RUnlock()
+	return len(fake.getOrgUsersArgsForCall)
+}
+
+func (fake *FakeClient) GetOrgUsersArgsForCall(i int) (context.Context, int64) {
+	fake.getOrgUsersMutex.RLock()
+	defer fake.getOrgUsersMutex.RUnlock()
+	return fake.getOrgUsersArgsForCall[i].ctx, fake.getOrgUsersArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetOrgUsersReturns(result1 []grafana.OrgUser, result2 error) {
+	fake.GetOrgUsersStub = nil
+	fake.getOrgUsersReturns = struct {
+		result1 []grafana.OrgUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetOrgUsersReturnsOnCall(i int, result1 []grafana.OrgUser, result2 error) {
+	fake.GetOrgUsersStub = nil
+	if fake.getOrgUsersReturnsOnCall == nil {
+		fake.getOrgUsersReturnsOnCall = make(map[int]struct {
+			result1 []grafana.OrgUser
+			result2 error
+		})
+	}
+	fake.getOrgUsersReturnsOnCall[i] = struct {
+		result1 []grafana.OrgUser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAlertRules(ctx context.Context, orgID int64) ([]grafana.AlertRule, error) {
+	fake.getAlertRulesMutex.Lock()
+	ret, specificReturn := fake.getAlertRulesReturnsOnCall[len(fake.getAlertRulesArgsForCall)]
+	fake.getAlertRulesArgsForCall = append(fake.getAlertRulesArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetAlertRules", []interface{}{ctx, orgID})
+	fake.getAlertRulesMutex.Unlock()
+	if fake.GetAlertRulesStub != nil {
+		return fake.GetAlertRulesStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAlertRulesReturns.result1, fake.getAlertRulesReturns.result2
+}
+
+func (fake *FakeClient) GetAlertRulesCallCount() int {
+	fake.getAlertRulesMutex.RLock()
+	defer fake.getAlertRulesMutex.RUnlock()
+	return len(fake.getAlertRulesArgsForCall)
+}
+
+func (fake *FakeClient) GetAlertRulesArgsForCall(i int) (context.Context, int64) {
+	fake.getAlertRulesMutex.RLock()
+	defer fake.getAlertRulesMutex.RUnlock()
+	return fake.getAlertRulesArgsForCall[i].ctx, fake.getAlertRulesArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetAlertRulesReturns(result1 []grafana.AlertRule, result2 error) {
+	fake.GetAlertRulesStub = nil
+	fake.getAlertRulesReturns = struct {
+		result1 []grafana.AlertRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAlertRulesReturnsOnCall(i int, result1 []grafana.AlertRule, result2 error) {
+	fake.GetAlertRulesStub = nil
+	if fake.getAlertRulesReturnsOnCall == nil {
+		fake.getAlertRulesReturnsOnCall = make(map[int]struct {
+			result1 []grafana.AlertRule
+			result2 error
+		})
+	}
+	fake.getAlertRulesReturnsOnCall[i] = struct {
+		result1 []grafana.AlertRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAlerts(ctx context.Context, orgID int64) ([]grafana.Alert, error) {
+	fake.getAlertsMutex.Lock()
+	ret, specificReturn := fake.getAlertsReturnsOnCall[len(fake.getAlertsArgsForCall)]
+	fake.getAlertsArgsForCall = append(fake.getAlertsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetAlerts", []interface{}{ctx, orgID})
+	fake.getAlertsMutex.Unlock()
+	if fake.GetAlertsStub != nil {
+		return fake.GetAlertsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAlertsReturns.result1, fake.getAlertsReturns.result2
+}
+
+func (fake *FakeClient) GetAlertsCallCount() int {
+	fake.getAlertsMutex.RLock()
+	defer fake.getAlertsMutex.RUnlock()
+	return len(fake.getAlertsArgsForCall)
+}
+
+func (fake *FakeClient) GetAlertsArgsForCall(i int) (context.Context, int64) {
+	fake.getAlertsMutex.RLock()
+	defer fake.getAlertsMutex.RUnlock()
+	return fake.getAlertsArgsForCall[i].ctx, fake.getAlertsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetAlertsReturns(result1 []grafana.Alert, result2 error) {
+	fake.GetAlertsStub = nil
+	fake.getAlertsReturns = struct {
+		result1 []grafana.Alert
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAlertsReturnsOnCall(i int, result1 []grafana.Alert, result2 error) {
+	fake.GetAlertsStub = nil
+	if fake.getAlertsReturnsOnCall == nil {
+		fake.getAlertsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Alert
+			result2 error
+		})
+	}
+	fake.getAlertsReturnsOnCall[i] = struct {
+		result1 []grafana.Alert
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetRuleGroups(ctx context.Context, orgID int64) ([]grafana.RuleGroup, error) {
+	fake.getRuleGroupsMutex.Lock()
+	ret, specificReturn := fake.getRuleGroupsReturnsOnCall[len(fake.getRuleGroupsArgsForCall)]
+	fake.getRuleGroupsArgsForCall = append(fake.getRuleGroupsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetRuleGroups", []interface{}{ctx, orgID})
+	fake.getRuleGroupsMutex.Unlock()
+	if fake.GetRuleGroupsStub != nil {
+		return fake.GetRuleGroupsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getRuleGroupsReturns.result1, fake.getRuleGroupsReturns.result2
+}
+
+func (fake *FakeClient) GetRuleGroupsCallCount() int {
+	fake.getRuleGroupsMutex.RLock()
+	defer fake.getRuleGroupsMutex.RUnlock()
+	return len(fake.getRuleGroupsArgsForCall)
+}
+
+func (fake *FakeClient) GetRuleGroupsArgsForCall(i int) (context.Context, int64) {
+	fake.getRuleGroupsMutex.RLock()
+	defer fake.getRuleGroupsMutex.RUnlock()
+	return fake.getRuleGroupsArgsForCall[i].ctx, fake.getRuleGroupsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetRuleGroupsReturns(result1 []grafana.RuleGroup, result2 error) {
+	fake.GetRuleGroupsStub = nil
+	fake.getRuleGroupsReturns = struct {
+		result1 []grafana.RuleGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetRuleGroupsReturnsOnCall(i int, result1 []grafana.RuleGroup, result2 error) {
+	fake.GetRuleGroupsStub = nil
+	if fake.getRuleGroupsReturnsOnCall == nil {
+		fake.getRuleGroupsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.RuleGroup
+			result2 error
+		})
+	}
+	fake.getRuleGroupsReturnsOnCall[i] = struct {
+		result1 []grafana.RuleGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAlertmanagerAlerts(ctx context.Context, orgID int64) ([]grafana.AlertmanagerAlert, error) {
+	fake.getAlertmanagerAlertsMutex.Lock()
+	ret, specificReturn := fake.getAlertmanagerAlertsReturnsOnCall[len(fake.getAlertmanagerAlertsArgsForCall)]
+	fake.getAlertmanagerAlertsArgsForCall = append(fake.getAlertmanagerAlertsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetAlertmanagerAlerts", []interface{}{ctx, orgID})
+	fake.getAlertmanagerAlertsMutex.Unlock()
+	if fake.GetAlertmanagerAlertsStub != nil {
+		return fake.GetAlertmanagerAlertsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAlertmanagerAlertsReturns.result1, fake.getAlertmanagerAlertsReturns.result2
+}
+
+func (fake *FakeClient) GetAlertmanagerAlertsCallCount() int {
+	fake.getAlertmanagerAlertsMutex.RLock()
+	defer fake.getAlertmanagerAlertsMutex.RUnlock()
+	return len(fake.getAlertmanagerAlertsArgsForCall)
+}
+
+func (fake *FakeClient) GetAlertmanagerAlertsArgsForCall(i int) (context.Context, int64) {
+	fake.getAlertmanagerAlertsMutex.RLock()
+	defer fake.getAlertmanagerAlertsMutex.RUnlock()
+	return fake.getAlertmanagerAlertsArgsForCall[i].ctx, fake.getAlertmanagerAlertsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetAlertmanagerAlertsReturns(result1 []grafana.AlertmanagerAlert, result2 error) {
+	fake.GetAlertmanagerAlertsStub = nil
+	fake.getAlertmanagerAlertsReturns = struct {
+		result1 []grafana.AlertmanagerAlert
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAlertmanagerAlertsReturnsOnCall(i int, result1 []grafana.AlertmanagerAlert, result2 error) {
+	fake.GetAlertmanagerAlertsStub = nil
+	if fake.getAlertmanagerAlertsReturnsOnCall == nil {
+		fake.getAlertmanagerAlertsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.AlertmanagerAlert
+			result2 error
+		})
+	}
+	fake.getAlertmanagerAlertsReturnsOnCall[i] = struct {
+		result1 []grafana.AlertmanagerAlert
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSilences(ctx context.Context, orgID int64) ([]grafana.Silence, error) {
+	fake.getSilencesMutex.Lock()
+	ret, specificReturn := fake.getSilencesReturnsOnCall[len(fake.getSilencesArgsForCall)]
+	fake.getSilencesArgsForCall = append(fake.getSilencesArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetSilences", []interface{}{ctx, orgID})
+	fake.getSilencesMutex.Unlock()
+	if fake.GetSilencesStub != nil {
+		return fake.GetSilencesStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getSilencesReturns.result1, fake.getSilencesReturns.result2
+}
+
+func (fake *FakeClient) GetSilencesCallCount() int {
+	fake.getSilencesMutex.RLock()
+	defer fake.getSilencesMutex.RUnlock()
+	return len(fake.getSilencesArgsForCall)
+}
+
+func (fake *FakeClient) GetSilencesArgsForCall(i int) (context.Context, int64) {
+	fake.getSilencesMutex.RLock()
+	defer fake.getSilencesMutex.RUnlock()
+	return fake.getSilencesArgsForCall[i].ctx, fake.getSilencesArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetSilencesReturns(result1 []grafana.Silence, result2 error) {
+	fake.GetSilencesStub = nil
+	fake.getSilencesReturns = struct {
+		result1 []grafana.Silence
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetSilencesReturnsOnCall(i int, result1 []grafana.Silence, result2 error) {
+	fake.GetSilencesStub = nil
+	if fake.getSilencesReturnsOnCall == nil {
+		fake.getSilencesReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Silence
+			result2 error
+		})
+	}
+	fake.getSilencesReturnsOnCall[i] = struct {
+		result1 []grafana.Silence
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetNotificationChannels(ctx context.Context, orgID int64) ([]grafana.NotificationChannel, error) {
+	fake.getNotificationChannelsMutex.Lock()
+	ret, specificReturn := fake.getNotificationChannelsReturnsOnCall[len(fake.getNotificationChannelsArgsForCall)]
+	fake.getNotificationChannelsArgsForCall = append(fake.getNotificationChannelsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetNotificationChannels", []interface{}{ctx, orgID})
+	fake.getNotificationChannelsMutex.Unlock()
+	if fake.GetNotificationChannelsStub != nil {
+		return fake.GetNotificationChannelsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getNotificationChannelsReturns.result1, fake.getNotificationChannelsReturns.result2
+}
+
+func (fake *FakeClient) GetNotificationChannelsCallCount() int {
+	fake.getNotificationChannelsMutex.RLock()
+	defer fake.getNotificationChannelsMutex.RUnlock()
+	return len(fake.getNotificationChannelsArgsForCall)
+}
+
+func (fake *FakeClient) GetNotificationChannelsArgsForCall(i int) (context.Context, int64) {
+	fake.getNotificationChannelsMutex.RLock()
+	defer fake.getNotificationChannelsMutex.RUnlock()
+	return fake.getNotificationChannelsArgsForCall[i].ctx, fake.getNotificationChannelsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetNotificationChannelsReturns(result1 []grafana.NotificationChannel, result2 error) {
+	fake.GetNotificationChannelsStub = nil
+	fake.getNotificationChannelsReturns = struct {
+		result1 []grafana.NotificationChannel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetNotificationChannelsReturnsOnCall(i int, result1 []grafana.NotificationChannel, result2 error) {
+	fake.GetNotificationChannelsStub = nil
+	if fake.getNotificationChannelsReturnsOnCall == nil {
+		fake.getNotificationChannelsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.NotificationChannel
+			result2 error
+		})
+	}
+	fake.getNotificationChannelsReturnsOnCall[i] = struct {
+		result1 []grafana.NotificationChannel
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetContactPoints(ctx context.Context, orgID int64) ([]grafana.ContactPoint, error) {
+	fake.getContactPointsMutex.Lock()
+	ret, specificReturn := fake.getContactPointsReturnsOnCall[len(fake.getContactPointsArgsForCall)]
+	fake.getContactPointsArgsForCall = append(fake.getContactPointsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetContactPoints", []interface{}{ctx, orgID})
+	fake.getContactPointsMutex.Unlock()
+	if fake.GetContactPointsStub != nil {
+		return fake.GetContactPointsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getContactPointsReturns.result1, fake.getContactPointsReturns.result2
+}
+
+func (fake *FakeClient) GetContactPointsCallCount() int {
+	fake.getContactPointsMutex.RLock()
+	defer fake.getContactPointsMutex.RUnlock()
+	return len(fake.getContactPointsArgsForCall)
+}
+
+func (fake *FakeClient) GetContactPointsArgsForCall(i int) (context.Context, int64) {
+	fake.getContactPointsMutex.RLock()
+	defer fake.getContactPointsMutex.RUnlock()
+	return fake.getContactPointsArgsForCall[i].ctx, fake.getContactPointsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetContactPointsReturns(result1 []grafana.ContactPoint, result2 error) {
+	fake.GetContactPointsStub = nil
+	fake.getContactPointsReturns = struct {
+		result1 []grafana.ContactPoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetContactPointsReturnsOnCall(i int, result1 []grafana.ContactPoint, result2 error) {
+	fake.GetContactPointsStub = nil
+	if fake.getContactPointsReturnsOnCall == nil {
+		fake.getContactPointsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.ContactPoint
+			result2 error
+		})
+	}
+	fake.getContactPointsReturnsOnCall[i] = struct {
+		result1 []grafana.ContactPoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetReceivers(ctx context.Context, orgID int64) ([]grafana.Receiver, error) {
+	fake.getReceiversMutex.Lock()
+	ret, specificReturn := fake.getReceiversReturnsOnCall[len(fake.getReceiversArgsForCall)]
+	fake.getReceiversArgsForCall = append(fake.getReceiversArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetReceivers", []interface{}{ctx, orgID})
+	fake.getReceiversMutex.Unlock()
+	if fake.GetReceiversStub != nil {
+		return fake.GetReceiversStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getReceiversReturns.result1, fake.getReceiversReturns.result2
+}
+
+func (fake *FakeClient) GetReceiversCallCount() int {
+	fake.getReceiversMutex.RLock()
+	defer fake.getReceiversMutex.RUnlock()
+	return len(fake.getReceiversArgsForCall)
+}
+
+func (fake *FakeClient) GetReceiversArgsForCall(i int) (context.Context, int64) {
+	fake.getReceiversMutex.RLock()
+	defer fake.getReceiversMutex.RUnlock()
+	return fake.getReceiversArgsForCall[i].ctx, fake.getReceiversArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetReceiversReturns(result1 []grafana.Receiver, result2 error) {
+	fake.GetReceiversStub = nil
+	fake.getReceiversReturns = struct {
+		result1 []grafana.Receiver
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetReceiversReturnsOnCall(i int, result1 []grafana.Receiver, result2 error) {
+	fake.GetReceiversStub = nil
+	if fake.getReceiversReturnsOnCall == nil {
+		fake.getReceiversReturnsOnCall = make(map[int]struct {
+			result1 []grafana.Receiver
+			result2 error
+		})
+	}
+	fake.getReceiversReturnsOnCall[i] = struct {
+		result1 []grafana.Receiver
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAPIKeys(ctx context.Context, orgID int64) ([]grafana.APIKey, error) {
+	fake.getAPIKeysMutex.Lock()
+	ret, specificReturn := fake.getAPIKeysReturnsOnCall[len(fake.getAPIKeysArgsForCall)]
+	fake.getAPIKeysArgsForCall = append(fake.getAPIKeysArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetAPIKeys", []interface{}{ctx, orgID})
+	fake.getAPIKeysMutex.Unlock()
+	if fake.GetAPIKeysStub != nil {
+		return fake.GetAPIKeysStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getAPIKeysReturns.result1, fake.getAPIKeysReturns.result2
+}
+
+func (fake *FakeClient) GetAPIKeysCallCount() int {
+	fake.getAPIKeysMutex.RLock()
+	defer fake.getAPIKeysMutex.RUnlock()
+	return len(fake.getAPIKeysArgsForCall)
+}
+
+func (fake *FakeClient) GetAPIKeysArgsForCall(i int) (context.Context, int64) {
+	fake.getAPIKeysMutex.RLock()
+	defer fake.getAPIKeysMutex.RUnlock()
+	return fake.getAPIKeysArgsForCall[i].ctx, fake.getAPIKeysArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetAPIKeysReturns(result1 []grafana.APIKey, result2 error) {
+	fake.GetAPIKeysStub = nil
+	fake.getAPIKeysReturns = struct {
+		result1 []grafana.APIKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAPIKeysReturnsOnCall(i int, result1 []grafana.APIKey, result2 error) {
+	fake.GetAPIKeysStub = nil
+	if fake.getAPIKeysReturnsOnCall == nil {
+		fake.getAPIKeysReturnsOnCall = make(map[int]struct {
+			result1 []grafana.APIKey
+			result2 error
+		})
+	}
+	fake.getAPIKeysReturnsOnCall[i] = struct {
+		result1 []grafana.APIKey
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceAccounts(ctx context.Context, orgID int64) ([]grafana.ServiceAccount, error) {
+	fake.getServiceAccountsMutex.Lock()
+	ret, specificReturn := fake.getServiceAccountsReturnsOnCall[len(fake.getServiceAccountsArgsForCall)]
+	fake.getServiceAccountsArgsForCall = append(fake.getServiceAccountsArgsForCall, struct {
+		ctx   context.Context
+		orgID int64
+	}{ctx, orgID})
+	fake.recordInvocation("GetServiceAccounts", []interface{}{ctx, orgID})
+	fake.getServiceAccountsMutex.Unlock()
+	if fake.GetServiceAccountsStub != nil {
+		return fake.GetServiceAccountsStub(ctx, orgID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceAccountsReturns.result1, fake.getServiceAccountsReturns.result2
+}
+
+func (fake *FakeClient) GetServiceAccountsCallCount() int {
+	fake.getServiceAccountsMutex.RLock()
+	defer fake.getServiceAccountsMutex.RUnlock()
+	return len(fake.getServiceAccountsArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceAccountsArgsForCall(i int) (context.Context, int64) {
+	fake.getServiceAccountsMutex.RLock()
+	defer fake.getServiceAccountsMutex.RUnlock()
+	return fake.getServiceAccountsArgsForCall[i].ctx, fake.getServiceAccountsArgsForCall[i].orgID
+}
+
+func (fake *FakeClient) GetServiceAccountsReturns(result1 []grafana.ServiceAccount, result2 error) {
+	fake.GetServiceAccountsStub = nil
+	fake.getServiceAccountsReturns = struct {
+		result1 []grafana.ServiceAccount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceAccountsReturnsOnCall(i int, result1 []grafana.ServiceAccount, result2 error) {
+	fake.GetServiceAccountsStub = nil
+	if fake.getServiceAccountsReturnsOnCall == nil {
+		fake.getServiceAccountsReturnsOnCall = make(map[int]struct {
+			result1 []grafana.ServiceAccount
+			result2 error
+		})
+	}
+	fake.getServiceAccountsReturnsOnCall[i] = struct {
+		result1 []grafana.ServiceAccount
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceAccountTokens(ctx context.Context, orgID int64, serviceAccountID int64) ([]grafana.ServiceAccountToken, error) {
+	fake.getServiceAccountTokensMutex.Lock()
+	ret, specificReturn := fake.getServiceAccountTokensReturnsOnCall[len(fake.getServiceAccountTokensArgsForCall)]
+	fake.getServiceAccountTokensArgsForCall = append(fake.getServiceAccountTokensArgsForCall, struct {
+		ctx              context.Context
+		orgID            int64
+		serviceAccountID int64
+	}{ctx, orgID, serviceAccountID})
+	fake.recordInvocation("GetServiceAccountTokens", []interface{}{ctx, orgID, serviceAccountID})
+	fake.getServiceAccountTokensMutex.Unlock()
+	if fake.GetServiceAccountTokensStub != nil {
+		return fake.GetServiceAccountTokensStub(ctx, orgID, serviceAccountID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getServiceAccountTokensReturns.result1, fake.getServiceAccountTokensReturns.result2
+}
+
+func (fake *FakeClient) GetServiceAccountTokensCallCount() int {
+	fake.getServiceAccountTokensMutex.RLock()
+	defer fake.getServiceAccountTokensMutex.RUnlock()
+	return len(fake.getServiceAccountTokensArgsForCall)
+}
+
+func (fake *FakeClient) GetServiceAccountTokensArgsForCall(i int) (context.Context, int64, int64) {
+	fake.getServiceAccountTokensMutex.RLock()
+	defer fake.getServiceAccountTokensMutex.RUnlock()
+	return fake.getServiceAccountTokensArgsForCall[i].ctx, fake.getServiceAccountTokensArgsForCall[i].orgID, fake.getServiceAccountTokensArgsForCall[i].serviceAccountID
+}
+
+func (fake *FakeClient) GetServiceAccountTokensReturns(result1 []grafana.ServiceAccountToken, result2 error) {
+	fake.GetServiceAccountTokensStub = nil
+	fake.getServiceAccountTokensReturns = struct {
+		result1 []grafana.ServiceAccountToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetServiceAccountTokensReturnsOnCall(i int, result1 []grafana.ServiceAccountToken, result2 error) {
+	fake.GetServiceAccountTokensStub = nil
+	if fake.getServiceAccountTokensReturnsOnCall == nil {
+		fake.getServiceAccountTokensReturnsOnCall = make(map[int]struct {
+			result1 []grafana.ServiceAccountToken
+			result2 error
+		})
+	}
+	fake.getServiceAccountTokensReturnsOnCall[i] = struct {
+		result1 []grafana.ServiceAccountToken
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -127,6 +1804,56 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.getAdminStatsMutex.RUnlock()
 	fake.getMetricsMutex.RLock()
 	defer fake.getMetricsMutex.RUnlock()
+	fake.getHealthMutex.RLock()
+	defer fake.getHealthMutex.RUnlock()
+	fake.getFrontendSettingsMutex.RLock()
+	defer fake.getFrontendSettingsMutex.RUnlock()
+	fake.getOrgsMutex.RLock()
+	defer fake.getOrgsMutex.RUnlock()
+	fake.getUsersMutex.RLock()
+	defer fake.getUsersMutex.RUnlock()
+	fake.getDashboardsMutex.RLock()
+	defer fake.getDashboardsMutex.RUnlock()
+	fake.getDashboardMutex.RLock()
+	defer fake.getDashboardMutex.RUnlock()
+	fake.getDashboardVersionsMutex.RLock()
+	defer fake.getDashboardVersionsMutex.RUnlock()
+	fake.getLibraryPanelMutex.RLock()
+	defer fake.getLibraryPanelMutex.RUnlock()
+	fake.getFoldersMutex.RLock()
+	defer fake.getFoldersMutex.RUnlock()
+	fake.getFolderPermissionsMutex.RLock()
+	defer fake.getFolderPermissionsMutex.RUnlock()
+	fake.getTeamsMutex.RLock()
+	defer fake.getTeamsMutex.RUnlock()
+	fake.getDatasourcesMutex.RLock()
+	defer fake.getDatasourcesMutex.RUnlock()
+	fake.getDatasourceHealthMutex.RLock()
+	defer fake.getDatasourceHealthMutex.RUnlock()
+	fake.getOrgUsersMutex.RLock()
+	defer fake.getOrgUsersMutex.RUnlock()
+	fake.getAlertRulesMutex.RLock()
+	defer fake.getAlertRulesMutex.RUnlock()
+	fake.getAlertsMutex.RLock()
+	defer fake.getAlertsMutex.RUnlock()
+	fake.getRuleGroupsMutex.RLock()
+	defer fake.getRuleGroupsMutex.RUnlock()
+	fake.getAlertmanagerAlertsMutex.RLock()
+	defer fake.getAlertmanagerAlertsMutex.RUnlock()
+	fake.getSilencesMutex.RLock()
+	defer fake.getSilencesMutex.RUnlock()
+	fake.getNotificationChannelsMutex.RLock()
+	defer fake.getNotificationChannelsMutex.RUnlock()
+	fake.getContactPointsMutex.RLock()
+	defer fake.getContactPointsMutex.RUnlock()
+	fake.getReceiversMutex.RLock()
+	defer fake.getReceiversMutex.RUnlock()
+	fake.getAPIKeysMutex.RLock()
+	defer fake.getAPIKeysMutex.RUnlock()
+	fake.getServiceAccountsMutex.RLock()
+	defer fake.getServiceAccountsMutex.RUnlock()
+	fake.getServiceAccountTokensMutex.RLock()
+	defer fake.getServiceAccountTokensMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
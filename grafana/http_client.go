@@ -1,7 +1,9 @@
 package grafana
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,118 +11,642 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/common/version"
 )
 
+const DefaultTimeout = 10 * time.Second
+
+// searchPageSize is the maximum number of results Grafana returns per search
+// page.
+const searchPageSize = 5000
+
+// usersPageSize is the number of users requested per users search page.
+const usersPageSize = 1000
+
+// serviceAccountsPageSize is the number of service accounts requested per
+// service accounts search page.
+const serviceAccountsPageSize = 1000
+
+// teamsPageSize is the number of teams requested per teams search page.
+const teamsPageSize = 1000
+
+type HTTPClientConfig struct {
+	Username      string        `yaml:"username,omitempty"`
+	Password      string        `yaml:"password,omitempty"`
+	PasswordFile  string        `yaml:"password_file,omitempty"`
+	APIToken      string        `yaml:"api_token,omitempty"`
+	APITokenFile  string        `yaml:"api_token_file,omitempty"`
+	SkipSSLVerify bool          `yaml:"skip_ssl_verify,omitempty"`
+	CAFile        string        `yaml:"ca_file,omitempty"`
+	CertFile      string        `yaml:"cert_file,omitempty"`
+	KeyFile       string        `yaml:"key_file,omitempty"`
+	ServerName    string        `yaml:"server_name,omitempty"`
+	Timeout       time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Validate checks that at most one authentication method is configured, and
+// that the client certificate and key are set together.
+func (c HTTPClientConfig) Validate() error {
+	if c.Password != "" && c.PasswordFile != "" {
+		return errors.New("at most one of `password` and `password_file` must be set")
+	}
+
+	if c.APIToken != "" && c.APITokenFile != "" {
+		return errors.New("at most one of `api_token` and `api_token_file` must be set")
+	}
+
+	if (c.APIToken != "" || c.APITokenFile != "") && (c.Username != "" || c.Password != "" || c.PasswordFile != "") {
+		return errors.New("basic authentication (`username` and `password`) and API token authentication are mutually exclusive")
+	}
+
+	if (c.CertFile != "") != (c.KeyFile != "") {
+		return errors.New("both `cert_file` and `key_file` must be set to use a client certificate")
+	}
+
+	return nil
+}
+
+type StatusCodeError struct {
+	Resource   string
+	StatusCode int
+}
+
+func (e StatusCodeError) Error() string {
+	return fmt.Sprintf("Error getting %s, http status code: %d", e.Resource, e.StatusCode)
+}
+
 type HTTPClient struct {
-	url        *url.URL
-	username   string
-	password   string
-	httpClient *http.Client
+	url          *url.URL
+	username     string
+	password     string
+	passwordFile *secretFile
+	apiToken     string
+	apiTokenFile *secretFile
+	transport    *tlsTransport
+	httpClient   *http.Client
 }
 
-func NewHTTPClient(uri string, username string, password string, skipSSLVerify bool) (*HTTPClient, error) {
+func NewHTTPClient(uri string, config HTTPClientConfig) (*HTTPClient, error) {
 	grafanaURL, err := url.Parse(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		MaxIdleConns:    10,
-		IdleConnTimeout: 30 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: skipSSLVerify,
-		},
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var passwordFile *secretFile
+	if config.PasswordFile != "" {
+		passwordFile, err = newSecretFile(config.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var apiTokenFile *secretFile
+	if config.APITokenFile != "" {
+		apiTokenFile, err = newSecretFile(config.APITokenFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	transport, err := newTLSTransport(config, func(tlsConfig *tls.Config) *http.Transport {
+		return &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:    10,
+			IdleConnTimeout: 30 * time.Second,
+			TLSClientConfig: tlsConfig,
+		}
+	})
+	if err != nil {
+		return nil, err
 	}
 	httpClient := &http.Client{
-		Timeout:   time.Duration(10 * time.Second),
+		Timeout:   timeout,
 		Transport: transport,
 	}
 	grafanaClient := &HTTPClient{
-		url:        grafanaURL,
-		username:   username,
-		password:   password,
-		httpClient: httpClient,
+		url:          grafanaURL,
+		username:     config.Username,
+		password:     config.Password,
+		passwordFile: passwordFile,
+		apiToken:     config.APIToken,
+		apiTokenFile: apiTokenFile,
+		transport:    transport,
+		httpClient:   httpClient,
 	}
 
 	return grafanaClient, nil
 }
 
-func (c *HTTPClient) GetAdminStats() (AdminStats, error) {
+// CloseIdleConnections closes the idle connections to Grafana, i.e. once the
+// client is no longer used.
+func (c *HTTPClient) CloseIdleConnections() {
+	c.transport.CloseIdleConnections()
+}
+
+func newTLSConfig(config HTTPClientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.SkipSSLVerify,
+		ServerName:         config.ServerName,
+	}
+
+	if config.CAFile != "" {
+		caCert, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Error reading CA file `%s`: %s", config.CAFile, err))
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New(fmt.Sprintf("Error parsing CA file `%s`: no PEM certificates found", config.CAFile))
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if config.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Error loading client certificate `%s` and key `%s`: %s", config.CertFile, config.KeyFile, err))
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (c *HTTPClient) GetAdminStats(ctx context.Context) (AdminStats, error) {
 	var adminStats AdminStats
 
-	uri := c.url
-	uri.Path = "/api/admin/stats"
-	request, err := http.NewRequest(http.MethodGet, uri.String(), nil)
-	if err != nil {
+	if err := c.get(ctx, 0, "/api/admin/stats", nil, "admin stats", &adminStats); err != nil {
+		if statusCodeErr, ok := err.(StatusCodeError); ok && statusCodeErr.StatusCode == http.StatusForbidden {
+			return adminStats, errors.New(fmt.Sprintf("%s, the credentials lack the Server Admin permission required by `/api/admin/stats`", err))
+		}
 		return adminStats, err
 	}
-	request.Header.Set("User-Agent", "grafana_exporter "+version.Version)
-	if c.username != "" && c.password != "" {
-		request.SetBasicAuth(c.username, c.password)
+
+	return adminStats, nil
+}
+
+func (c *HTTPClient) GetMetrics(ctx context.Context) (Metrics, error) {
+	var metrics Metrics
+
+	if err := c.get(ctx, 0, "/api/metrics", nil, "metrics", &metrics); err != nil {
+		return metrics, err
 	}
 
-	response, err := c.httpClient.Do(request)
-	if err != nil {
-		return adminStats, errors.New(fmt.Sprintf("Error getting admin stats: %s", err))
+	return metrics, nil
+}
+
+func (c *HTTPClient) GetHealth(ctx context.Context) (Health, error) {
+	var health Health
+
+	if err := c.get(ctx, 0, "/api/health", nil, "health", &health); err != nil {
+		// Grafana answers with a 503 http status code when its database is
+		// failing, which is a health to report rather than an error.
+		if statusCodeErr, ok := err.(StatusCodeError); ok && statusCodeErr.StatusCode == http.StatusServiceUnavailable {
+			return Health{Database: "failing"}, nil
+		}
+		return health, err
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return adminStats, errors.New(fmt.Sprintf("Error getting admin stats, http status code: %d", response.StatusCode))
+	return health, nil
+}
+
+func (c *HTTPClient) GetFrontendSettings(ctx context.Context) (FrontendSettings, error) {
+	var frontendSettings FrontendSettings
+
+	if err := c.get(ctx, 0, "/api/frontend/settings", nil, "frontend settings", &frontendSettings); err != nil {
+		return frontendSettings, err
 	}
 
-	responseBody, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return adminStats, errors.New(fmt.Sprintf("Error reading admin stats response: %s", err))
+	return frontendSettings, nil
+}
+
+func (c *HTTPClient) GetOrgs(ctx context.Context) ([]Org, error) {
+	var orgs []Org
+
+	query := url.Values{"perpage": []string{"5000"}}
+	if err := c.get(ctx, 0, "/api/orgs", query, "orgs", &orgs); err != nil {
+		return orgs, err
 	}
-	defer response.Body.Close()
 
-	if err := json.Unmarshal(responseBody, &adminStats); err != nil {
-		return adminStats, errors.New(fmt.Sprintf("Error unmarshalling admin stats response: %s", err))
+	return orgs, nil
+}
+
+// GetDashboards pages through the dashboards search results, as Grafana caps
+// the number of results returned by a single search.
+func (c *HTTPClient) GetDashboards(ctx context.Context, orgID int64) ([]SearchHit, error) {
+	var dashboards []SearchHit
+
+	for page := 1; ; page++ {
+		var searchHits []SearchHit
+
+		query := url.Values{
+			"type":  []string{"dash-db"},
+			"limit": []string{strconv.Itoa(searchPageSize)},
+			"page":  []string{strconv.Itoa(page)},
+		}
+		if err := c.get(ctx, orgID, "/api/search", query, "dashboards", &searchHits); err != nil {
+			return dashboards, err
+		}
+
+		dashboards = append(dashboards, searchHits...)
+		if len(searchHits) < searchPageSize {
+			return dashboards, nil
+		}
 	}
+}
 
-	return adminStats, nil
+// GetUsers pages through the users search results of the Grafana server, in
+// the context of no org, so it requires the Server Admin permission.
+func (c *HTTPClient) GetUsers(ctx context.Context) ([]User, error) {
+	var users []User
+
+	for page := 1; ; page++ {
+		var usersSearch struct {
+			TotalCount int    `json:"totalCount"`
+			Users      []User `json:"users"`
+		}
+
+		query := url.Values{
+			"perpage": []string{strconv.Itoa(usersPageSize)},
+			"page":    []string{strconv.Itoa(page)},
+		}
+		if err := c.get(ctx, 0, "/api/users/search", query, "users", &usersSearch); err != nil {
+			return users, err
+		}
+
+		users = append(users, usersSearch.Users...)
+		if len(usersSearch.Users) == 0 || len(users) >= usersSearch.TotalCount {
+			return users, nil
+		}
+	}
 }
 
-func (c *HTTPClient) GetMetrics() (Metrics, error) {
-	var metrics Metrics
+func (c *HTTPClient) GetDashboard(ctx context.Context, orgID int64, uid string) (Dashboard, error) {
+	var dashboard Dashboard
+
+	if err := c.get(ctx, orgID, "/api/dashboards/uid/"+url.PathEscape(uid), nil, "dashboard", &dashboard); err != nil {
+		return dashboard, err
+	}
+
+	return dashboard, nil
+}
+
+func (c *HTTPClient) GetDashboardVersions(ctx context.Context, orgID int64, uid string, limit int) (DashboardVersions, error) {
+	var dashboardVersions DashboardVersions
+
+	path := "/api/dashboards/uid/" + url.PathEscape(uid) + "/versions"
+	query := url.Values{"limit": []string{strconv.Itoa(limit)}}
+	if err := c.get(ctx, orgID, path, query, "dashboard versions", &dashboardVersions); err != nil {
+		return dashboardVersions, err
+	}
+
+	return dashboardVersions, nil
+}
+
+func (c *HTTPClient) GetLibraryPanel(ctx context.Context, orgID int64, uid string) (LibraryPanel, error) {
+	var libraryElement struct {
+		Result LibraryPanel `json:"result"`
+	}
 
-	uri := c.url
-	uri.Path = "/api/metrics"
-	request, err := http.NewRequest(http.MethodGet, uri.String(), nil)
+	if err := c.get(ctx, orgID, "/api/library-elements/"+url.PathEscape(uid), nil, "library panel", &libraryElement); err != nil {
+		return libraryElement.Result, err
+	}
+
+	return libraryElement.Result, nil
+}
+
+func (c *HTTPClient) GetFolders(ctx context.Context, orgID int64) ([]Folder, error) {
+	var folders []Folder
+
+	query := url.Values{"limit": []string{"5000"}}
+	if err := c.get(ctx, orgID, "/api/folders", query, "folders", &folders); err != nil {
+		return folders, err
+	}
+
+	return folders, nil
+}
+
+func (c *HTTPClient) GetFolderPermissions(ctx context.Context, orgID int64, uid string) ([]FolderPermission, error) {
+	var folderPermissions []FolderPermission
+
+	if err := c.get(ctx, orgID, "/api/folders/"+url.PathEscape(uid)+"/permissions", nil, "folder permissions", &folderPermissions); err != nil {
+		return folderPermissions, err
+	}
+
+	return folderPermissions, nil
+}
+
+func (c *HTTPClient) GetTeams(ctx context.Context, orgID int64) ([]Team, error) {
+	var teams []Team
+
+	for page := 1; ; page++ {
+		var teamsSearch struct {
+			TotalCount int    `json:"totalCount"`
+			Teams      []Team `json:"teams"`
+		}
+
+		query := url.Values{
+			"perpage": []string{strconv.Itoa(teamsPageSize)},
+			"page":    []string{strconv.Itoa(page)},
+		}
+		if err := c.get(ctx, orgID, "/api/teams/search", query, "teams", &teamsSearch); err != nil {
+			return teams, err
+		}
+
+		teams = append(teams, teamsSearch.Teams...)
+		if len(teamsSearch.Teams) == 0 || len(teams) >= teamsSearch.TotalCount {
+			return teams, nil
+		}
+	}
+}
+
+func (c *HTTPClient) GetDatasources(ctx context.Context, orgID int64) ([]Datasource, error) {
+	var datasources []Datasource
+
+	if err := c.get(ctx, orgID, "/api/datasources", nil, "datasources", &datasources); err != nil {
+		return datasources, err
+	}
+
+	return datasources, nil
+}
+
+// GetDatasourceHealth runs the health check of the datasource. Grafana answers
+// a failed health check with a `400` http status code, which is returned as
+// an `ERROR` status rather than as an error.
+func (c *HTTPClient) GetDatasourceHealth(ctx context.Context, orgID int64, uid string) (DatasourceHealth, error) {
+	var datasourceHealth DatasourceHealth
+
+	path := "/api/datasources/uid/" + url.PathEscape(uid) + "/health"
+	if err := c.get(ctx, orgID, path, nil, "datasource health", &datasourceHealth); err != nil {
+		if statusCodeErr, ok := err.(StatusCodeError); ok && statusCodeErr.StatusCode == http.StatusBadRequest {
+			return DatasourceHealth{Status: "ERROR"}, nil
+		}
+		return datasourceHealth, err
+	}
+
+	return datasourceHealth, nil
+}
+
+// GetOrgUsers uses the admin endpoint of the org instead of the current org
+// one, so the users of an org the exporter user is not a member of can be
+// requested as well.
+func (c *HTTPClient) GetOrgUsers(ctx context.Context, orgID int64) ([]OrgUser, error) {
+	var orgUsers []OrgUser
+
+	path := "/api/orgs/" + strconv.FormatInt(orgID, 10) + "/users"
+	if err := c.get(ctx, 0, path, nil, "org users", &orgUsers); err != nil {
+		return orgUsers, err
+	}
+
+	return orgUsers, nil
+}
+
+func (c *HTTPClient) GetAlertRules(ctx context.Context, orgID int64) ([]AlertRule, error) {
+	var alertRules []AlertRule
+
+	if err := c.get(ctx, orgID, "/api/v1/provisioning/alert-rules", nil, "alert rules", &alertRules); err != nil {
+		return alertRules, err
+	}
+
+	return alertRules, nil
+}
+
+// GetAlerts returns every legacy alert of the org in a single request. Unlike
+// the search, `/api/alerts` cannot be paged: it has no page parameter, and
+// only caps its results when a `limit` is set, which is left out on purpose.
+func (c *HTTPClient) GetAlerts(ctx context.Context, orgID int64) ([]Alert, error) {
+	var alerts []Alert
+
+	if err := c.get(ctx, orgID, "/api/alerts", nil, "alerts", &alerts); err != nil {
+		return alerts, err
+	}
+
+	return alerts, nil
+}
+
+func (c *HTTPClient) GetRuleGroups(ctx context.Context, orgID int64) ([]RuleGroup, error) {
+	var rules struct {
+		Data struct {
+			Groups []RuleGroup `json:"groups"`
+		} `json:"data"`
+	}
+
+	if err := c.get(ctx, orgID, "/api/prometheus/grafana/api/v1/rules", nil, "rule groups", &rules); err != nil {
+		return rules.Data.Groups, err
+	}
+
+	return rules.Data.Groups, nil
+}
+
+func (c *HTTPClient) GetAlertmanagerAlerts(ctx context.Context, orgID int64) ([]AlertmanagerAlert, error) {
+	var alerts []AlertmanagerAlert
+
+	if err := c.get(ctx, orgID, "/api/alertmanager/grafana/api/v2/alerts", nil, "alertmanager alerts", &alerts); err != nil {
+		return alerts, err
+	}
+
+	return alerts, nil
+}
+
+func (c *HTTPClient) GetSilences(ctx context.Context, orgID int64) ([]Silence, error) {
+	var silences []Silence
+
+	if err := c.get(ctx, orgID, "/api/alertmanager/grafana/api/v2/silences", nil, "silences", &silences); err != nil {
+		return silences, err
+	}
+
+	return silences, nil
+}
+
+func (c *HTTPClient) GetNotificationChannels(ctx context.Context, orgID int64) ([]NotificationChannel, error) {
+	var notificationChannels []NotificationChannel
+
+	if err := c.get(ctx, orgID, "/api/alert-notifications", nil, "notification channels", &notificationChannels); err != nil {
+		return notificationChannels, err
+	}
+
+	return notificationChannels, nil
+}
+
+func (c *HTTPClient) GetContactPoints(ctx context.Context, orgID int64) ([]ContactPoint, error) {
+	var contactPoints []ContactPoint
+
+	if err := c.get(ctx, orgID, "/api/v1/provisioning/contact-points", nil, "contact points", &contactPoints); err != nil {
+		return contactPoints, err
+	}
+
+	return contactPoints, nil
+}
+
+func (c *HTTPClient) GetReceivers(ctx context.Context, orgID int64) ([]Receiver, error) {
+	var receivers []Receiver
+
+	if err := c.get(ctx, orgID, "/api/alertmanager/grafana/config/api/v1/receivers", nil, "receivers", &receivers); err != nil {
+		return receivers, err
+	}
+
+	return receivers, nil
+}
+
+func (c *HTTPClient) GetAPIKeys(ctx context.Context, orgID int64) ([]APIKey, error) {
+	var apiKeys []APIKey
+
+	query := url.Values{"includeExpired": []string{"true"}}
+	if err := c.get(ctx, orgID, "/api/auth/keys", query, "api keys", &apiKeys); err != nil {
+		return apiKeys, err
+	}
+
+	return apiKeys, nil
+}
+
+func (c *HTTPClient) GetServiceAccounts(ctx context.Context, orgID int64) ([]ServiceAccount, error) {
+	var serviceAccounts []ServiceAccount
+
+	for page := 1; ; page++ {
+		var serviceAccountsSearch struct {
+			TotalCount      int              `json:"totalCount"`
+			ServiceAccounts []ServiceAccount `json:"serviceAccounts"`
+		}
+
+		query := url.Values{
+			"perpage": []string{strconv.Itoa(serviceAccountsPageSize)},
+			"page":    []string{strconv.Itoa(page)},
+		}
+		if err := c.get(ctx, orgID, "/api/serviceaccounts/search", query, "service accounts", &serviceAccountsSearch); err != nil {
+			return serviceAccounts, err
+		}
+
+		serviceAccounts = append(serviceAccounts, serviceAccountsSearch.ServiceAccounts...)
+		if len(serviceAccountsSearch.ServiceAccounts) == 0 || len(serviceAccounts) >= serviceAccountsSearch.TotalCount {
+			return serviceAccounts, nil
+		}
+	}
+}
+
+func (c *HTTPClient) GetServiceAccountTokens(ctx context.Context, orgID int64, serviceAccountID int64) ([]ServiceAccountToken, error) {
+	var serviceAccountTokens []ServiceAccountToken
+
+	path := "/api/serviceaccounts/" + strconv.FormatInt(serviceAccountID, 10) + "/tokens"
+	if err := c.get(ctx, orgID, path, nil, "service account tokens", &serviceAccountTokens); err != nil {
+		return serviceAccountTokens, err
+	}
+
+	return serviceAccountTokens, nil
+}
+
+// get requests the endpoint path in the context of the given Grafana org, or
+// in the context of the current org of the credentials if the org id is 0.
+// The path is escaped, so the uids it holds must be escaped with
+// url.PathEscape.
+func (c *HTTPClient) get(ctx context.Context, orgID int64, path string, query url.Values, resource string, v interface{}) error {
+	endpointURL, err := c.endpointURL(path, query)
 	if err != nil {
-		return metrics, err
+		return errors.New(fmt.Sprintf("Error getting %s: %s", resource, err))
 	}
+
+	request, err := http.NewRequest(http.MethodGet, endpointURL, nil)
+	if err != nil {
+		return err
+	}
+	request = request.WithContext(ctx)
 	request.Header.Set("User-Agent", "grafana_exporter "+version.Version)
-	if c.username != "" && c.password != "" {
-		request.SetBasicAuth(c.username, c.password)
+	if orgID != 0 {
+		request.Header.Set("X-Grafana-Org-Id", strconv.FormatInt(orgID, 10))
+	}
+	if err := c.setAuthentication(request); err != nil {
+		return errors.New(fmt.Sprintf("Error getting %s: %s", resource, err))
 	}
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
-		return metrics, errors.New(fmt.Sprintf("Error getting metrics: %s", err))
+		return errors.New(fmt.Sprintf("Error getting %s: %s", resource, err))
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return metrics, errors.New(fmt.Sprintf("Error getting metrics, http status code: %d", response.StatusCode))
+		return StatusCodeError{Resource: resource, StatusCode: response.StatusCode}
 	}
 
 	responseBody, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return metrics, errors.New(fmt.Sprintf("Error reading metrics response: %s", err))
+		return errors.New(fmt.Sprintf("Error reading %s response: %s", resource, err))
 	}
-	defer response.Body.Close()
 
-	if err := json.Unmarshal(responseBody, &metrics); err != nil {
-		return metrics, errors.New(fmt.Sprintf("Error unmarshalling metrics response: %s", err))
+	if err := json.Unmarshal(responseBody, v); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarshalling %s response: %s", resource, err))
 	}
 
-	return metrics, nil
+	return nil
+}
+
+// endpointURL joins the escaped endpoint path onto the Grafana URI path, so
+// Grafanas served from a sub path are supported, and adds the endpoint query
+// parameters to the URI ones. Both the unescaped and the escaped paths are
+// set, so the escaped uids (i.e. `%2F`) are requested as they are instead of
+// being escaped again. It works on a copy of the URI, as it is shared by
+// concurrent scrapes.
+func (c *HTTPClient) endpointURL(path string, query url.Values) (string, error) {
+	unescapedPath, err := url.PathUnescape(path)
+	if err != nil {
+		return "", err
+	}
+
+	uri := *c.url
+	uri.Path = strings.TrimSuffix(c.url.Path, "/") + unescapedPath
+	uri.RawPath = strings.TrimSuffix(c.url.EscapedPath(), "/") + path
+
+	if len(query) > 0 {
+		uriQuery := uri.Query()
+		for name, values := range query {
+			uriQuery[name] = values
+		}
+		uri.RawQuery = uriQuery.Encode()
+	}
+
+	return uri.String(), nil
+}
+
+func (c *HTTPClient) setAuthentication(request *http.Request) error {
+	apiToken := c.apiToken
+	if c.apiTokenFile != nil {
+		var err error
+		if apiToken, err = c.apiTokenFile.Get(); err != nil {
+			return err
+		}
+	}
+	if apiToken != "" {
+		request.Header.Set("Authorization", "Bearer "+apiToken)
+		return nil
+	}
+
+	password := c.password
+	if c.passwordFile != nil {
+		var err error
+		if password, err = c.passwordFile.Get(); err != nil {
+			return err
+		}
+	}
+	if c.username != "" && password != "" {
+		request.SetBasicAuth(c.username, password)
+	}
+
+	return nil
 }
@@ -1,7 +1,19 @@
 package grafana_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
 	"net/http"
+	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -12,19 +24,31 @@ import (
 
 var _ = Describe("HTTPClient", func() {
 	var (
-		server *ghttp.Server
-		client Client
-		err    error
+		server       *ghttp.Server
+		client       Client
+		clientConfig HTTPClientConfig
+		authHandler  http.HandlerFunc
+		err          error
 
 		username      = "fake-username"
 		password      = "fake-password"
+		apiToken      = "fake-api-token"
 		skipSSLVerify = true
 	)
 
 	BeforeEach(func() {
 		server = ghttp.NewServer()
 
-		client, err = NewHTTPClient(server.URL(), username, password, skipSSLVerify)
+		clientConfig = HTTPClientConfig{
+			Username:      username,
+			Password:      password,
+			SkipSSLVerify: skipSSLVerify,
+		}
+		authHandler = ghttp.VerifyBasicAuth(username, password)
+	})
+
+	JustBeforeEach(func() {
+		client, err = NewHTTPClient(server.URL(), clientConfig)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -32,6 +56,236 @@ var _ = Describe("HTTPClient", func() {
 		server.Close()
 	})
 
+	Describe("NewHTTPClient", func() {
+		Context("when both basic authentication and an API token are set", func() {
+			It("returns an error", func() {
+				_, err := NewHTTPClient(server.URL(), HTTPClientConfig{
+					Username: username,
+					Password: password,
+					APIToken: apiToken,
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("mutually exclusive"))
+			})
+		})
+
+		Context("when both a password and a password file are set", func() {
+			It("returns an error", func() {
+				_, err := NewHTTPClient(server.URL(), HTTPClientConfig{
+					Username:     username,
+					Password:     password,
+					PasswordFile: "/password-file",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("at most one of `password` and `password_file` must be set"))
+			})
+		})
+
+		Context("when only a client certificate is set", func() {
+			It("returns an error", func() {
+				_, err := NewHTTPClient(server.URL(), HTTPClientConfig{
+					CertFile: "/cert-file",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("both `cert_file` and `key_file` must be set to use a client certificate"))
+			})
+		})
+
+		Context("when the client certificate does not exist", func() {
+			It("returns an error", func() {
+				_, err := NewHTTPClient(server.URL(), HTTPClientConfig{
+					CertFile: "/non-existent-cert-file",
+					KeyFile:  "/non-existent-key-file",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("Error loading client certificate `/non-existent-cert-file` and key `/non-existent-key-file`"))
+			})
+		})
+
+		Context("when the CA file does not contain certificates", func() {
+			var (
+				caFile string
+			)
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "grafana_exporter")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+				caFile = file.Name()
+			})
+
+			AfterEach(func() {
+				os.Remove(caFile)
+			})
+
+			It("returns an error", func() {
+				_, err := NewHTTPClient(server.URL(), HTTPClientConfig{
+					CAFile: caFile,
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error parsing CA file `" + caFile + "`: no PEM certificates found"))
+			})
+		})
+
+		Context("when the API token file does not exist", func() {
+			It("returns an error", func() {
+				_, err := NewHTTPClient(server.URL(), HTTPClientConfig{
+					APITokenFile: "/non-existent-api-token-file",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("Error reading secret file `/non-existent-api-token-file`"))
+			})
+		})
+	})
+
+	Describe("TLS", func() {
+		var (
+			tlsServer *ghttp.Server
+			caFile    string
+		)
+
+		BeforeEach(func() {
+			tlsServer = ghttp.NewTLSServer()
+			tlsServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/admin/stats"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, AdminStats{}),
+				),
+			)
+
+			file, err := ioutil.TempFile("", "grafana_exporter")
+			Expect(err).ToNot(HaveOccurred())
+			err = pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.HTTPTestServer.Certificate().Raw})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			caFile = file.Name()
+		})
+
+		AfterEach(func() {
+			tlsServer.Close()
+			os.Remove(caFile)
+		})
+
+		It("verifies the server certificate against the CA file", func() {
+			tlsClient, err := NewHTTPClient(tlsServer.URL(), HTTPClientConfig{
+				CAFile:     caFile,
+				ServerName: "example.com",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = tlsClient.GetAdminStats(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("when the server certificate is not signed by the CA", func() {
+			It("returns an error", func() {
+				tlsClient, err := NewHTTPClient(tlsServer.URL(), HTTPClientConfig{})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("certificate"))
+			})
+		})
+
+		Context("when the CA file is rotated", func() {
+			It("verifies the server certificate against the new CA", func() {
+				Expect(writeCertificate(caFile, "", "fake-ca")).To(Succeed())
+				tlsClient, err := NewHTTPClient(tlsServer.URL(), HTTPClientConfig{
+					CAFile:     caFile,
+					ServerName: "example.com",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).To(HaveOccurred())
+
+				err = ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.HTTPTestServer.Certificate().Raw}), 0600)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(touch(caFile)).To(Succeed())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("when the CA file is rotated to an invalid file", func() {
+			It("keeps the previous CA until the CA file is valid again", func() {
+				tlsClient, err := NewHTTPClient(tlsServer.URL(), HTTPClientConfig{
+					CAFile:     caFile,
+					ServerName: "example.com",
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ioutil.WriteFile(caFile, []byte("fake-half-written-ca"), 0600)).To(Succeed())
+				Expect(touch(caFile)).To(Succeed())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(writeCertificate(caFile, "", "fake-ca")).To(Succeed())
+				Expect(touch(caFile)).To(Succeed())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("certificate"))
+			})
+		})
+
+		Context("when the client certificate is rotated", func() {
+			var (
+				mtlsServer  *ghttp.Server
+				certFile    string
+				keyFile     string
+				commonNames chan string
+			)
+
+			BeforeEach(func() {
+				commonNames = make(chan string, 2)
+				mtlsServer = ghttp.NewUnstartedServer()
+				mtlsServer.HTTPTestServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+				mtlsServer.HTTPTestServer.StartTLS()
+				mtlsServer.RouteToHandler("GET", "/api/admin/stats", ghttp.CombineHandlers(
+					func(w http.ResponseWriter, r *http.Request) {
+						commonNames <- r.TLS.PeerCertificates[0].Subject.CommonName
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusOK, AdminStats{}),
+				))
+
+				certFile = caFile + ".crt"
+				keyFile = caFile + ".key"
+				Expect(writeCertificate(certFile, keyFile, "fake-client-1")).To(Succeed())
+			})
+
+			AfterEach(func() {
+				mtlsServer.Close()
+				os.Remove(certFile)
+				os.Remove(keyFile)
+			})
+
+			It("presents the new client certificate", func() {
+				tlsClient, err := NewHTTPClient(mtlsServer.URL(), HTTPClientConfig{
+					SkipSSLVerify: true,
+					CertFile:      certFile,
+					KeyFile:       keyFile,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(commonNames).To(Receive(Equal("fake-client-1")))
+
+				Expect(writeCertificate(certFile, keyFile, "fake-client-2")).To(Succeed())
+				Expect(touch(certFile)).To(Succeed())
+				Expect(touch(keyFile)).To(Succeed())
+
+				_, err = tlsClient.GetAdminStats(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(commonNames).To(Receive(Equal("fake-client-2")))
+			})
+		})
+	})
+
 	Describe("GetAdminStats", func() {
 		var (
 			statusCode         int
@@ -56,14 +310,14 @@ var _ = Describe("HTTPClient", func() {
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
 					ghttp.VerifyRequest("GET", "/api/admin/stats"),
-					ghttp.VerifyBasicAuth(username, password),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
 					ghttp.RespondWithJSONEncodedPtr(&statusCode, &adminStatsResponse),
 				),
 			)
 		})
 
 		JustBeforeEach(func() {
-			adminStats, err = client.GetAdminStats()
+			adminStats, err = client.GetAdminStats(context.Background())
 		})
 
 		It("returns the admin stats", func() {
@@ -71,6 +325,153 @@ var _ = Describe("HTTPClient", func() {
 			Expect(adminStats).To(Equal(adminStatsResponse))
 		})
 
+		Context("when Grafana is served from a sub path", func() {
+			var (
+				subPathServer *ghttp.Server
+			)
+
+			BeforeEach(func() {
+				subPathServer = ghttp.NewServer()
+				subPathServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/grafana/api/admin/stats", "orgId=1"),
+						ghttp.RespondWithJSONEncodedPtr(&statusCode, &adminStatsResponse),
+					),
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/grafana/api/metrics", "orgId=1"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, Metrics{}),
+					),
+				)
+			})
+
+			AfterEach(func() {
+				subPathServer.Close()
+			})
+
+			It("joins the endpoint path onto the sub path and keeps the query parameters", func() {
+				subPathClient, err := NewHTTPClient(subPathServer.URL()+"/grafana/?orgId=1", HTTPClientConfig{})
+				Expect(err).ToNot(HaveOccurred())
+
+				adminStats, err := subPathClient.GetAdminStats(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(adminStats).To(Equal(adminStatsResponse))
+
+				_, err = subPathClient.GetMetrics(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("when using an API token", func() {
+			BeforeEach(func() {
+				clientConfig.Username = ""
+				clientConfig.Password = ""
+				clientConfig.APIToken = apiToken
+				authHandler = ghttp.VerifyHeaderKV("Authorization", "Bearer "+apiToken)
+			})
+
+			It("returns the admin stats", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(adminStats).To(Equal(adminStatsResponse))
+			})
+		})
+
+		Context("when using an API token file", func() {
+			var (
+				apiTokenFile string
+			)
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "grafana_exporter")
+				Expect(err).ToNot(HaveOccurred())
+				_, err = file.WriteString(apiToken + "\n")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+				apiTokenFile = file.Name()
+
+				clientConfig.Username = ""
+				clientConfig.Password = ""
+				clientConfig.APITokenFile = apiTokenFile
+				authHandler = ghttp.VerifyHeaderKV("Authorization", "Bearer "+apiToken)
+			})
+
+			AfterEach(func() {
+				os.Remove(apiTokenFile)
+			})
+
+			It("returns the admin stats", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(adminStats).To(Equal(adminStatsResponse))
+			})
+		})
+
+		Context("when using a password file", func() {
+			var (
+				passwordFile string
+			)
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "grafana_exporter")
+				Expect(err).ToNot(HaveOccurred())
+				_, err = file.WriteString(password + "\n")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+				passwordFile = file.Name()
+
+				clientConfig.Password = ""
+				clientConfig.PasswordFile = passwordFile
+			})
+
+			AfterEach(func() {
+				os.Remove(passwordFile)
+			})
+
+			It("returns the admin stats", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(adminStats).To(Equal(adminStatsResponse))
+			})
+
+			Context("when the password file changes", func() {
+				var (
+					rotatedPassword = "fake-rotated-password"
+				)
+
+				BeforeEach(func() {
+					server.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("GET", "/api/admin/stats"),
+							ghttp.VerifyBasicAuth(username, rotatedPassword),
+							ghttp.RespondWithJSONEncodedPtr(&statusCode, &adminStatsResponse),
+						),
+					)
+				})
+
+				It("uses the new password", func() {
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(ioutil.WriteFile(passwordFile, []byte(rotatedPassword), 0600)).To(Succeed())
+					modTime := time.Now().Add(time.Minute)
+					Expect(os.Chtimes(passwordFile, modTime, modTime)).To(Succeed())
+
+					adminStats, err = client.GetAdminStats(context.Background())
+					Expect(err).ToNot(HaveOccurred())
+					Expect(adminStats).To(Equal(adminStatsResponse))
+				})
+			})
+		})
+
+		Context("when the context is done", func() {
+			It("returns an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err = client.GetAdminStats(ctx)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("context canceled"))
+			})
+		})
+
 		Context("when it fails to get the admin stats", func() {
 			BeforeEach(func() {
 				statusCode = http.StatusInternalServerError
@@ -81,6 +482,17 @@ var _ = Describe("HTTPClient", func() {
 				Expect(err.Error()).To(Equal("Error getting admin stats, http status code: 500"))
 			})
 		})
+
+		Context("when the credentials lack the Server Admin permission", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusForbidden
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting admin stats, http status code: 403, the credentials lack the Server Admin permission required by `/api/admin/stats`"))
+			})
+		})
 	})
 
 	Describe("GetMetrics", func() {
@@ -93,218 +505,41 @@ var _ = Describe("HTTPClient", func() {
 		BeforeEach(func() {
 			statusCode = http.StatusOK
 			metricsResponse = Metrics{
-				AlertingActiveAlerts: Gauge{
-					Value: int64(1),
-				},
-				AlertingNotificationsSentLine: Counter{
-					Count: int64(2),
-				},
-				AlertingExecutionTime: Timer{
-					Count: int64(1),
-					Max:   int64(2),
-					Mean:  float64(1.1),
-					Min:   int64(3),
-					P25:   float64(2.2),
-					P75:   float64(3.3),
-					P90:   float64(3.4),
-					P99:   float64(3.5),
-					Std:   float64(3.6),
-				},
-				AlertingNotificationsSentDingDing: Counter{
-					Count: int64(3),
-				},
-				AlertingNotificationsSentEmail: Counter{
-					Count: int64(4),
-				},
-				AlertingNotificationsSentOpsgenie: Counter{
-					Count: int64(5),
-				},
-				AlertingNotificationsSentPagerduty: Counter{
-					Count: int64(6),
-				},
-				AlertingNotificationsSentPushover: Counter{
-					Count: int64(7),
-				},
-				AlertingNotificationsSentSensu: Counter{
-					Count: int64(8),
-				},
-				AlertingNotificationsSentSlack: Counter{
-					Count: int64(9),
-				},
-				AlertingNotificationsSentTelegram: Counter{
-					Count: int64(10),
-				},
-				AlertingNotificationsSentThreema: Counter{
-					Count: int64(11),
-				},
-				AlertingNotificationsSentVictorops: Counter{
-					Count: int64(12),
-				},
-				AlertingNotificationsSentWebhook: Counter{
-					Count: int64(13),
-				},
-				AlertingResultStateAlerting: Counter{
-					Count: int64(14),
-				},
-				AlertingResultStateNoData: Counter{
-					Count: int64(15),
-				},
-				AlertingResultStateOk: Counter{
-					Count: int64(16),
-				},
-				AlertingResultStatePaused: Counter{
-					Count: int64(17),
-				},
-				AlertingResultStatePending: Counter{
-					Count: int64(18),
-				},
-				APIAdminUserCreate: Counter{
-					Count: int64(19),
-				},
-				APIDashboardGet: Timer{
-					Count: int64(1),
-					Max:   int64(2),
-					Mean:  float64(1.1),
-					Min:   int64(3),
-					P25:   float64(2.2),
-					P75:   float64(3.3),
-					P90:   float64(3.4),
-					P99:   float64(3.5),
-					Std:   float64(3.6),
-				},
-				APIDashboardSave: Timer{
-					Count: int64(1),
-					Max:   int64(2),
-					Mean:  float64(1.1),
-					Min:   int64(3),
-					P25:   float64(2.2),
-					P75:   float64(3.3),
-					P90:   float64(3.4),
-					P99:   float64(3.5),
-					Std:   float64(3.6),
-				},
-				APIDashboardSearch: Timer{
-					Count: int64(1),
-					Max:   int64(2),
-					Mean:  float64(1.1),
-					Min:   int64(3),
-					P25:   float64(2.2),
-					P75:   float64(3.3),
-					P90:   float64(3.4),
-					P99:   float64(3.5),
-					Std:   float64(3.6),
-				},
-				APIDashboardSnapshotCreate: Counter{
-					Count: int64(20),
-				},
-				APIDashboardSnapshotExternal: Counter{
-					Count: int64(21),
-				},
-				APIDashboardSnapshotGet: Counter{
-					Count: int64(22),
-				},
-				APIDataproxyRequestAll: Timer{
-					Count: int64(1),
-					Max:   int64(2),
-					Mean:  float64(1.1),
-					Min:   int64(3),
-					P25:   float64(2.2),
-					P75:   float64(3.3),
-					P90:   float64(3.4),
-					P99:   float64(3.5),
-					Std:   float64(3.6),
-				},
-				APILoginOauth: Counter{
-					Count: int64(23),
-				},
-				APILoginPost: Counter{
-					Count: int64(24),
-				},
-				APIOrgCreate: Counter{
-					Count: int64(25),
-				},
-				APIRespStatusCode200: Counter{
-					Count: int64(26),
-				},
-				APIRespStatusCode404: Counter{
-					Count: int64(27),
-				},
-				APIRespStatusCode500: Counter{
-					Count: int64(28),
-				},
-				APIRespStatusCodeUnknown: Counter{
-					Count: int64(29),
-				},
-				APIUserSignupCompleted: Counter{
-					Count: int64(30),
+				"alerting.active_alerts": Metric{
+					Gauge: &Gauge{Value: float64(1)},
 				},
-				APIUserSignupInvite: Counter{
-					Count: int64(31),
+				"alerting.notifications_sent.type_email": Metric{
+					Counter: &Counter{Count: int64(2)},
 				},
-				APIUserSignupStarted: Counter{
-					Count: int64(32),
+				"alerting.execution_time": Metric{
+					Timer: &Timer{
+						Count: int64(3),
+						Max:   int64(4),
+						Mean:  float64(5.1),
+						Min:   int64(6),
+						P25:   float64(7.1),
+						P75:   float64(8.1),
+						P90:   float64(9.1),
+						P99:   float64(10.1),
+						Std:   float64(11.1),
+					},
 				},
-				AWSCloudwatchGetMetricStatistics: Counter{
-					Count: int64(33),
-				},
-				AWSCloudwatchListMetrics: Counter{
-					Count: int64(34),
-				},
-				InstanceStart: Counter{
-					Count: int64(35),
-				},
-				ModelsDashboardInsert: Counter{
-					Count: int64(36),
-				},
-				PageRespStatusCode200: Counter{
-					Count: int64(37),
-				},
-				PageRespStatusCode404: Counter{
-					Count: int64(38),
-				},
-				PageRespStatusCode500: Counter{
-					Count: int64(39),
-				},
-				PageRespStatusCodeUnknown: Counter{
-					Count: int64(40),
-				},
-				ProxyRespStatusCode200: Counter{
-					Count: int64(41),
-				},
-				ProxyRespStatusCode404: Counter{
-					Count: int64(42),
-				},
-				ProxyRespStatusCode500: Counter{
-					Count: int64(43),
-				},
-				ProxyRespStatusCodeUnknown: Counter{
-					Count: int64(44),
-				},
-				StatsTotalsStatDashboards: Gauge{
-					Value: int64(45),
-				},
-				StatsTotalsStatOrgs: Gauge{
-					Value: int64(46),
-				},
-				StatsTotalsStatPlaylists: Gauge{
-					Value: int64(47),
-				},
-				StatsTotalsStatUsers: Gauge{
-					Value: int64(48),
+				"api.login.post": Metric{
+					Counter: &Counter{Count: int64(0)},
 				},
 			}
 
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
 					ghttp.VerifyRequest("GET", "/api/metrics"),
-					ghttp.VerifyBasicAuth(username, password),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
 					ghttp.RespondWithJSONEncodedPtr(&statusCode, &metricsResponse),
 				),
 			)
 		})
 
 		JustBeforeEach(func() {
-			metrics, err = client.GetMetrics()
+			metrics, err = client.GetMetrics(context.Background())
 		})
 
 		It("returns the metrics", func() {
@@ -312,6 +547,24 @@ var _ = Describe("HTTPClient", func() {
 			Expect(metrics).To(Equal(metricsResponse))
 		})
 
+		Context("when the metrics have an unknown shape", func() {
+			BeforeEach(func() {
+				server.SetHandler(0, ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/metrics"),
+					ghttp.RespondWith(http.StatusOK, `{"api.login.post": {"count": 1}, "api.unknown": {"rate1": 0.5}, "version": "5.0.0"}`),
+				))
+			})
+
+			It("returns the metrics without their values", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metrics).To(Equal(Metrics{
+					"api.login.post": Metric{Counter: &Counter{Count: int64(1)}},
+					"api.unknown":    Metric{},
+					"version":        Metric{},
+				}))
+			})
+		})
+
 		Context("when it fails to get the metrics", func() {
 			BeforeEach(func() {
 				statusCode = http.StatusInternalServerError
@@ -323,4 +576,1467 @@ var _ = Describe("HTTPClient", func() {
 			})
 		})
 	})
-})
+
+	Describe("GetHealth", func() {
+		var (
+			statusCode     int
+			health         Health
+			healthResponse Health
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			healthResponse = Health{Commit: "fake-commit", Database: "ok", Version: "9.3.2"}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/health"),
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &healthResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			health, err = client.GetHealth(context.Background())
+		})
+
+		It("returns the health", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(health).To(Equal(healthResponse))
+		})
+
+		Context("when the database is failing", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusServiceUnavailable
+			})
+
+			It("returns a failing database", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(health.Database).To(Equal("failing"))
+			})
+		})
+
+		Context("when it fails to get the health", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting health, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetFrontendSettings", func() {
+		var (
+			statusCode               int
+			frontendSettings         FrontendSettings
+			frontendSettingsResponse FrontendSettings
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			frontendSettingsResponse = FrontendSettings{
+				BuildInfo:      BuildInfo{Version: "9.3.2", Commit: "fake-commit", Edition: "Enterprise"},
+				FeatureToggles: map[string]bool{"publicDashboards": true},
+				LicenseInfo:    LicenseInfo{HasLicense: true, Expiry: 1483326245, StateInfo: "Valid"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/frontend/settings"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &frontendSettingsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			frontendSettings, err = client.GetFrontendSettings(context.Background())
+		})
+
+		It("returns the frontend settings", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(frontendSettings).To(Equal(frontendSettingsResponse))
+		})
+
+		Context("when it fails to get the frontend settings", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting frontend settings, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetOrgs", func() {
+		var (
+			statusCode   int
+			orgs         []Org
+			orgsResponse []Org
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			orgsResponse = []Org{
+				{ID: 1, Name: "Main Org."},
+				{ID: 2, Name: "fake-org"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/orgs", "perpage=5000"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &orgsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			orgs, err = client.GetOrgs(context.Background())
+		})
+
+		It("returns the orgs", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orgs).To(Equal(orgsResponse))
+		})
+
+		Context("when it fails to get the orgs", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting orgs, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetUsers", func() {
+		var (
+			statusCode    int
+			users         []User
+			usersResponse []User
+			totalCount    int
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			usersResponse = []User{
+				{ID: 1, Name: "admin", Login: "admin", Email: "admin@localhost", IsAdmin: true, LastSeenAt: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+				{ID: 2, Login: "fake-user", IsDisabled: true, LastSeenAt: time.Date(2017, 2, 3, 4, 5, 6, 0, time.UTC), AuthLabels: []string{"LDAP"}},
+			}
+			totalCount = 2
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/users/search", "page=1&perpage=1000"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.Header.Get("X-Grafana-Org-Id")).To(BeEmpty())
+					},
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"totalCount": &totalCount, "users": &usersResponse, "page": 1, "perPage": 1000}),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			users, err = client.GetUsers(context.Background())
+		})
+
+		It("returns the users", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(users).To(Equal(usersResponse))
+		})
+
+		Context("when the users span more than one page", func() {
+			var (
+				secondPageResponse []User
+			)
+
+			BeforeEach(func() {
+				totalCount = 3
+				secondPageResponse = []User{{ID: 3, Login: "fake-user-3"}}
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/users/search", "page=2&perpage=1000"),
+						func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+						ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"totalCount": 3, "users": secondPageResponse, "page": 2, "perPage": 1000}),
+					),
+				)
+			})
+
+			It("returns the users of every page", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(users).To(HaveLen(3))
+				Expect(users[2]).To(Equal(secondPageResponse[0]))
+			})
+		})
+
+		Context("when it fails to get the users", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusForbidden
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting users, http status code: 403"))
+			})
+		})
+	})
+
+	Describe("GetDashboards", func() {
+		var (
+			statusCode         int
+			dashboards         []SearchHit
+			dashboardsResponse []SearchHit
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			dashboardsResponse = []SearchHit{
+				{ID: 1, UID: "fake-dashboard-uid", Title: "fake-dashboard", Type: "dash-db", Tags: []string{"fake-tag"}, FolderID: 3, FolderUID: "fake-folder-uid", FolderTitle: "fake-folder"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/search", "limit=5000&page=1&type=dash-db"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &dashboardsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			dashboards, err = client.GetDashboards(context.Background(), 2)
+		})
+
+		It("returns the dashboards of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dashboards).To(Equal(dashboardsResponse))
+		})
+
+		Context("when the dashboards span more than one page", func() {
+			var (
+				secondPageResponse []SearchHit
+			)
+
+			BeforeEach(func() {
+				dashboardsResponse = make([]SearchHit, 5000)
+				for i := range dashboardsResponse {
+					dashboardsResponse[i] = SearchHit{ID: int64(i + 1), Type: "dash-db"}
+				}
+				secondPageResponse = []SearchHit{{ID: 5001, Type: "dash-db"}}
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/search", "limit=5000&page=2&type=dash-db"),
+						ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+						func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+						ghttp.RespondWithJSONEncodedPtr(&statusCode, &secondPageResponse),
+					),
+				)
+			})
+
+			It("returns the dashboards of every page", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dashboards).To(HaveLen(5001))
+				Expect(dashboards[5000]).To(Equal(secondPageResponse[0]))
+			})
+		})
+
+		Context("when it fails to get the dashboards", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting dashboards, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetDashboard", func() {
+		var (
+			statusCode            int
+			dashboard             Dashboard
+			dashboardResponse     Dashboard
+			dashboardResponseBody interface{}
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			dashboardResponse = Dashboard{
+				Dashboard: DashboardModel{
+					UID:           "fake-dashboard-uid",
+					Title:         "fake-dashboard",
+					Tags:          []string{"fake-tag"},
+					SchemaVersion: 16,
+					Version:       3,
+					Panels: []Panel{
+						{ID: 1, Type: "timeseries", Title: "fake-panel", Datasource: &PanelDatasource{UID: "-- Mixed --", Type: "datasource"}, Targets: []PanelTarget{
+							{RefID: "A", Datasource: &PanelDatasource{UID: "fake-datasource-uid", Type: "prometheus"}},
+						}},
+						{ID: 2, Type: "row", Title: "fake-row", Panels: []Panel{
+							{ID: 3, LibraryPanel: &LibraryPanelRef{UID: "fake-library-panel-uid", Name: "fake-library-panel"}},
+						}},
+					},
+				},
+				Meta: DashboardMeta{
+					Slug:        "fake-dashboard",
+					FolderID:    3,
+					FolderUID:   "fake-folder-uid",
+					FolderTitle: "fake-folder",
+					Provisioned: true,
+					Created:     time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC),
+					Updated:     time.Date(2017, 2, 3, 4, 5, 6, 0, time.UTC),
+					CreatedBy:   "admin",
+					UpdatedBy:   "fake-user",
+					Version:     3,
+				},
+			}
+			dashboardResponseBody = dashboardResponse
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/dashboards/uid/fake-dashboard-uid"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &dashboardResponseBody),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			dashboard, err = client.GetDashboard(context.Background(), 2, "fake-dashboard-uid")
+		})
+
+		It("returns the dashboard", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dashboard).To(Equal(dashboardResponse))
+		})
+
+		Context("when the dashboard has rows and references datasources by name", func() {
+			BeforeEach(func() {
+				dashboardResponseBody = map[string]interface{}{
+					"dashboard": map[string]interface{}{
+						"uid":           "fake-dashboard-uid",
+						"schemaVersion": 14,
+						"rows": []interface{}{
+							map[string]interface{}{
+								"title": "fake-row",
+								"panels": []interface{}{
+									map[string]interface{}{"id": 1, "type": "graph", "datasource": "fake-datasource"},
+									map[string]interface{}{"id": 2, "type": "singlestat", "datasource": nil},
+								},
+							},
+						},
+					},
+				}
+			})
+
+			It("returns the dashboard rows", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dashboard.Dashboard.Rows).To(Equal([]DashboardRow{
+					{Title: "fake-row", Panels: []Panel{
+						{ID: 1, Type: "graph", Datasource: &PanelDatasource{Name: "fake-datasource"}},
+						{ID: 2, Type: "singlestat"},
+					}},
+				}))
+			})
+		})
+
+		Context("when it fails to get the dashboard", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusNotFound
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting dashboard, http status code: 404"))
+			})
+		})
+	})
+
+	Describe("GetDashboardVersions", func() {
+		var (
+			statusCode                int
+			dashboardVersions         DashboardVersions
+			dashboardVersionsResponse interface{}
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			dashboardVersionsResponse = DashboardVersions{
+				{ID: 12, ParentVersion: 2, Version: 3, Created: time.Date(2017, 2, 3, 4, 5, 6, 0, time.UTC), CreatedBy: "fake-user", Message: "fake-message"},
+				{ID: 11, ParentVersion: 1, Version: 2, Created: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), CreatedBy: "admin"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/dashboards/uid/fake-dashboard-uid/versions", "limit=2"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &dashboardVersionsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			dashboardVersions, err = client.GetDashboardVersions(context.Background(), 2, "fake-dashboard-uid", 2)
+		})
+
+		It("returns the dashboard versions", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dashboardVersions).To(Equal(dashboardVersionsResponse))
+		})
+
+		Context("when Grafana paginates the dashboard versions", func() {
+			var (
+				versions DashboardVersions
+			)
+
+			BeforeEach(func() {
+				versions = dashboardVersionsResponse.(DashboardVersions)
+				dashboardVersionsResponse = map[string]interface{}{
+					"continueToken": "fake-continue-token",
+					"versions":      versions,
+				}
+			})
+
+			It("returns the dashboard versions", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dashboardVersions).To(Equal(versions))
+			})
+		})
+
+		Context("when it fails to get the dashboard versions", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting dashboard versions, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetLibraryPanel", func() {
+		var (
+			statusCode           int
+			libraryPanel         LibraryPanel
+			libraryPanelResponse LibraryPanel
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			libraryPanelResponse = LibraryPanel{
+				UID:  "fake-library-panel-uid",
+				Name: "fake-library-panel",
+				Type: "stat",
+				Model: Panel{
+					Type:       "stat",
+					Title:      "fake-panel",
+					Datasource: &PanelDatasource{UID: "fake-datasource-uid", Type: "loki"},
+				},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/library-elements/fake-library-panel-uid"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"result": libraryPanelResponse}),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			libraryPanel, err = client.GetLibraryPanel(context.Background(), 2, "fake-library-panel-uid")
+		})
+
+		It("returns the library panel", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(libraryPanel).To(Equal(libraryPanelResponse))
+		})
+
+		Context("when it fails to get the library panel", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusNotFound
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting library panel, http status code: 404"))
+			})
+		})
+	})
+
+	Describe("GetFolders", func() {
+		var (
+			statusCode      int
+			folders         []Folder
+			foldersResponse []Folder
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			foldersResponse = []Folder{
+				{ID: 3, UID: "fake-folder-uid", Title: "fake-folder"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/folders", "limit=5000"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &foldersResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			folders, err = client.GetFolders(context.Background(), 2)
+		})
+
+		It("returns the folders of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(folders).To(Equal(foldersResponse))
+		})
+
+		Context("when it fails to get the folders", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting folders, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetFolderPermissions", func() {
+		var (
+			statusCode                int
+			folderPermissions         []FolderPermission
+			folderPermissionsResponse []FolderPermission
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			folderPermissionsResponse = []FolderPermission{
+				{UserID: 1, UserLogin: "admin", Permission: 4, PermissionName: "Admin"},
+				{TeamID: 2, Team: "fake-team", Permission: 2, PermissionName: "Edit"},
+				{Role: "Viewer", Permission: 1, PermissionName: "View"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/folders/fake-folder-uid/permissions"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &folderPermissionsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			folderPermissions, err = client.GetFolderPermissions(context.Background(), 2, "fake-folder-uid")
+		})
+
+		It("returns the folder permissions", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(folderPermissions).To(Equal(folderPermissionsResponse))
+		})
+
+		Context("when it fails to get the folder permissions", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusForbidden
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting folder permissions, http status code: 403"))
+			})
+		})
+	})
+
+	Describe("GetTeams", func() {
+		var (
+			statusCode    int
+			teams         []Team
+			teamsResponse []Team
+			totalCount    int
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			teamsResponse = []Team{
+				{ID: 1, OrgID: 2, Name: "fake-team", Email: "fake-team@example.com", MemberCount: 3},
+			}
+			totalCount = 1
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/teams/search", "page=1&perpage=1000"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"totalCount": &totalCount, "teams": &teamsResponse, "page": 1, "perPage": 1000}),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			teams, err = client.GetTeams(context.Background(), 2)
+		})
+
+		It("returns the teams of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(teams).To(Equal(teamsResponse))
+		})
+
+		Context("when the teams span more than one page", func() {
+			var (
+				secondPageResponse []Team
+			)
+
+			BeforeEach(func() {
+				totalCount = 2
+				secondPageResponse = []Team{{ID: 2, OrgID: 2, Name: "fake-other-team"}}
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/teams/search", "page=2&perpage=1000"),
+						ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+						func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+						ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"totalCount": 2, "teams": secondPageResponse, "page": 2, "perPage": 1000}),
+					),
+				)
+			})
+
+			It("returns the teams of every page", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(teams).To(HaveLen(2))
+				Expect(teams[1]).To(Equal(secondPageResponse[0]))
+			})
+		})
+
+		Context("when it fails to get the teams", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting teams, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetDatasources", func() {
+		var (
+			statusCode          int
+			datasources         []Datasource
+			datasourcesResponse []Datasource
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			datasourcesResponse = []Datasource{
+				{ID: 4, UID: "fake-datasource-uid", OrgID: 2, Name: "fake-datasource", Type: "prometheus", URL: "http://prometheus:9090", Access: "proxy", IsDefault: true},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/datasources"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &datasourcesResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			datasources, err = client.GetDatasources(context.Background(), 2)
+		})
+
+		It("returns the datasources of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(datasources).To(Equal(datasourcesResponse))
+		})
+
+		Context("when it fails to get the datasources", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting datasources, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("uids needing escaping", func() {
+		var (
+			statusCode int
+			uid        = "fake uid/1"
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+		})
+
+		verifyEscapedPath := func(escapedPath string, response interface{}) {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.URL.EscapedPath()).To(Equal(escapedPath))
+					},
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, response),
+				),
+			)
+		}
+
+		It("escapes the uid of GetDashboard once", func() {
+			verifyEscapedPath("/api/dashboards/uid/fake%20uid%2F1", &Dashboard{})
+			_, err = client.GetDashboard(context.Background(), 2, uid)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("escapes the uid of GetDashboardVersions once", func() {
+			verifyEscapedPath("/api/dashboards/uid/fake%20uid%2F1/versions", &[]DashboardVersion{})
+			_, err = client.GetDashboardVersions(context.Background(), 2, uid, 10)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("escapes the uid of GetLibraryPanel once", func() {
+			verifyEscapedPath("/api/library-elements/fake%20uid%2F1", &map[string]interface{}{"result": LibraryPanel{}})
+			_, err = client.GetLibraryPanel(context.Background(), 2, uid)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("escapes the uid of GetFolderPermissions once", func() {
+			verifyEscapedPath("/api/folders/fake%20uid%2F1/permissions", &[]FolderPermission{})
+			_, err = client.GetFolderPermissions(context.Background(), 2, uid)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("escapes the uid of GetDatasourceHealth once", func() {
+			verifyEscapedPath("/api/datasources/uid/fake%20uid%2F1/health", &DatasourceHealth{})
+			_, err = client.GetDatasourceHealth(context.Background(), 2, uid)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("when Grafana is served from a sub path", func() {
+			JustBeforeEach(func() {
+				client, err = NewHTTPClient(server.URL()+"/grafana/", clientConfig)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("escapes the uid once", func() {
+				verifyEscapedPath("/grafana/api/dashboards/uid/fake%20uid%2F1", &Dashboard{})
+				_, err = client.GetDashboard(context.Background(), 2, uid)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("GetDatasourceHealth", func() {
+		var (
+			statusCode               int
+			datasourceHealth         DatasourceHealth
+			datasourceHealthResponse DatasourceHealth
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			datasourceHealthResponse = DatasourceHealth{Status: "OK", Message: "Data source is working"}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/datasources/uid/fake-datasource-uid/health"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &datasourceHealthResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			datasourceHealth, err = client.GetDatasourceHealth(context.Background(), 2, "fake-datasource-uid")
+		})
+
+		It("returns the datasource health", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(datasourceHealth).To(Equal(datasourceHealthResponse))
+		})
+
+		Context("when the health check fails", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusBadRequest
+				datasourceHealthResponse = DatasourceHealth{Status: "ERROR", Message: "connection refused"}
+			})
+
+			It("returns an error status", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(datasourceHealth.Status).To(Equal("ERROR"))
+			})
+		})
+
+		Context("when it fails to get the datasource health", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting datasource health, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetOrgUsers", func() {
+		var (
+			statusCode       int
+			orgUsers         []OrgUser
+			orgUsersResponse []OrgUser
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			orgUsersResponse = []OrgUser{
+				{OrgID: 2, UserID: 5, Email: "fake-user@example.com", Login: "fake-user", Role: "Admin", LastSeenAt: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/orgs/2/users"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.Header.Get("X-Grafana-Org-Id")).To(BeEmpty())
+					},
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &orgUsersResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			orgUsers, err = client.GetOrgUsers(context.Background(), 2)
+		})
+
+		It("returns the org users of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(orgUsers).To(Equal(orgUsersResponse))
+		})
+
+		Context("when it fails to get the org users", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting org users, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetAlertRules", func() {
+		var (
+			statusCode         int
+			alertRules         []AlertRule
+			alertRulesResponse []AlertRule
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			alertRulesResponse = []AlertRule{
+				{ID: 6, UID: "fake-alert-rule-uid", OrgID: 2, FolderUID: "fake-folder-uid", RuleGroup: "fake-rule-group", Title: "fake-alert-rule"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/provisioning/alert-rules"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &alertRulesResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			alertRules, err = client.GetAlertRules(context.Background(), 2)
+		})
+
+		It("returns the alert rules of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alertRules).To(Equal(alertRulesResponse))
+		})
+
+		Context("when it fails to get the alert rules", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting alert rules, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetAlerts", func() {
+		var (
+			statusCode     int
+			alerts         []Alert
+			alertsResponse []Alert
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			alertsResponse = []Alert{
+				{ID: 7, DashboardID: 1, DashboardUID: "fake-dashboard-uid", PanelID: 8, Name: "fake-alert", State: "ok", NewStateDate: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), URL: "/d/fake-dashboard-uid/fake-dashboard"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/alerts"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &alertsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			alerts, err = client.GetAlerts(context.Background(), 2)
+		})
+
+		It("returns the alerts of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alerts).To(Equal(alertsResponse))
+		})
+
+		It("does not limit the alerts", func() {
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+			Expect(server.ReceivedRequests()[0].URL.Query()).ToNot(HaveKey("limit"))
+		})
+
+		Context("when it fails to get the alerts", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting alerts, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetRuleGroups", func() {
+		var (
+			statusCode         int
+			ruleGroups         []RuleGroup
+			ruleGroupsResponse []RuleGroup
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			ruleGroupsResponse = []RuleGroup{
+				{
+					Name:      "fake-rule-group",
+					File:      "Fake Folder",
+					FolderUID: "fake-folder",
+					Rules: []Rule{
+						{Name: "fake-rule", State: "firing", Health: "error", LastError: "fake-error", Type: "alerting"},
+					},
+				},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/prometheus/grafana/api/v1/rules"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{
+						"status": "success",
+						"data":   map[string]interface{}{"groups": ruleGroupsResponse},
+					}),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			ruleGroups, err = client.GetRuleGroups(context.Background(), 2)
+		})
+
+		It("returns the rule groups of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ruleGroups).To(Equal(ruleGroupsResponse))
+		})
+
+		Context("when it fails to get the rule groups", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting rule groups, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetAlertmanagerAlerts", func() {
+		var (
+			statusCode                 int
+			alertmanagerAlerts         []AlertmanagerAlert
+			alertmanagerAlertsResponse []AlertmanagerAlert
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			alertmanagerAlertsResponse = []AlertmanagerAlert{
+				{
+					Fingerprint: "fake-fingerprint",
+					Labels:      map[string]string{"alertname": "fake-rule", "severity": "critical"},
+					StartsAt:    time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC),
+					EndsAt:      time.Date(2017, 1, 2, 4, 4, 5, 0, time.UTC),
+					Status:      AlertStatus{State: "active"},
+				},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/alertmanager/grafana/api/v2/alerts"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &alertmanagerAlertsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			alertmanagerAlerts, err = client.GetAlertmanagerAlerts(context.Background(), 2)
+		})
+
+		It("returns the alertmanager alerts of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alertmanagerAlerts).To(Equal(alertmanagerAlertsResponse))
+		})
+
+		Context("when it fails to get the alertmanager alerts", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting alertmanager alerts, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetSilences", func() {
+		var (
+			statusCode       int
+			silences         []Silence
+			silencesResponse []Silence
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			silencesResponse = []Silence{
+				{
+					ID:       "fake-silence-id",
+					Comment:  "fake-comment",
+					StartsAt: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC),
+					EndsAt:   time.Date(2017, 1, 2, 4, 4, 5, 0, time.UTC),
+					Status:   SilenceStatus{State: "expired"},
+				},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/alertmanager/grafana/api/v2/silences"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &silencesResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			silences, err = client.GetSilences(context.Background(), 2)
+		})
+
+		It("returns the silences of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(silences).To(Equal(silencesResponse))
+		})
+
+		Context("when it fails to get the silences", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting silences, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetNotificationChannels", func() {
+		var (
+			statusCode                   int
+			notificationChannels         []NotificationChannel
+			notificationChannelsResponse []NotificationChannel
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			notificationChannelsResponse = []NotificationChannel{
+				{ID: 9, UID: "fake-notification-channel-uid", Name: "fake-notification-channel", Type: "slack", IsDefault: true, SendReminder: true},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/alert-notifications"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &notificationChannelsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			notificationChannels, err = client.GetNotificationChannels(context.Background(), 2)
+		})
+
+		It("returns the notification channels of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(notificationChannels).To(Equal(notificationChannelsResponse))
+		})
+
+		Context("when it fails to get the notification channels", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting notification channels, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetContactPoints", func() {
+		var (
+			statusCode            int
+			contactPoints         []ContactPoint
+			contactPointsResponse []ContactPoint
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			contactPointsResponse = []ContactPoint{
+				{UID: "fake-contact-point-uid", Name: "fake-contact-point", Type: "slack", DisableResolveMessage: true},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/provisioning/contact-points"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &contactPointsResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			contactPoints, err = client.GetContactPoints(context.Background(), 2)
+		})
+
+		It("returns the contact points of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contactPoints).To(Equal(contactPointsResponse))
+		})
+
+		Context("when it fails to get the contact points", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting contact points, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetReceivers", func() {
+		var (
+			statusCode        int
+			receivers         []Receiver
+			receiversResponse []Receiver
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			receiversResponse = []Receiver{
+				{
+					Name:   "fake-contact-point",
+					Active: true,
+					Integrations: []ReceiverIntegration{
+						{Name: "slack", LastNotifyAttempt: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), LastNotifyAttemptError: "fake-error", SendResolved: true},
+					},
+				},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/alertmanager/grafana/config/api/v1/receivers"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &receiversResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			receivers, err = client.GetReceivers(context.Background(), 2)
+		})
+
+		It("returns the receivers of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(receivers).To(Equal(receiversResponse))
+		})
+
+		Context("when it fails to get the receivers", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting receivers, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetAPIKeys", func() {
+		var (
+			statusCode      int
+			apiKeys         []APIKey
+			apiKeysResponse []APIKey
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			apiKeysResponse = []APIKey{
+				{ID: 9, Name: "fake-api-key", Role: "Viewer"},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/auth/keys", "includeExpired=true"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &apiKeysResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			apiKeys, err = client.GetAPIKeys(context.Background(), 2)
+		})
+
+		It("returns the api keys of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apiKeys).To(Equal(apiKeysResponse))
+		})
+
+		Context("when it fails to get the api keys", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting api keys, http status code: 500"))
+			})
+		})
+	})
+
+	Describe("GetServiceAccounts", func() {
+		var (
+			statusCode              int
+			serviceAccounts         []ServiceAccount
+			serviceAccountsResponse []ServiceAccount
+			totalCount              int
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			serviceAccountsResponse = []ServiceAccount{
+				{ID: 3, Name: "fake-service-account", Login: "sa-fake-service-account", Role: "Editor", Tokens: 2},
+			}
+			totalCount = 1
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/serviceaccounts/search", "page=1&perpage=1000"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"totalCount": &totalCount, "serviceAccounts": &serviceAccountsResponse, "page": 1, "perPage": 1000}),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			serviceAccounts, err = client.GetServiceAccounts(context.Background(), 2)
+		})
+
+		It("returns the service accounts of the org", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(serviceAccounts).To(Equal(serviceAccountsResponse))
+		})
+
+		Context("when the service accounts span more than one page", func() {
+			var (
+				secondPageResponse []ServiceAccount
+			)
+
+			BeforeEach(func() {
+				totalCount = 2
+				secondPageResponse = []ServiceAccount{{ID: 4, Name: "fake-service-account-4"}}
+
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("GET", "/api/serviceaccounts/search", "page=2&perpage=1000"),
+						ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+						func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+						ghttp.RespondWithJSONEncodedPtr(&statusCode, &map[string]interface{}{"totalCount": 2, "serviceAccounts": secondPageResponse, "page": 2, "perPage": 1000}),
+					),
+				)
+			})
+
+			It("returns the service accounts of every page", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(serviceAccounts).To(HaveLen(2))
+				Expect(serviceAccounts[1]).To(Equal(secondPageResponse[0]))
+			})
+		})
+
+		Context("when it fails to get the service accounts", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusNotFound
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting service accounts, http status code: 404"))
+			})
+		})
+	})
+
+	Describe("GetServiceAccountTokens", func() {
+		var (
+			statusCode                   int
+			serviceAccountTokens         []ServiceAccountToken
+			serviceAccountTokensResponse []ServiceAccountToken
+		)
+
+		BeforeEach(func() {
+			statusCode = http.StatusOK
+			expiration := time.Date(2017, 2, 3, 4, 5, 6, 0, time.UTC)
+			serviceAccountTokensResponse = []ServiceAccountToken{
+				{ID: 5, Name: "fake-token", Created: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), Expiration: &expiration, HasExpired: true},
+				{ID: 6, Name: "fake-non-expiring-token", Created: time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+			}
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/serviceaccounts/3/tokens"),
+					ghttp.VerifyHeaderKV("X-Grafana-Org-Id", "2"),
+					func(w http.ResponseWriter, r *http.Request) { authHandler(w, r) },
+					ghttp.RespondWithJSONEncodedPtr(&statusCode, &serviceAccountTokensResponse),
+				),
+			)
+		})
+
+		JustBeforeEach(func() {
+			serviceAccountTokens, err = client.GetServiceAccountTokens(context.Background(), 2, 3)
+		})
+
+		It("returns the tokens of the service account", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(serviceAccountTokens).To(Equal(serviceAccountTokensResponse))
+		})
+
+		Context("when it fails to get the service account tokens", func() {
+			BeforeEach(func() {
+				statusCode = http.StatusInternalServerError
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Error getting service account tokens, http status code: 500"))
+			})
+		})
+	})
+})
+
+// writeCertificate writes a self-signed certificate for the common name to the
+// certificate file, and its key to the key file unless it is empty.
+func writeCertificate(certFile string, keyFile string, commonName string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600); err != nil {
+		return err
+	}
+
+	if keyFile == "" {
+		return nil
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+}
+
+// touch moves the modification time of the file forward, so its rotation is
+// noticed even within the resolution of the file system timestamps.
+func touch(file string) error {
+	modTime := time.Now().Add(time.Hour)
+	return os.Chtimes(file, modTime, modTime)
+}
@@ -0,0 +1,57 @@
+package grafana
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretFile holds a secret read from a file. The file is checked on every
+// access and re-read whenever its modification time or size changes, so
+// rotated secrets (i.e. Kubernetes secrets mounted as volumes) are picked up
+// without restarting the exporter.
+type secretFile struct {
+	path    string
+	mtx     sync.Mutex
+	modTime time.Time
+	size    int64
+	secret  string
+}
+
+func newSecretFile(path string) (*secretFile, error) {
+	secretFile := &secretFile{path: path}
+	if _, err := secretFile.Get(); err != nil {
+		return nil, err
+	}
+
+	return secretFile, nil
+}
+
+func (s *secretFile) Get() (string, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	fileInfo, err := os.Stat(s.path)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error reading secret file `%s`: %s", s.path, err))
+	}
+
+	if fileInfo.ModTime().Equal(s.modTime) && fileInfo.Size() == s.size {
+		return s.secret, nil
+	}
+
+	content, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("Error reading secret file `%s`: %s", s.path, err))
+	}
+
+	s.secret = strings.TrimSpace(string(content))
+	s.modTime = fileInfo.ModTime()
+	s.size = fileInfo.Size()
+
+	return s.secret, nil
+}
@@ -0,0 +1,129 @@
+package grafana
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/log"
+)
+
+// tlsTransport is an http.RoundTripper following the CA file and the client
+// certificate files of the Grafana. The files are checked on every request and
+// the underlying transport is rebuilt whenever the modification time or size of
+// one of them changes, so rotated certificates (i.e. cert-manager certificates
+// mounted as volumes) are picked up without restarting the exporter.
+type tlsTransport struct {
+	config       HTTPClientConfig
+	newTransport func(tlsConfig *tls.Config) *http.Transport
+	mtx          sync.Mutex
+	files        []tlsFile
+	failedFiles  []tlsFile
+	transport    *http.Transport
+}
+
+// tlsFile holds the modification time and size of a TLS file when it was
+// loaded. A missing file is left zeroed, so it is loaded again as soon as it
+// shows up.
+type tlsFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+func newTLSTransport(config HTTPClientConfig, newTransport func(tlsConfig *tls.Config) *http.Transport) (*tlsTransport, error) {
+	tlsTransport := &tlsTransport{
+		config:       config,
+		newTransport: newTransport,
+	}
+	if _, err := tlsTransport.currentTransport(); err != nil {
+		return nil, err
+	}
+
+	return tlsTransport, nil
+}
+
+func (t *tlsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport, err := t.currentTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.RoundTrip(request)
+}
+
+func (t *tlsTransport) CloseIdleConnections() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.transport != nil {
+		t.transport.CloseIdleConnections()
+	}
+}
+
+// currentTransport returns the transport built from the current TLS files. A
+// failed rebuild (i.e. a certificate rotated before its key or a file still
+// being written) keeps the last transport built, and is only retried once the
+// files change again. Only the first build fails.
+func (t *tlsTransport) currentTransport() (*http.Transport, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	files := statTLSFiles(t.config.CAFile, t.config.CertFile, t.config.KeyFile)
+	if t.transport != nil && (sameTLSFiles(files, t.files) || sameTLSFiles(files, t.failedFiles)) {
+		return t.transport, nil
+	}
+
+	tlsConfig, err := newTLSConfig(t.config)
+	if err != nil {
+		if t.transport == nil {
+			return nil, err
+		}
+		log.Errorf("Error reloading the TLS files, keeping the previous ones until they change again: %s", err)
+		t.failedFiles = files
+		return t.transport, nil
+	}
+
+	if t.transport != nil {
+		t.transport.CloseIdleConnections()
+	}
+	t.transport = t.newTransport(tlsConfig)
+	t.files = files
+	t.failedFiles = nil
+
+	return t.transport, nil
+}
+
+func statTLSFiles(paths ...string) []tlsFile {
+	var files []tlsFile
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		file := tlsFile{path: path}
+		if fileInfo, err := os.Stat(path); err == nil {
+			file.modTime = fileInfo.ModTime()
+			file.size = fileInfo.Size()
+		}
+		files = append(files, file)
+	}
+
+	return files
+}
+
+func sameTLSFiles(a, b []tlsFile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].path != b[i].path || !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+
+	return true
+}
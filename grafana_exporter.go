@@ -5,17 +5,26 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/log"
 	"github.com/prometheus/common/version"
 
-	"github.com/frodenas/grafana_exporter/collectors"
+	"github.com/frodenas/grafana_exporter/config"
 	"github.com/frodenas/grafana_exporter/grafana"
 )
 
 var (
+	configFile = flag.String(
+		"config.file", "",
+		"Path to the configuration file ($GRAFANA_EXPORTER_CONFIG_FILE).",
+	)
+
 	grafanaURI = flag.String(
 		"grafana.uri", "",
 		"Grafana URI ($GRAFANA_EXPORTER_GRAFANA_URI).",
@@ -31,11 +40,101 @@ var (
 		"Grafana Password ($GRAFANA_EXPORTER_GRAFANA_PASSWORD).",
 	)
 
+	grafanaPasswordFile = flag.String(
+		"grafana.password-file", "",
+		"Path to a file containing the Grafana Password ($GRAFANA_EXPORTER_GRAFANA_PASSWORD_FILE).",
+	)
+
+	grafanaAPIToken = flag.String(
+		"grafana.api-token", "",
+		"Grafana API Token or Service Account Token ($GRAFANA_EXPORTER_GRAFANA_API_TOKEN).",
+	)
+
+	grafanaAPITokenFile = flag.String(
+		"grafana.api-token-file", "",
+		"Path to a file containing the Grafana API Token or Service Account Token ($GRAFANA_EXPORTER_GRAFANA_API_TOKEN_FILE).",
+	)
+
 	grafanaSkipSSLValidation = flag.Bool(
 		"grafana.skip-ssl-verify", false,
 		"Disable Grafana SSL Verify ($GRAFANA_EXPORTER_GRAFANA_SKIP_SSL_VERIFY).",
 	)
 
+	grafanaCAFile = flag.String(
+		"grafana.ca-file", "",
+		"Path to a CA certificate file to verify the Grafana server certificate ($GRAFANA_EXPORTER_GRAFANA_CA_FILE).",
+	)
+
+	grafanaCertFile = flag.String(
+		"grafana.cert-file", "",
+		"Path to a client certificate file to present to Grafana ($GRAFANA_EXPORTER_GRAFANA_CERT_FILE).",
+	)
+
+	grafanaKeyFile = flag.String(
+		"grafana.key-file", "",
+		"Path to the client certificate key file ($GRAFANA_EXPORTER_GRAFANA_KEY_FILE).",
+	)
+
+	grafanaServerName = flag.String(
+		"grafana.server-name", "",
+		"Server name used to verify the Grafana server certificate ($GRAFANA_EXPORTER_GRAFANA_SERVER_NAME).",
+	)
+
+	grafanaTimeout = flag.Duration(
+		"grafana.timeout", grafana.DefaultTimeout,
+		"Timeout for requests to Grafana ($GRAFANA_EXPORTER_GRAFANA_TIMEOUT).",
+	)
+
+	collectorsEnabled = flag.String(
+		"collectors.enabled", strings.Join(config.DefaultCollectors, ","),
+		"Comma separated list of collectors to enable ("+strings.Join(config.AvailableCollectors, ", ")+") ($GRAFANA_EXPORTER_COLLECTORS_ENABLED).",
+	)
+
+	apiKeysExpiryWindow = flag.Duration(
+		"api-keys.expiry-window", config.DefaultAPIKeysExpiryWindow,
+		"Window the API keys and service account tokens are reported as expiring within by the api_keys collector ($GRAFANA_EXPORTER_API_KEYS_EXPIRY_WINDOW).",
+	)
+
+	dashboardsInfoLimit = flag.Int(
+		"dashboards.info-limit", config.DefaultDashboardsInfoLimit,
+		"Maximum number of dashboards exported as per dashboard series by the dashboards and dashboard_activity collectors ($GRAFANA_EXPORTER_DASHBOARDS_INFO_LIMIT).",
+	)
+
+	datasourcesHealthCheckInterval = flag.Duration(
+		"datasources.health-check-interval", 0,
+		"Interval between the datasources health checks run by the datasources collector, disabled if 0 ($GRAFANA_EXPORTER_DATASOURCES_HEALTH_CHECK_INTERVAL).",
+	)
+
+	panelsDenyTypes = flag.String(
+		"panels.deny-types", "",
+		"Comma separated list of panel types whose dashboards are reported by the panels collector ($GRAFANA_EXPORTER_PANELS_DENY_TYPES).",
+	)
+
+	teamsFolderPermissions = flag.Bool(
+		"teams.folder-permissions", false,
+		"Count the grants of the folders permissions by the teams collector ($GRAFANA_EXPORTER_TEAMS_FOLDER_PERMISSIONS).",
+	)
+
+	usersLastSeenAge = flag.Bool(
+		"users.last-seen-age", false,
+		"Export the number of seconds since every user was last seen, with its login as a label, by the users collector ($GRAFANA_EXPORTER_USERS_LAST_SEEN_AGE).",
+	)
+
+	orgsInclude = flag.String(
+		"orgs.include", "",
+		"Comma separated list of org ids or names to scrape by the per org collectors, all orgs if empty ($GRAFANA_EXPORTER_ORGS_INCLUDE).",
+	)
+
+	orgsExclude = flag.String(
+		"orgs.exclude", "",
+		"Comma separated list of org ids or names not to scrape by the per org collectors ($GRAFANA_EXPORTER_ORGS_EXCLUDE).",
+	)
+
+	legacyMetricNames = flag.Bool(
+		"compat.legacy-metric-names", false,
+		"Also export the Grafana counters and timers as gauges under their legacy names, without the `_total` and `_seconds` suffixes ($GRAFANA_EXPORTER_COMPAT_LEGACY_METRIC_NAMES).",
+	)
+
 	listenAddress = flag.String(
 		"web.listen-address", ":9261",
 		"Address to listen on for web interface and telemetry ($GRAFANA_EXPORTER_WEB_LISTEN_ADDRESS).",
@@ -46,6 +145,11 @@ var (
 		"Path under which to expose Prometheus metrics ($GRAFANA_EXPORTER_WEB_TELEMETRY_PATH).",
 	)
 
+	timeoutOffset = flag.Duration(
+		"web.timeout-offset", 500*time.Millisecond,
+		"Offset to subtract from the Prometheus scrape timeout ($GRAFANA_EXPORTER_WEB_TIMEOUT_OFFSET).",
+	)
+
 	showVersion = flag.Bool(
 		"version", false,
 		"Print version information.",
@@ -54,15 +158,37 @@ var (
 
 func init() {
 	prometheus.MustRegister(version.NewCollector("grafana_exporter"))
+	prometheus.MustRegister(configLastReloadSuccessfulMetric)
+	prometheus.MustRegister(configLastReloadSuccessTimestampMetric)
 }
 
 func overrideFlagsWithEnvVars() {
+	overrideWithEnvVar("GRAFANA_EXPORTER_CONFIG_FILE", configFile)
 	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_URI", grafanaURI)
 	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_USERNAME", grafanaUsername)
 	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_PASSWORD", grafanaPassword)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_PASSWORD_FILE", grafanaPasswordFile)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_API_TOKEN", grafanaAPIToken)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_API_TOKEN_FILE", grafanaAPITokenFile)
 	overrideWithEnvBool("GRAFANA_EXPORTER_GRAFANA_SKIP_SSL_VERIFY", grafanaSkipSSLValidation)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_CA_FILE", grafanaCAFile)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_CERT_FILE", grafanaCertFile)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_KEY_FILE", grafanaKeyFile)
+	overrideWithEnvVar("GRAFANA_EXPORTER_GRAFANA_SERVER_NAME", grafanaServerName)
+	overrideWithEnvDuration("GRAFANA_EXPORTER_GRAFANA_TIMEOUT", grafanaTimeout)
+	overrideWithEnvVar("GRAFANA_EXPORTER_COLLECTORS_ENABLED", collectorsEnabled)
+	overrideWithEnvDuration("GRAFANA_EXPORTER_API_KEYS_EXPIRY_WINDOW", apiKeysExpiryWindow)
+	overrideWithEnvInt("GRAFANA_EXPORTER_DASHBOARDS_INFO_LIMIT", dashboardsInfoLimit)
+	overrideWithEnvDuration("GRAFANA_EXPORTER_DATASOURCES_HEALTH_CHECK_INTERVAL", datasourcesHealthCheckInterval)
+	overrideWithEnvVar("GRAFANA_EXPORTER_PANELS_DENY_TYPES", panelsDenyTypes)
+	overrideWithEnvBool("GRAFANA_EXPORTER_TEAMS_FOLDER_PERMISSIONS", teamsFolderPermissions)
+	overrideWithEnvBool("GRAFANA_EXPORTER_USERS_LAST_SEEN_AGE", usersLastSeenAge)
+	overrideWithEnvVar("GRAFANA_EXPORTER_ORGS_INCLUDE", orgsInclude)
+	overrideWithEnvVar("GRAFANA_EXPORTER_ORGS_EXCLUDE", orgsExclude)
+	overrideWithEnvBool("GRAFANA_EXPORTER_COMPAT_LEGACY_METRIC_NAMES", legacyMetricNames)
 	overrideWithEnvVar("GRAFANA_EXPORTER_WEB_LISTEN_ADDRESS", listenAddress)
 	overrideWithEnvVar("GRAFANA_EXPORTER_WEB_TELEMETRY_PATH", metricsPath)
+	overrideWithEnvDuration("GRAFANA_EXPORTER_WEB_TIMEOUT_OFFSET", timeoutOffset)
 }
 
 func overrideWithEnvVar(name string, value *string) {
@@ -83,6 +209,97 @@ func overrideWithEnvBool(name string, value *bool) {
 	}
 }
 
+func overrideWithEnvInt(name string, value *int) {
+	envValue := os.Getenv(name)
+	if envValue != "" {
+		var err error
+		*value, err = strconv.Atoi(envValue)
+		if err != nil {
+			log.Fatalf("Invalid `%s`: %s", name, err)
+		}
+	}
+}
+
+func overrideWithEnvDuration(name string, value *time.Duration) {
+	envValue := os.Getenv(name)
+	if envValue != "" {
+		var err error
+		*value, err = time.ParseDuration(envValue)
+		if err != nil {
+			log.Fatalf("Invalid `%s`: %s", name, err)
+		}
+	}
+}
+
+func loadConfig() (*config.Config, error) {
+	exporterConfig := &config.Config{}
+	if *configFile != "" {
+		var err error
+		exporterConfig, err = config.LoadFile(*configFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if *grafanaURI != "" {
+		exporterConfig.Grafanas = append(exporterConfig.Grafanas, config.Grafana{
+			URI: *grafanaURI,
+			ScrapeConfig: config.ScrapeConfig{
+				HTTPClientConfig: grafana.HTTPClientConfig{
+					Username:      *grafanaUsername,
+					Password:      *grafanaPassword,
+					PasswordFile:  *grafanaPasswordFile,
+					APIToken:      *grafanaAPIToken,
+					APITokenFile:  *grafanaAPITokenFile,
+					SkipSSLVerify: *grafanaSkipSSLValidation,
+					CAFile:        *grafanaCAFile,
+					CertFile:      *grafanaCertFile,
+					KeyFile:       *grafanaKeyFile,
+					ServerName:    *grafanaServerName,
+					Timeout:       *grafanaTimeout,
+				},
+				Collectors: strings.Split(*collectorsEnabled, ","),
+				APIKeys: config.APIKeys{
+					ExpiryWindow: *apiKeysExpiryWindow,
+				},
+				Dashboards: config.Dashboards{
+					InfoLimit: *dashboardsInfoLimit,
+				},
+				Datasources: config.Datasources{
+					HealthCheckInterval: *datasourcesHealthCheckInterval,
+				},
+				Orgs: config.Orgs{
+					Include: splitList(*orgsInclude),
+					Exclude: splitList(*orgsExclude),
+				},
+				Panels: config.Panels{
+					DenyTypes: splitList(*panelsDenyTypes),
+				},
+				Teams: config.Teams{
+					FolderPermissions: *teamsFolderPermissions,
+				},
+				Users: config.Users{
+					LastSeenAge: *usersLastSeenAge,
+				},
+			},
+		})
+
+		if err := exporterConfig.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return exporterConfig, nil
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	return strings.Split(list, ",")
+}
+
 func main() {
 	flag.Parse()
 	overrideFlagsWithEnvVars()
@@ -92,35 +309,58 @@ func main() {
 		os.Exit(0)
 	}
 
-	if *grafanaURI == "" {
-		log.Error("Flag `grafana.uri` is required")
+	if *grafanaURI == "" && *configFile == "" {
+		log.Error("Flag `grafana.uri` or `config.file` is required")
 		os.Exit(1)
 	}
 
 	log.Infoln("Starting grafana_exporter", version.Info())
 	log.Infoln("Build context", version.BuildContext())
 
-	grafanaClient, err := grafana.NewHTTPClient(*grafanaURI, *grafanaUsername, *grafanaPassword, *grafanaSkipSSLValidation)
-	if err != nil {
+	grafanaExporter := newExporter(loadConfig)
+	if err := grafanaExporter.Reload(); err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
 
-	adminStatsCollector := collectors.NewAdminStatsCollector(grafanaClient)
-	prometheus.MustRegister(adminStatsCollector)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := grafanaExporter.Reload(); err != nil {
+				log.Errorf("Error reloading config: %s", err)
+				continue
+			}
+			log.Infoln("Reloaded config")
+		}
+	}()
 
-	metricsCollector := collectors.NewMetricsCollector(grafanaClient)
-	prometheus.MustRegister(metricsCollector)
+	http.Handle(*metricsPath, prometheus.InstrumentHandlerFunc("prometheus", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := scrapeContext(r, *timeoutOffset)
+		defer cancel()
 
-	http.Handle(*metricsPath, prometheus.Handler())
+		handlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, grafanaExporter.Gatherer(ctx)}).ServeHTTP(w, r)
+	}))
+	grafanaProber := newProber()
+	http.HandleFunc("/probe", func(w http.ResponseWriter, r *http.Request) {
+		grafanaProber.probeHandler(w, r, grafanaExporter.Config(), *timeoutOffset)
+	})
+	http.HandleFunc("/-/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "This endpoint requires a POST request", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := grafanaExporter.Reload(); err != nil {
+			log.Errorf("Error reloading config: %s", err)
+			http.Error(w, fmt.Sprintf("Error reloading config: %s", err), http.StatusInternalServerError)
+			return
+		}
+		log.Infoln("Reloaded config")
+	})
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(`<html>
-             <head><title>Grafana Exporter</title></head>
-             <body>
-             <h1>Grafana Exporter</h1>
-             <p><a href='` + *metricsPath + `'>Metrics</a></p>
-             </body>
-             </html>`))
+		w.Write(landingPage(*metricsPath, grafanaExporter.Config()))
 	})
 
 	log.Infoln("Listening on", *listenAddress)
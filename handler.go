@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/config"
+)
+
+const scrapeTimeoutHeader = "X-Prometheus-Scrape-Timeout-Seconds"
+
+// scrapeContext returns a context that is cancelled when the scrape request
+// is done, with a deadline derived from the Prometheus scrape timeout minus
+// the timeout offset, so the requests to Grafana are not left running once
+// Prometheus gives up on the scrape. When the offset is not shorter than the
+// scrape timeout, the requests are given up halfway through the scrape
+// timeout instead.
+func scrapeContext(r *http.Request, timeoutOffset time.Duration) (context.Context, context.CancelFunc) {
+	if value := r.Header.Get(scrapeTimeoutHeader); value != "" {
+		timeoutSeconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Warnf("Invalid `%s` header `%s`: %s", scrapeTimeoutHeader, value, err)
+		} else if scrapeTimeout := time.Duration(timeoutSeconds * float64(time.Second)); scrapeTimeout > 0 {
+			timeout := scrapeTimeout - timeoutOffset
+			if timeout <= 0 {
+				timeout = scrapeTimeout / 2
+				log.Warnf("Timeout offset `%s` is not shorter than the scrape timeout `%s`, using a `%s` timeout", timeoutOffset, scrapeTimeout, timeout)
+			}
+			return context.WithTimeout(r.Context(), timeout)
+		}
+	}
+
+	return context.WithCancel(r.Context())
+}
+
+func handlerFor(gatherer prometheus.Gatherer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metricFamilies, err := gatherer.Gather()
+		if err != nil {
+			http.Error(w, "An error has occurred during metrics collection:\n\n"+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var buf bytes.Buffer
+		contentType := expfmt.Negotiate(r.Header)
+		encoder := expfmt.NewEncoder(&buf, contentType)
+		for _, metricFamily := range metricFamilies {
+			if err := encoder.Encode(metricFamily); err != nil {
+				http.Error(w, "An error has occurred during metrics encoding:\n\n"+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", string(contentType))
+		w.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+		w.Write(buf.Bytes())
+	})
+}
+
+// landingPage returns the exporter home page, linking to the metrics and
+// holding a probe form for every module, as the targets a module may probe
+// are only known as regular expressions.
+func landingPage(metricsPath string, exporterConfig *config.Config) []byte {
+	var moduleNames []string
+	for moduleName := range exporterConfig.Modules {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	var buf bytes.Buffer
+	buf.WriteString(`<html>
+             <head><title>Grafana Exporter</title></head>
+             <body>
+             <h1>Grafana Exporter</h1>
+             <p><a href='` + html.EscapeString(metricsPath) + `'>Metrics</a></p>
+`)
+	for _, moduleName := range moduleNames {
+		buf.WriteString(`             <form action='/probe'>
+             <input type='hidden' name='module' value='` + html.EscapeString(moduleName) + `'>
+             <input type='text' name='target' placeholder='https://grafana.example.com'>
+             <input type='submit' value='Probe with module ` + html.EscapeString(moduleName) + `'>
+             </form>
+`)
+	}
+	buf.WriteString(`             </body>
+             </html>`)
+
+	return buf.Bytes()
+}
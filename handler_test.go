@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/frodenas/grafana_exporter/config"
+)
+
+var _ = Describe("landingPage", func() {
+	var exporterConfig *config.Config
+
+	BeforeEach(func() {
+		exporterConfig = &config.Config{}
+	})
+
+	It("links to the metrics", func() {
+		Expect(string(landingPage("/metrics", exporterConfig))).To(ContainSubstring("<a href='/metrics'>Metrics</a>"))
+	})
+
+	It("does not hold any probe form", func() {
+		Expect(string(landingPage("/metrics", exporterConfig))).ToNot(ContainSubstring("/probe"))
+	})
+
+	Context("when modules are configured", func() {
+		BeforeEach(func() {
+			exporterConfig.Modules = map[string]config.Module{"insecure": {}, "default": {}}
+		})
+
+		It("holds a probe form for every module", func() {
+			page := string(landingPage("/metrics", exporterConfig))
+			Expect(page).To(MatchRegexp(`(?s)name='module' value='default'.*name='module' value='insecure'`))
+			Expect(page).To(ContainSubstring("<form action='/probe'>"))
+		})
+	})
+})
+
+var _ = Describe("scrapeContext", func() {
+	var (
+		request       *http.Request
+		timeoutOffset time.Duration
+		ctx           context.Context
+		cancel        context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		request = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		timeoutOffset = 500 * time.Millisecond
+	})
+
+	JustBeforeEach(func() {
+		ctx, cancel = scrapeContext(request, timeoutOffset)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("does not set a deadline", func() {
+		_, ok := ctx.Deadline()
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("when the scrape request is done", func() {
+		var requestCancel context.CancelFunc
+
+		BeforeEach(func() {
+			var requestCtx context.Context
+			requestCtx, requestCancel = context.WithCancel(context.Background())
+			request = request.WithContext(requestCtx)
+		})
+
+		It("is cancelled", func() {
+			requestCancel()
+			Eventually(ctx.Done()).Should(BeClosed())
+		})
+	})
+
+	Context("when Prometheus sends the scrape timeout", func() {
+		BeforeEach(func() {
+			request.Header.Set(scrapeTimeoutHeader, "10")
+		})
+
+		It("sets a deadline the timeout offset before the scrape timeout", func() {
+			deadline, ok := ctx.Deadline()
+			Expect(ok).To(BeTrue())
+			Expect(deadline).To(BeTemporally("~", time.Now().Add(9500*time.Millisecond), time.Second))
+		})
+
+		Context("when the timeout offset is not shorter than the scrape timeout", func() {
+			BeforeEach(func() {
+				timeoutOffset = 10 * time.Second
+			})
+
+			It("sets a deadline halfway through the scrape timeout", func() {
+				deadline, ok := ctx.Deadline()
+				Expect(ok).To(BeTrue())
+				Expect(deadline).To(BeTemporally("~", time.Now().Add(5*time.Second), time.Second))
+			})
+		})
+	})
+
+	Context("when the scrape timeout is invalid", func() {
+		BeforeEach(func() {
+			request.Header.Set(scrapeTimeoutHeader, "fake-timeout")
+		})
+
+		It("does not set a deadline", func() {
+			_, ok := ctx.Deadline()
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+
+	"github.com/frodenas/grafana_exporter/collectors"
+	"github.com/frodenas/grafana_exporter/config"
+	"github.com/frodenas/grafana_exporter/grafana"
+)
+
+const defaultModule = "default"
+
+// probeIdleTimeout is the time after which the collectors of a target that is
+// no longer probed are forgotten.
+const probeIdleTimeout = time.Hour
+
+type probeKey struct {
+	module string
+	target string
+}
+
+type probeInstance struct {
+	scrapeConfig config.ScrapeConfig
+	client       *grafana.HTTPClient
+	collectors   []collectors.ContextCollector
+	lastProbe    time.Time
+}
+
+// prober holds the collectors of the probed targets. The collectors of a
+// target are kept between probes, so its connections, its detected version
+// and its counters are reused, and rebuilt when the settings of its module
+// change.
+type prober struct {
+	mtx       sync.Mutex
+	instances map[probeKey]*probeInstance
+}
+
+func newProber() *prober {
+	return &prober{
+		instances: map[probeKey]*probeInstance{},
+	}
+}
+
+func (p *prober) probeHandler(w http.ResponseWriter, r *http.Request, exporterConfig *config.Config, timeoutOffset time.Duration) {
+	params := r.URL.Query()
+
+	target := params.Get("target")
+	if target == "" {
+		http.Error(w, "Target parameter is missing", http.StatusBadRequest)
+		return
+	}
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+
+	moduleName := params.Get("module")
+	if moduleName == "" {
+		moduleName = defaultModule
+	}
+	module, ok := exporterConfig.Modules[moduleName]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown module `%s`", moduleName), http.StatusBadRequest)
+		return
+	}
+
+	// Never send the module credentials to a target the module was not
+	// configured for.
+	if !module.AllowsTarget(target) {
+		log.Warnf("Refusing to probe target `%s` not allowed by module `%s`", target, moduleName)
+		http.Error(w, fmt.Sprintf("Target `%s` is not allowed by module `%s`", target, moduleName), http.StatusForbidden)
+		return
+	}
+
+	instance, err := p.instance(probeKey{module: moduleName, target: target}, module.ScrapeConfig)
+	if err != nil {
+		log.Errorf("Error creating Grafana client for target `%s`: %s", target, err)
+		http.Error(w, fmt.Sprintf("Invalid target `%s`: %s", target, err), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := scrapeContext(r, timeoutOffset)
+	defer cancel()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(withContext(ctx, instance.collectors)...)
+
+	handlerFor(registry).ServeHTTP(w, r)
+}
+
+// instance returns the instance probing the target with the module scrape
+// settings, and forgets the instances that were not probed for
+// probeIdleTimeout.
+func (p *prober) instance(key probeKey, scrapeConfig config.ScrapeConfig) (*probeInstance, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	now := time.Now()
+	for instanceKey, instance := range p.instances {
+		if now.Sub(instance.lastProbe) > probeIdleTimeout {
+			instance.client.CloseIdleConnections()
+			delete(p.instances, instanceKey)
+		}
+	}
+
+	instance, ok := p.instances[key]
+	if !ok || !reflect.DeepEqual(instance.scrapeConfig, scrapeConfig) {
+		grafanaClient, err := grafana.NewHTTPClient(key.target, scrapeConfig.HTTPClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			instance.client.CloseIdleConnections()
+		}
+
+		instance = &probeInstance{
+			scrapeConfig: scrapeConfig,
+			client:       grafanaClient,
+			collectors:   newCollectors(grafanaClient, scrapeConfig, nil),
+		}
+		p.instances[key] = instance
+	}
+	instance.lastProbe = now
+
+	return instance, nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/frodenas/grafana_exporter/config"
+)
+
+var _ = Describe("probeHandler", func() {
+	var (
+		server         *ghttp.Server
+		exporterConfig *config.Config
+		target         string
+		module         string
+		grafanaProber  *prober
+		response       *httptest.ResponseRecorder
+
+		username = "fake-username"
+		password = "fake-password"
+	)
+
+	probe := func() *httptest.ResponseRecorder {
+		params := url.Values{}
+		if target != "" {
+			params.Set("target", target)
+		}
+		if module != "" {
+			params.Set("module", module)
+		}
+
+		request := httptest.NewRequest(http.MethodGet, "/probe?"+params.Encode(), nil)
+		response := httptest.NewRecorder()
+		grafanaProber.probeHandler(response, request, exporterConfig, 500*time.Millisecond)
+
+		return response
+	}
+
+	healthRequests := func() int {
+		count := 0
+		for _, request := range server.ReceivedRequests() {
+			if request.URL.Path == "/api/health" {
+				count++
+			}
+		}
+		return count
+	}
+
+	BeforeEach(func() {
+		grafanaProber = newProber()
+		server = ghttp.NewServer()
+		server.RouteToHandler("GET", "/api/health", ghttp.CombineHandlers(
+			ghttp.VerifyBasicAuth(username, password),
+			ghttp.RespondWith(http.StatusOK, `{"database": "ok", "version": "4.6.3"}`),
+		))
+		server.RouteToHandler("GET", "/api/admin/stats", ghttp.CombineHandlers(
+			ghttp.VerifyBasicAuth(username, password),
+			ghttp.RespondWith(http.StatusOK, `{"dashboard_count": 4}`),
+		))
+		server.RouteToHandler("GET", "/api/metrics", ghttp.CombineHandlers(
+			ghttp.VerifyBasicAuth(username, password),
+			ghttp.RespondWith(http.StatusOK, `{"stat_totals.stat_dashboards": {"value": 4}}`),
+		))
+
+		var err error
+		exporterConfig, err = config.Load(`
+modules:
+  default:
+    username: ` + username + `
+    password: ` + password + `
+    targets:
+      - ` + regexp.QuoteMeta(server.URL()) + `
+`)
+		Expect(err).ToNot(HaveOccurred())
+
+		target = server.URL()
+		module = ""
+	})
+
+	JustBeforeEach(func() {
+		response = probe()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the target metrics", func() {
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Body.String()).To(ContainSubstring("grafana_admin_stats_dashboards 4"))
+		Expect(response.Body.String()).To(ContainSubstring("grafana_metrics_dashboards 4"))
+	})
+
+	It("keeps the collectors of the target between probes", func() {
+		Expect(probe().Body.String()).To(ContainSubstring("grafana_admin_stats_scrapes_total 2"))
+		Expect(healthRequests()).To(Equal(1))
+	})
+
+	Context("when the module settings change between probes", func() {
+		It("rebuilds the collectors of the target", func() {
+			defaultModule := exporterConfig.Modules["default"]
+			defaultModule.Collectors = []string{"admin_stats"}
+			exporterConfig.Modules["default"] = defaultModule
+
+			body := probe().Body.String()
+			Expect(body).To(ContainSubstring("grafana_admin_stats_scrapes_total 1"))
+			Expect(body).ToNot(ContainSubstring("grafana_metrics_dashboards"))
+		})
+	})
+
+	Context("when the Grafana version dropped the JSON metrics", func() {
+		BeforeEach(func() {
+			server.RouteToHandler("GET", "/api/health", ghttp.RespondWith(http.StatusOK, `{"database": "ok", "version": "10.4.2"}`))
+			server.RouteToHandler("GET", "/api/metrics", ghttp.RespondWith(http.StatusNotFound, `{"message": "Not found"}`))
+		})
+
+		It("disables the metrics collector", func() {
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Body.String()).To(ContainSubstring(`grafana_exporter_collector_enabled{collector="metrics"} 0`))
+			Expect(response.Body.String()).ToNot(ContainSubstring("grafana_metrics_last_scrape_error"))
+		})
+
+		It("does not request the JSON metrics", func() {
+			for _, request := range server.ReceivedRequests() {
+				Expect(request.URL.Path).ToNot(Equal("/api/metrics"))
+			}
+		})
+	})
+
+	Context("when the target is missing", func() {
+		BeforeEach(func() {
+			target = ""
+		})
+
+		It("returns a bad request error", func() {
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+			Expect(response.Body.String()).To(ContainSubstring("Target parameter is missing"))
+		})
+	})
+
+	Context("when the module is unknown", func() {
+		BeforeEach(func() {
+			module = "unknown"
+		})
+
+		It("returns a bad request error", func() {
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+			Expect(response.Body.String()).To(ContainSubstring("Unknown module `unknown`"))
+		})
+
+		It("does not probe the target", func() {
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Context("when the target is not allowed by the module", func() {
+		var otherServer *ghttp.Server
+
+		BeforeEach(func() {
+			otherServer = ghttp.NewServer()
+			target = otherServer.URL()
+		})
+
+		AfterEach(func() {
+			otherServer.Close()
+		})
+
+		It("returns a forbidden error", func() {
+			Expect(response.Code).To(Equal(http.StatusForbidden))
+			Expect(response.Body.String()).To(ContainSubstring("is not allowed by module `default`"))
+		})
+
+		It("does not send the module credentials to the target", func() {
+			Expect(otherServer.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Context("when the target has no scheme", func() {
+		BeforeEach(func() {
+			parsedURL, err := url.Parse(server.URL())
+			Expect(err).ToNot(HaveOccurred())
+			target = parsedURL.Host
+		})
+
+		It("probes the target over http", func() {
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(server.ReceivedRequests()).ToNot(BeEmpty())
+		})
+	})
+
+	Context("when the target is invalid", func() {
+		BeforeEach(func() {
+			exporterConfig.Modules["default"] = config.Module{Targets: []string{".*"}}
+			Expect(exporterConfig.Validate()).To(Succeed())
+			target = "http://[::1"
+		})
+
+		It("returns a bad request error", func() {
+			Expect(response.Code).To(Equal(http.StatusBadRequest))
+			Expect(response.Body.String()).To(ContainSubstring("Invalid target `http://[::1`"))
+		})
+	})
+})